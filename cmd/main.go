@@ -36,6 +36,7 @@ import (
 	secretsreflector "github.com/mesosphere/dklb/pkg/secrets_reflector"
 	"github.com/mesosphere/dklb/pkg/signals"
 	translatorapi "github.com/mesosphere/dklb/pkg/translator/api"
+	kubernetesutil "github.com/mesosphere/dklb/pkg/util/kubernetes"
 	"github.com/mesosphere/dklb/pkg/version"
 )
 
@@ -84,6 +85,7 @@ func init() {
 	flag.StringVar(&admissionTLSCaBundle, admissionTLSCaBundleFlagName, "", "the base64-encoded ca bundle to use for registering the admission webhook")
 	flag.StringVar(&admissionTLSCertFile, admissionTLSCertFileFlagName, "", "the path to the file containing the certificate to use for serving the admission webhook")
 	flag.StringVar(&admissionTLSPrivateKeyFile, admissionTLSPrivateKeyFlagName, "", "the path to the file containing the private key to use for serving the admission webhook")
+	flag.DurationVar(&controllers.EndpointsBatchPeriod, "endpoints-batch-period", constants.DefaultEndpointsBatchPeriod, "the amount of time during which changes to endpoints are batched before the services/ingresses routed directly to pod ips are synced")
 	flag.StringVar(&edgelbOptions.BearerToken, "edgelb-bearer-token", "", "the (optional) bearer token to use when communicating with the edgelb api server")
 	flag.StringVar(&edgelbOptions.Host, "edgelb-host", constants.DefaultEdgeLBHost, "the host at which the edgelb api server can be reached")
 	flag.BoolVar(&edgelbOptions.InsecureSkipTLSVerify, "edgelb-insecure-skip-tls-verify", false, "whether to skip verification of the tls certificate presented by the edgelb api server")
@@ -94,9 +96,11 @@ func init() {
 	flag.StringVar(&featureGates, "feature-gates", "", "a comma-separated list of \"key=value\" pairs used to toggle certain features")
 	flag.StringVar(&kubeconfig, "kubeconfig", "", "the path to the kubeconfig file to use when running outside a kubernetes cluster")
 	flag.StringVar(&cluster.Name, "kubernetes-cluster-framework-name", "", "the name of the mesos framework that corresponds to the current kubernetes cluster")
+	flag.StringVar(&kubernetesutil.LoadBalancerClass, "load-balancer-class", constants.DefaultLoadBalancerClass, "the load balancer class (as specified by the \"kubernetes.dcos.io/load-balancer-class\" annotation) identifying the services to provision using edgelb")
 	flag.StringVar(&logLevel, "log-level", log.InfoLevel.String(), "the log level to use")
 	flag.StringVar(&podNamespace, "pod-namespace", "", "the name of the namespace in which the current instance of the application is deployed (used to perform leader election)")
 	flag.StringVar(&podName, "pod-name", "", "the identity of the current instance of the application (used to perform leader election)")
+	flag.BoolVar(&kubernetesutil.ProvisionUnclassifiedServices, "provision-unclassified-services", true, "whether to provision services of type loadbalancer that don't specify a load balancer class using edgelb")
 	flag.DurationVar(&resyncPeriod, "resync-period", constants.DefaultResyncPeriod, "the maximum amount of time that may elapse between two consecutive synchronizations of ingress/service resources and the status of edgelb pools")
 }
 
@@ -217,7 +221,7 @@ func main() {
 				log.Fatalf("failed to read the tls certificate: %v", err)
 			}
 			// Create and start the admission webhook.
-			if err := admission.NewWebhook(p).Run(stopCh); err != nil {
+			if err := admission.NewWebhook(kubeClient, p).Run(stopCh); err != nil {
 				log.Fatalf("failed to serve the admission webhook: %v", err)
 			}
 		}()
@@ -241,10 +245,11 @@ func main() {
 
 	// Setup a resource lock so we can perform leader election.
 	rl, _ := resourcelock.New(
-		resourcelock.EndpointsResourceLock,
+		resourcelock.LeasesResourceLock,
 		podNamespace,
 		constants.ComponentName,
 		kubeClient.CoreV1(),
+		kubeClient.CoordinationV1(),
 		resourcelock.ResourceLockConfig{
 			Identity:      podName,
 			EventRecorder: er,
@@ -285,8 +290,10 @@ func main() {
 
 // run starts the controllers and blocks until they stop.
 func run(ctx context.Context, kubeClient kubernetes.Interface, er record.EventRecorder, edgelbManager manager.EdgeLBManager, kubeInformerFactory kubeinformers.SharedInformerFactory, kubeCache dklbcache.KubernetesResourceCache, dcosClient *dcos.APIClient, saConfig dcos.ServiceAccountOptions) {
-	ingressInformer := kubeInformerFactory.Extensions().V1beta1().Ingresses()
+	ingressInformer := kubeInformerFactory.Networking().V1().Ingresses()
+	ingressClassInformer := kubeInformerFactory.Networking().V1().IngressClasses()
 	serviceInformer := kubeInformerFactory.Core().V1().Services()
+	endpointsInformer := kubeInformerFactory.Core().V1().Endpoints()
 	// we need to setup the secrets informer so that the kubeCache
 	// gets populated accordingly
 	secretsInformer := kubeInformerFactory.Core().V1().Secrets()
@@ -294,17 +301,17 @@ func run(ctx context.Context, kubeClient kubernetes.Interface, er record.EventRe
 	secretsReflector := secretsreflector.New(dcosClient.Secrets, kubeCache, kubeClient)
 
 	// Create an instance of the ingress controller.
-	ingressController := controllers.NewIngressController(kubeClient, er, ingressInformer, serviceInformer, kubeCache, edgelbManager, secretsReflector)
+	ingressController := controllers.NewIngressController(kubeClient, er, ingressInformer, ingressClassInformer, serviceInformer, endpointsInformer, kubeCache, edgelbManager, secretsReflector)
 
 	// Create an instance of the service controller.
-	serviceController := controllers.NewServiceController(kubeClient, er, serviceInformer, kubeCache, edgelbManager)
+	serviceController := controllers.NewServiceController(kubeClient, er, serviceInformer, endpointsInformer, kubeCache, edgelbManager, secretsReflector)
 
 	// Start the shared informer factory.
 	go kubeInformerFactory.Start(ctx.Done())
 
 	// Wait for the caches to be synced before starting workers.
 	log.Debug("waiting for informer caches to be synced")
-	if ok := cache.WaitForCacheSync(ctx.Done(), kubeCache.HasSynced, ingressInformer.Informer().HasSynced, serviceInformer.Informer().HasSynced, secretsInformer.Informer().HasSynced); !ok {
+	if ok := cache.WaitForCacheSync(ctx.Done(), kubeCache.HasSynced, ingressInformer.Informer().HasSynced, ingressClassInformer.Informer().HasSynced, serviceInformer.Informer().HasSynced, endpointsInformer.Informer().HasSynced, secretsInformer.Informer().HasSynced); !ok {
 		log.Error("failed to wait for informer caches to be synced")
 		return
 	}
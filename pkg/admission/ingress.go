@@ -1,17 +1,33 @@
 package admission
 
 import (
-	extsv1beta1 "k8s.io/api/extensions/v1beta1"
+	"context"
+	"fmt"
 
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	secretsreflector "github.com/mesosphere/dklb/pkg/secrets_reflector"
 	translatorapi "github.com/mesosphere/dklb/pkg/translator/api"
 	kubernetesutil "github.com/mesosphere/dklb/pkg/util/kubernetes"
 )
 
 // validateAndMutateIngress validates the current Ingress resource.
 // If "previousIng" is not nil, the transition between "previousIng" and "currentIng" is also validated.
-func (w *Webhook) validateAndMutateIngress(currentIng, previousIng *extsv1beta1.Ingress) (*extsv1beta1.Ingress, error) {
-	// If the current Ingress resource is not annotated to be provisioned by EdgeLB, and we're not transitioning from an Ingress resource annotated to be provisioned by EdgeLB, there is nothing to validate/mutate.
-	if !kubernetesutil.IsEdgeLBIngress(currentIng) && (previousIng == nil || !kubernetesutil.IsEdgeLBIngress(previousIng)) {
+func (w *Webhook) validateAndMutateIngress(currentIng, previousIng *networkingv1.Ingress) (*networkingv1.Ingress, error) {
+	// Read the IngressClass resources against which the ingress classes of "currentIng" and "previousIng" are resolved.
+	ingressClassList, err := w.kubeClient.NetworkingV1().IngressClasses().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ingress classes: %v", err)
+	}
+	ingressClasses := make([]*networkingv1.IngressClass, 0, len(ingressClassList.Items))
+	for idx := range ingressClassList.Items {
+		ingressClasses = append(ingressClasses, &ingressClassList.Items[idx])
+	}
+
+	// If the current Ingress resource is not meant to be provisioned by EdgeLB, and we're not transitioning from an Ingress resource meant to be provisioned by EdgeLB, there is nothing to validate/mutate.
+	if !kubernetesutil.IsEdgeLBIngress(currentIng, ingressClasses) && (previousIng == nil || !kubernetesutil.IsEdgeLBIngress(previousIng, ingressClasses)) {
 		return currentIng, nil
 	}
 
@@ -24,13 +40,21 @@ func (w *Webhook) validateAndMutateIngress(currentIng, previousIng *extsv1beta1.
 		return nil, err
 	}
 
+	// Make sure that the credentials required to access the Ingress resource can be reflected, so that unsupported password hashes are reported up front rather than blocking the translation of the Ingress resource.
+	// Deleted Ingress resources are skipped so that the removal of our finalizer is never blocked.
+	if currentIng.DeletionTimestamp == nil && kubernetesutil.IsEdgeLBIngress(currentIng, ingressClasses) {
+		if err := w.validateBasicAuthSecret(currentIng, currentSpec, previousIng); err != nil {
+			return nil, err
+		}
+	}
+
 	// Mutate the Ingress resource with the expanded, validated EdgeLB pool configuration object.
 	if err := translatorapi.SetIngressEdgeLBPoolSpec(mutatedIng, currentSpec); err != nil {
 		return nil, err
 	}
 
 	// If the current operation is not an UPDATE operation, or if the Ingress is being "converted" to an EdgeLB ingress, there's nothing else to do.
-	if previousIng == nil || !kubernetesutil.IsEdgeLBIngress(previousIng) {
+	if previousIng == nil || !kubernetesutil.IsEdgeLBIngress(previousIng, ingressClasses) {
 		return mutatedIng, nil
 	}
 
@@ -48,5 +72,35 @@ func (w *Webhook) validateAndMutateIngress(currentIng, previousIng *extsv1beta1.
 	if err := currentSpec.ValidateTransition(previousSpec); err != nil {
 		return nil, err
 	}
+
+	// Keep track of the EdgeLB pool being replaced (if any).
+	if err := mutateEdgeLBPoolReplacement(mutatedIng, previousIng, &currentSpec.BaseEdgeLBPoolSpec, &previousSpec.BaseEdgeLBPoolSpec); err != nil {
+		return nil, err
+	}
 	return mutatedIng, nil
 }
+
+// validateBasicAuthSecret validates the credentials held by the Secret resource referenced by the current Ingress resource for HTTP basic authentication (if any).
+// Validation only happens when said Secret resource is first referenced, so that unrelated updates to the Ingress resource (e.g. the ones made by dklb itself) are never rejected.
+// A missing Secret resource is not considered an error, as it may be created after the Ingress resource.
+func (w *Webhook) validateBasicAuthSecret(currentIng *networkingv1.Ingress, currentSpec *translatorapi.IngressEdgeLBPoolSpec, previousIng *networkingv1.Ingress) error {
+	if currentSpec.BasicAuth == nil {
+		return nil
+	}
+	if previousIng != nil {
+		if previousSpec, err := translatorapi.GetIngressEdgeLBPoolSpec(previousIng); err == nil && previousSpec.BasicAuth != nil && *previousSpec.BasicAuth.SecretName == *currentSpec.BasicAuth.SecretName {
+			return nil
+		}
+	}
+	secret, err := w.kubeClient.CoreV1().Secrets(currentIng.Namespace).Get(context.TODO(), *currentSpec.BasicAuth.SecretName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get secret \"%s/%s\": %v", currentIng.Namespace, *currentSpec.BasicAuth.SecretName, err)
+	}
+	if err := secretsreflector.ValidateBasicAuthSecret(secret); err != nil {
+		return fmt.Errorf(".basicAuth.secretName: %v", err)
+	}
+	return nil
+}
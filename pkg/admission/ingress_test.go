@@ -0,0 +1,82 @@
+package admission
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/mesosphere/dklb/pkg/cluster"
+	"github.com/mesosphere/dklb/pkg/constants"
+	ingresstestutil "github.com/mesosphere/dklb/test/util/kubernetes/ingress"
+	secrettestutil "github.com/mesosphere/dklb/test/util/kubernetes/secret"
+)
+
+// TestWebhook_validateAndMutateIngressBasicAuth tests that the credentials referenced by an Ingress resource for HTTP basic authentication are validated up front.
+func TestWebhook_validateAndMutateIngressBasicAuth(t *testing.T) {
+	cluster.Name = "test-cluster"
+
+	shaSecret := secrettestutil.DummySecretResource("test-namespace", "sha-auth", func(secret *corev1.Secret) {
+		secret.Data["auth"] = []byte("alice:{SHA}qUqP5cyxm6YcTAhz05Hph5gvu9M=\n")
+	})
+	apr1Secret := secrettestutil.DummySecretResource("test-namespace", "apr1-auth", func(secret *corev1.Secret) {
+		secret.Data["auth"] = []byte("alice:$apr1$4Bfnb3nx$ZqYTSqHGVqbG2z5Xh8GzX/\n")
+	})
+	// newIngress returns an Ingress resource requiring HTTP basic authentication using the credentials held by the specified Secret resource.
+	newIngress := func(secretName string, deleted bool) *networkingv1.Ingress {
+		return ingresstestutil.DummyEdgeLBIngressResource("test-namespace", "test-ingress", func(ingress *networkingv1.Ingress) {
+			ingress.Annotations[constants.DklbConfigAnnotationKey] = "name: test-pool\nbasicAuth:\n  secretName: " + secretName + "\n"
+			if deleted {
+				ingress.DeletionTimestamp = &metav1.Time{}
+			}
+		})
+	}
+
+	tests := []struct {
+		description   string
+		currentIng    *networkingv1.Ingress
+		previousIng   *networkingv1.Ingress
+		expectedError bool
+	}{
+		{
+			description: "should accept sha-1 hashed passwords",
+			currentIng:  newIngress(shaSecret.Name, false),
+		},
+		{
+			description:   "should reject passwords hashed using other algorithms",
+			currentIng:    newIngress(apr1Secret.Name, false),
+			expectedError: true,
+		},
+		{
+			description: "should accept a missing secret",
+			currentIng:  newIngress("missing-auth", false),
+		},
+		{
+			description:   "should reject a newly referenced secret holding unsupported passwords",
+			currentIng:    newIngress(apr1Secret.Name, false),
+			previousIng:   newIngress(shaSecret.Name, false),
+			expectedError: true,
+		},
+		{
+			description: "should accept an update that does not change the referenced secret",
+			currentIng:  newIngress(apr1Secret.Name, false),
+			previousIng: newIngress(apr1Secret.Name, false),
+		},
+		{
+			description: "should accept an update to a deleted ingress",
+			currentIng:  newIngress(apr1Secret.Name, true),
+			previousIng: newIngress(shaSecret.Name, false),
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+		w := NewWebhook(fake.NewSimpleClientset(shaSecret, apr1Secret), tls.Certificate{})
+		_, err := w.validateAndMutateIngress(test.currentIng, test.previousIng)
+		assert.Equal(t, test.expectedError, err != nil, "unexpected error: %v", err)
+	}
+}
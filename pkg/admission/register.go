@@ -1,14 +1,16 @@
 package admission
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
 	"reflect"
 	"strings"
 
-	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
-	extsv1beta1 "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -34,31 +36,32 @@ func RegisterWebhook(kubeClient kubernetes.Interface, admissionTLSCaBundle, admi
 	}
 	// Parse "admissionFailurePolicy" as a failure policy.
 	var (
-		failurePolicy admissionregistrationv1beta1.FailurePolicyType
+		failurePolicy admissionregistrationv1.FailurePolicyType
+		sideEffects   = admissionregistrationv1.SideEffectClassNone
 	)
 	switch {
-	case strings.EqualFold(admissionFailurePolicy, string(admissionregistrationv1beta1.Fail)):
-		failurePolicy = admissionregistrationv1beta1.Fail
-	case strings.EqualFold(admissionFailurePolicy, string(admissionregistrationv1beta1.Ignore)):
-		failurePolicy = admissionregistrationv1beta1.Ignore
+	case strings.EqualFold(admissionFailurePolicy, string(admissionregistrationv1.Fail)):
+		failurePolicy = admissionregistrationv1.Fail
+	case strings.EqualFold(admissionFailurePolicy, string(admissionregistrationv1.Ignore)):
+		failurePolicy = admissionregistrationv1.Ignore
 	default:
 		return fmt.Errorf("%q is not a valid failure policy for the admission webhook", admissionFailurePolicy)
 	}
 	// Create the webhook configuration object containing the desired configuration
-	desiredCfg := &admissionregistrationv1beta1.MutatingWebhookConfiguration{
+	desiredCfg := &admissionregistrationv1.MutatingWebhookConfiguration{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: mutatingWebhookConfigurationResourceName,
 		},
-		Webhooks: []admissionregistrationv1beta1.Webhook{
+		Webhooks: []admissionregistrationv1.MutatingWebhook{
 			{
 				Name: webhookName,
-				Rules: []admissionregistrationv1beta1.RuleWithOperations{
+				Rules: []admissionregistrationv1.RuleWithOperations{
 					{
-						Operations: []admissionregistrationv1beta1.OperationType{
-							admissionregistrationv1beta1.Create,
-							admissionregistrationv1beta1.Update,
+						Operations: []admissionregistrationv1.OperationType{
+							admissionregistrationv1.Create,
+							admissionregistrationv1.Update,
 						},
-						Rule: admissionregistrationv1beta1.Rule{
+						Rule: admissionregistrationv1.Rule{
 							APIGroups: []string{
 								corev1.SchemeGroupVersion.Group,
 							},
@@ -71,16 +74,16 @@ func RegisterWebhook(kubeClient kubernetes.Interface, admissionTLSCaBundle, admi
 						},
 					},
 					{
-						Operations: []admissionregistrationv1beta1.OperationType{
-							admissionregistrationv1beta1.Create,
-							admissionregistrationv1beta1.Update,
+						Operations: []admissionregistrationv1.OperationType{
+							admissionregistrationv1.Create,
+							admissionregistrationv1.Update,
 						},
-						Rule: admissionregistrationv1beta1.Rule{
+						Rule: admissionregistrationv1.Rule{
 							APIGroups: []string{
-								extsv1beta1.SchemeGroupVersion.Group,
+								networkingv1.SchemeGroupVersion.Group,
 							},
 							APIVersions: []string{
-								extsv1beta1.SchemeGroupVersion.Version,
+								networkingv1.SchemeGroupVersion.Version,
 							},
 							Resources: []string{
 								"ingresses",
@@ -88,8 +91,8 @@ func RegisterWebhook(kubeClient kubernetes.Interface, admissionTLSCaBundle, admi
 						},
 					},
 				},
-				ClientConfig: admissionregistrationv1beta1.WebhookClientConfig{
-					Service: &admissionregistrationv1beta1.ServiceReference{
+				ClientConfig: admissionregistrationv1.WebhookClientConfig{
+					Service: &admissionregistrationv1.ServiceReference{
 						Name:      dklbServiceName,
 						Namespace: constants.KubeSystemNamespaceName,
 						Path:      &admissionPath,
@@ -97,12 +100,18 @@ func RegisterWebhook(kubeClient kubernetes.Interface, admissionTLSCaBundle, admi
 					CABundle: tlsCaBundle,
 				},
 				FailurePolicy: &failurePolicy,
+				// The admission webhook doesn't have side effects other than mutating the resource under admission.
+				SideEffects: &sideEffects,
+				// The admission webhook only understands "admission.k8s.io/v1" AdmissionReview objects.
+				AdmissionReviewVersions: []string{
+					admissionv1.SchemeGroupVersion.Version,
+				},
 			},
 		},
 	}
 
 	// Attempt to register the webhook.
-	_, err = kubeClient.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Create(desiredCfg)
+	_, err = kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Create(context.TODO(), desiredCfg, metav1.CreateOptions{})
 	if err == nil {
 		return nil
 	}
@@ -114,7 +123,7 @@ func RegisterWebhook(kubeClient kubernetes.Interface, admissionTLSCaBundle, admi
 	// At this point the webhook is already registered but the spec of the corresponding MutatingWebhookConfiguration resource may differ.
 
 	// Read the latest version of the MutatingWebhookConfiguration resource.
-	currentCfg, err := kubeClient.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Get(mutatingWebhookConfigurationResourceName, metav1.GetOptions{})
+	currentCfg, err := kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.TODO(), mutatingWebhookConfigurationResourceName, metav1.GetOptions{})
 	if err != nil {
 		// We've failed to fetch the latest version of the config
 		return err
@@ -126,7 +135,7 @@ func RegisterWebhook(kubeClient kubernetes.Interface, admissionTLSCaBundle, admi
 
 	// Attempt to update the resource by setting the resulting resource's ".spec" field according to the desired value.
 	currentCfg.Webhooks = desiredCfg.Webhooks
-	if _, err := kubeClient.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Update(currentCfg); err != nil {
+	if _, err := kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Update(context.TODO(), currentCfg, metav1.UpdateOptions{}); err != nil {
 		return err
 	}
 	return nil
@@ -0,0 +1,32 @@
+package admission
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/mesosphere/dklb/pkg/constants"
+	translatorapi "github.com/mesosphere/dklb/pkg/translator/api"
+)
+
+// mutateEdgeLBPoolReplacement records the name of the EdgeLB pool being replaced on the current Service/Ingress resource whenever the name of the target EdgeLB pool changes.
+// This allows for the controllers to remove the Service/Ingress resource from the replaced EdgeLB pool once the replacement EdgeLB pool has become ready.
+// Replacing the target EdgeLB pool while a previous replacement is still in progress is not supported, as the EdgeLB pool being replaced would otherwise be forgotten about.
+func mutateEdgeLBPoolReplacement(current, previous metav1.Object, currentSpec, previousSpec *translatorapi.BaseEdgeLBPoolSpec) error {
+	// If the name of the target EdgeLB pool hasn't changed, there's nothing to do.
+	if *previousSpec.Name == *currentSpec.Name {
+		return nil
+	}
+	// Prevent the target EdgeLB pool from being replaced while a previous replacement is still in progress.
+	if replacedPoolName, exists := previous.GetAnnotations()[constants.DklbReplacedPoolAnnotationKey]; exists {
+		return fmt.Errorf("the target edgelb pool cannot be replaced while the replacement of edgelb pool %q is in progress", replacedPoolName)
+	}
+	// Record the name of the EdgeLB pool being replaced.
+	annotations := current.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string, 1)
+	}
+	annotations[constants.DklbReplacedPoolAnnotationKey] = *previousSpec.Name
+	current.SetAnnotations(annotations)
+	return nil
+}
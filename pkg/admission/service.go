@@ -4,13 +4,14 @@ import (
 	corev1 "k8s.io/api/core/v1"
 
 	translatorapi "github.com/mesosphere/dklb/pkg/translator/api"
+	kubernetesutil "github.com/mesosphere/dklb/pkg/util/kubernetes"
 )
 
 // validateAndMutateService validates the current Service resource.
 // If "previousSvc" is not nil, the transition between "previousSvc" and "currentSvc" is also validated.
 func (w *Webhook) validateAndMutateService(currentSvc, previousSvc *corev1.Service) (*corev1.Service, error) {
-	// If the current Service resource is not of type LoadBalancer, and if we're not transitioning from a Service resource of type LoadBalancer, there is nothing to validate/mutate.
-	if currentSvc.Spec.Type != corev1.ServiceTypeLoadBalancer && (previousSvc == nil || previousSvc.Spec.Type != corev1.ServiceTypeLoadBalancer) {
+	// If the current Service resource is not meant to be provisioned by EdgeLB, and if we're not transitioning from a Service resource meant to be provisioned by EdgeLB, there is nothing to validate/mutate.
+	if !kubernetesutil.IsEdgeLBService(currentSvc) && (previousSvc == nil || !kubernetesutil.IsEdgeLBService(previousSvc)) {
 		return currentSvc, nil
 	}
 
@@ -28,8 +29,8 @@ func (w *Webhook) validateAndMutateService(currentSvc, previousSvc *corev1.Servi
 		return nil, err
 	}
 
-	// If the current operation is not an UPDATE operation, or if the Service is being "converted" to a Service of type LoadBalancer, there's nothing else to do.
-	if previousSvc == nil || previousSvc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+	// If the current operation is not an UPDATE operation, or if the Service is being "converted" to a Service meant to be provisioned by EdgeLB, there's nothing else to do.
+	if previousSvc == nil || !kubernetesutil.IsEdgeLBService(previousSvc) {
 		return mutatedSvc, nil
 	}
 
@@ -47,5 +48,10 @@ func (w *Webhook) validateAndMutateService(currentSvc, previousSvc *corev1.Servi
 	if err := currentSpec.ValidateTransition(previousSpec); err != nil {
 		return nil, err
 	}
+
+	// Keep track of the EdgeLB pool being replaced (if any).
+	if err := mutateEdgeLBPoolReplacement(mutatedSvc, previousSvc, &currentSpec.BaseEdgeLBPoolSpec, &previousSpec.BaseEdgeLBPoolSpec); err != nil {
+		return nil, err
+	}
 	return mutatedSvc, nil
 }
@@ -11,13 +11,14 @@ import (
 	"time"
 
 	log "github.com/sirupsen/logrus"
-	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	admissionv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
-	extsv1beta1 "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/client-go/kubernetes"
 )
 
 const (
@@ -31,11 +32,11 @@ var (
 	// admissionPath is the path where the admission endpoint is served.
 	admissionPath = "/admissionrequests"
 	// ingressGvk is the "GroupVersionKind" that corresponds to Ingress resources.
-	ingressGvk = &schema.GroupVersionKind{Group: "extensions", Version: "v1beta1", Kind: "Ingress"}
+	ingressGvk = &schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"}
 	// ingressGvr is the "GroupVersionResource" that corresponds to Ingress resources.
-	ingressGvr = metav1.GroupVersionResource{Group: "extensions", Version: "v1beta1", Resource: "ingresses"}
+	ingressGvr = metav1.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"}
 	// patchType is the type of patch sent in admission responses.
-	patchType = admissionv1beta1.PatchTypeJSONPatch
+	patchType = admissionv1.PatchTypeJSONPatch
 	// serviceGvk is the "GroupVersionKind" that corresponds to Service resources.
 	serviceGvk = &schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Service"}
 	// serviceGvr is the "GroupVersionResource" that corresponds to Service resources.
@@ -46,18 +47,21 @@ var (
 type Webhook struct {
 	// codecs is the codec factory to use to serialize/deserialize Kubernetes resources.
 	codecs serializer.CodecFactory
+	// kubeClient is a client to the Kubernetes core APIs, used to read the IngressClass and Secret resources referenced by Ingress resources.
+	kubeClient kubernetes.Interface
 	// tlsCertificate is the TLS certificate to use for the server.
 	tlsCertificate tls.Certificate
 }
 
 // NewWebhook creates a new instance of the admission webhook.
-func NewWebhook(tlsCertificate tls.Certificate) *Webhook {
+func NewWebhook(kubeClient kubernetes.Interface, tlsCertificate tls.Certificate) *Webhook {
 	// Create a new scheme and register the Ingress and Service types so we can serialize/deserialize them.
 	scheme := runtime.NewScheme()
-	scheme.AddKnownTypes(extsv1beta1.SchemeGroupVersion, &extsv1beta1.Ingress{})
+	scheme.AddKnownTypes(networkingv1.SchemeGroupVersion, &networkingv1.Ingress{})
 	scheme.AddKnownTypes(corev1.SchemeGroupVersion, &corev1.Service{})
 	return &Webhook{
 		codecs:         serializer.NewCodecFactory(scheme),
+		kubeClient:     kubeClient,
 		tlsCertificate: tlsCertificate,
 	}
 }
@@ -114,9 +118,15 @@ func (w *Webhook) handleAdmission(res http.ResponseWriter, req *http.Request) {
 	}
 
 	// aReq is the AdmissionReview that was sent to the admission webhook.
-	aReq := admissionv1beta1.AdmissionReview{}
+	aReq := admissionv1.AdmissionReview{}
 	// rRes is the AdmissionReview that will be returned.
-	aRes := admissionv1beta1.AdmissionReview{}
+	// Its apiVersion and kind must match the ones of the AdmissionReview that was sent.
+	aRes := admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: admissionv1.SchemeGroupVersion.String(),
+			Kind:       "AdmissionReview",
+		},
+	}
 
 	// Deserialize the requested AdmissionReview and, if successful, pass it to the provided admission function.
 	deserializer := w.codecs.UniversalDeserializer()
@@ -140,7 +150,7 @@ func (w *Webhook) handleAdmission(res http.ResponseWriter, req *http.Request) {
 	}
 }
 
-func (w *Webhook) validateAndMutateResource(rev admissionv1beta1.AdmissionReview) *admissionv1beta1.AdmissionResponse {
+func (w *Webhook) validateAndMutateResource(rev admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
 	var (
 		// currentObj will contain the resource in its current form.
 		// It MUST NOT be modified, as it is used as the basis for the patch to apply as a result of the current request.
@@ -173,13 +183,13 @@ func (w *Webhook) validateAndMutateResource(rev admissionv1beta1.AdmissionReview
 
 	// Populate "currentObj" and "previousObj" based on the type of the current operation.
 	switch rev.Request.Operation {
-	case admissionv1beta1.Create:
+	case admissionv1.Create:
 		// Deserialize the current object.
 		currentObj, _, err = w.codecs.UniversalDeserializer().Decode(rev.Request.Object.Raw, currentGVK, nil)
 		if err != nil {
 			return admissionResponseFromError(fmt.Errorf("failed to deserialize the current object: %v", err))
 		}
-	case admissionv1beta1.Update:
+	case admissionv1.Update:
 		// Deserialize the current object.
 		currentObj, _, err = w.codecs.UniversalDeserializer().Decode(rev.Request.Object.Raw, currentGVK, nil)
 		if err != nil {
@@ -194,12 +204,12 @@ func (w *Webhook) validateAndMutateResource(rev admissionv1beta1.AdmissionReview
 
 	// Perform validation on the current resource according to its type.
 	switch cObj := currentObj.(type) {
-	case *extsv1beta1.Ingress:
+	case *networkingv1.Ingress:
 		var (
-			previousIng *extsv1beta1.Ingress
+			previousIng *networkingv1.Ingress
 		)
 		if previousObj != nil {
-			previousIng = previousObj.(*extsv1beta1.Ingress)
+			previousIng = previousObj.(*networkingv1.Ingress)
 		}
 		mutatedObj, err = w.validateAndMutateIngress(cObj, previousIng)
 	case *corev1.Service:
@@ -226,7 +236,7 @@ func (w *Webhook) validateAndMutateResource(rev admissionv1beta1.AdmissionReview
 	}
 
 	// Return an admission response that admits the resource and contains the patch to be applied.
-	return &admissionv1beta1.AdmissionResponse{
+	return &admissionv1.AdmissionResponse{
 		Allowed:   true,
 		Patch:     patch,
 		PatchType: &patchType,
@@ -234,8 +244,8 @@ func (w *Webhook) validateAndMutateResource(rev admissionv1beta1.AdmissionReview
 }
 
 // admissionResponseFromError creates an admission response based on the specified error.
-func admissionResponseFromError(err error) *admissionv1beta1.AdmissionResponse {
-	return &admissionv1beta1.AdmissionResponse{
+func admissionResponseFromError(err error) *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{
 		Allowed: false,
 		Result: &metav1.Status{
 			Message: err.Error(),
@@ -2,17 +2,21 @@ package cache
 
 import (
 	corev1 "k8s.io/api/core/v1"
-	extsv1beta1 "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	kubeinformers "k8s.io/client-go/informers"
 	corev1informers "k8s.io/client-go/informers/core/v1"
-	extsv1beta1informers "k8s.io/client-go/informers/extensions/v1beta1"
+	networkingv1informers "k8s.io/client-go/informers/networking/v1"
 )
 
 // informerBackedResourceCache is an implementation of KubernetesResourceCache backed by informers and their associated listers.
 type informerBackedResourceCache struct {
+	// endpointsInformer is an informer for Endpoints resources.
+	endpointsInformer corev1informers.EndpointsInformer
 	// ingressInformer is an informer for Ingress resources.
-	ingressInformer extsv1beta1informers.IngressInformer
+	ingressInformer networkingv1informers.IngressInformer
+	// ingressClassInformer is an informer for IngressClass resources.
+	ingressClassInformer networkingv1informers.IngressClassInformer
 	// secretInformer is an informer for Secret resources.
 	secretInformer corev1informers.SecretInformer
 	// serviceInformer is an informer for Service resources.
@@ -22,27 +26,39 @@ type informerBackedResourceCache struct {
 // NewInformerBackedResourceCache returns a new cache that reads resources using listers obtained from the provided shared informer factory..
 func NewInformerBackedResourceCache(factory kubeinformers.SharedInformerFactory) KubernetesResourceCache {
 	return &informerBackedResourceCache{
-		ingressInformer: factory.Extensions().V1beta1().Ingresses(),
-		secretInformer:  factory.Core().V1().Secrets(),
-		serviceInformer: factory.Core().V1().Services(),
+		endpointsInformer:    factory.Core().V1().Endpoints(),
+		ingressInformer:      factory.Networking().V1().Ingresses(),
+		ingressClassInformer: factory.Networking().V1().IngressClasses(),
+		secretInformer:       factory.Core().V1().Secrets(),
+		serviceInformer:      factory.Core().V1().Services(),
 	}
 }
 
 // HasSynced returns a value indicating whether the cache is synced.
 func (c *informerBackedResourceCache) HasSynced() bool {
-	return c.ingressInformer.Informer().HasSynced() && c.serviceInformer.Informer().HasSynced()
+	return c.endpointsInformer.Informer().HasSynced() && c.ingressInformer.Informer().HasSynced() && c.ingressClassInformer.Informer().HasSynced() && c.serviceInformer.Informer().HasSynced()
+}
+
+// GetEndpoints returns the Endpoints resource with the specified namespace and name.
+func (c *informerBackedResourceCache) GetEndpoints(namespace, name string) (*corev1.Endpoints, error) {
+	return c.endpointsInformer.Lister().Endpoints(namespace).Get(name)
 }
 
 // GetIngress returns the Ingress resource with the specified namespace and name.
-func (c *informerBackedResourceCache) GetIngress(namespace, name string) (*extsv1beta1.Ingress, error) {
+func (c *informerBackedResourceCache) GetIngress(namespace, name string) (*networkingv1.Ingress, error) {
 	return c.ingressInformer.Lister().Ingresses(namespace).Get(name)
 }
 
 // GetIngresses returns a list of all Ingress resources in the specified namespace.
-func (c *informerBackedResourceCache) GetIngresses(namespace string) ([]*extsv1beta1.Ingress, error) {
+func (c *informerBackedResourceCache) GetIngresses(namespace string) ([]*networkingv1.Ingress, error) {
 	return c.ingressInformer.Lister().Ingresses(namespace).List(labels.Everything())
 }
 
+// GetIngressClasses returns a list of all IngressClass resources.
+func (c *informerBackedResourceCache) GetIngressClasses() ([]*networkingv1.IngressClass, error) {
+	return c.ingressClassInformer.Lister().List(labels.Everything())
+}
+
 // GetSecret returns the Secret resource with the specified namespace and name.
 func (c *informerBackedResourceCache) GetSecret(namespace, name string) (*corev1.Secret, error) {
 	return c.secretInformer.Lister().Secrets(namespace).Get(name)
@@ -5,10 +5,10 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
-	extsv1beta1 "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/apimachinery/pkg/util/intstr"
 
 	dklbcache "github.com/mesosphere/dklb/pkg/cache"
 	cachetestutil "github.com/mesosphere/dklb/test/util/cache"
@@ -19,12 +19,33 @@ import (
 
 var (
 	// dummyIngress1 represents a dummy Ingress resource.
-	dummyIngress1 = ingresstestutil.DummyEdgeLBIngressResource("namespace-1", "name-1", func(ingress *extsv1beta1.Ingress) {
-		ingress.Spec.Backend = &extsv1beta1.IngressBackend{
-			ServiceName: "foo",
-			ServicePort: intstr.FromInt(80),
+	dummyIngress1 = ingresstestutil.DummyEdgeLBIngressResource("namespace-1", "name-1", func(ingress *networkingv1.Ingress) {
+		ingress.Spec.DefaultBackend = &networkingv1.IngressBackend{
+			Service: &networkingv1.IngressServiceBackend{
+				Name: "foo",
+				Port: networkingv1.ServiceBackendPort{Number: 80},
+			},
 		}
 	})
+	// dummyIngressClass1 represents a dummy IngressClass resource.
+	dummyIngressClass1 = ingresstestutil.DummyEdgeLBIngressClassResource("name-1")
+	// dummyEndpoints1 represents a dummy Endpoints resource.
+	dummyEndpoints1 = &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "namespace-1",
+			Name:      "name-1",
+		},
+		Subsets: []corev1.EndpointSubset{
+			{
+				Addresses: []corev1.EndpointAddress{
+					{IP: "9.0.0.1"},
+				},
+				Ports: []corev1.EndpointPort{
+					{Port: 8080},
+				},
+			},
+		},
+	}
 	// dummyService1 represents a dummy Service resource.
 	dummyService1 = servicetestutil.DummyServiceResource("namespace-1", "name-1", func(service *corev1.Service) {
 		service.Spec.Ports = []corev1.ServicePort{
@@ -47,6 +68,42 @@ func TestHasSynced(t *testing.T) {
 	assert.True(t, cache.HasSynced())
 }
 
+// TestGetEndpoints tests the "GetEndpoints" function.
+func TestGetEndpoints(t *testing.T) {
+	cache := dklbcache.NewInformerBackedResourceCache(cachetestutil.NewFakeSharedInformerFactory(dummyEndpoints1))
+	tests := []struct {
+		description    string
+		namespace      string
+		name           string
+		expectedResult *corev1.Endpoints
+		expectedError  error
+	}{
+		{
+			description:    "get an existing endpoints resource",
+			namespace:      dummyEndpoints1.Namespace,
+			name:           dummyEndpoints1.Name,
+			expectedResult: dummyEndpoints1,
+			expectedError:  nil,
+		},
+		{
+			description:    "get an inexistent endpoints resource",
+			namespace:      "foo",
+			name:           "bar",
+			expectedResult: nil,
+			expectedError:  kubeerrors.NewNotFound(schema.GroupResource{Group: "", Resource: "endpoints"}, "bar"),
+		},
+	}
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+		res, err := cache.GetEndpoints(test.namespace, test.name)
+		if test.expectedError != nil {
+			assert.Equal(t, test.expectedError, err)
+		} else {
+			assert.Equal(t, test.expectedResult, res)
+		}
+	}
+}
+
 // TestGetIngress tests the "GetIngress" function.
 func TestGetIngress(t *testing.T) {
 	cache := dklbcache.NewInformerBackedResourceCache(cachetestutil.NewFakeSharedInformerFactory(dummyIngress1))
@@ -54,7 +111,7 @@ func TestGetIngress(t *testing.T) {
 		description    string
 		namespace      string
 		name           string
-		expectedResult *extsv1beta1.Ingress
+		expectedResult *networkingv1.Ingress
 		expectedError  error
 	}{
 		{
@@ -69,7 +126,7 @@ func TestGetIngress(t *testing.T) {
 			namespace:      "foo",
 			name:           "bar",
 			expectedResult: nil,
-			expectedError:  kubeerrors.NewNotFound(schema.GroupResource{Group: "extensions", Resource: "ingress"}, "bar"),
+			expectedError:  kubeerrors.NewNotFound(schema.GroupResource{Group: "networking.k8s.io", Resource: "ingress"}, "bar"),
 		},
 	}
 	for _, test := range tests {
@@ -83,6 +140,14 @@ func TestGetIngress(t *testing.T) {
 	}
 }
 
+// TestGetIngressClasses tests the "GetIngressClasses" function.
+func TestGetIngressClasses(t *testing.T) {
+	cache := dklbcache.NewInformerBackedResourceCache(cachetestutil.NewFakeSharedInformerFactory(dummyIngressClass1))
+	res, err := cache.GetIngressClasses()
+	assert.NoError(t, err)
+	assert.Equal(t, []*networkingv1.IngressClass{dummyIngressClass1}, res)
+}
+
 // TestGetService tests the "GetService" function.
 func TestGetService(t *testing.T) {
 	cache := dklbcache.NewInformerBackedResourceCache(cachetestutil.NewFakeSharedInformerFactory(dummyService1))
@@ -2,17 +2,21 @@ package cache
 
 import (
 	corev1 "k8s.io/api/core/v1"
-	extsv1beta1 "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 )
 
 // KubernetesResourceCache knows how to list Kubernetes resources.
 type KubernetesResourceCache interface {
 	// HasSynced returns a value indicating whether the cache is synced.
 	HasSynced() bool
+	// GetEndpoints returns the Endpoints resource with the specified namespace and name.
+	GetEndpoints(namespace, name string) (*corev1.Endpoints, error)
 	// GetIngress returns the Ingress resource with the specified namespace and name.
-	GetIngress(string, string) (*extsv1beta1.Ingress, error)
+	GetIngress(string, string) (*networkingv1.Ingress, error)
+	// GetIngressClasses returns a list of all IngressClass resources.
+	GetIngressClasses() ([]*networkingv1.IngressClass, error)
 	// GetIngresses returns a list of all Ingress resources in the specified namespace.
-	GetIngresses(string) ([]*extsv1beta1.Ingress, error)
+	GetIngresses(string) ([]*networkingv1.Ingress, error)
 	// GetSecret returns the Secret resource with the specified namespace and name.
 	GetSecret(namespace, name string) (*corev1.Secret, error)
 	// GetService returns the Service resource with the specified namespace and name.
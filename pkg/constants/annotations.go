@@ -8,11 +8,20 @@ const (
 const (
 	// EdgeLBIngressClassAnnotationKey is the key of the annotation that selects the ingress controller used to satisfy a given Ingress resource.
 	// This is the same annotation that is used by Ingress controllers such as "kubernetes/ingress-nginx" or "containous/traefik".
+	// It has been deprecated in favor of the ".spec.ingressClassName" field, but is still honored.
 	EdgeLBIngressClassAnnotationKey = "kubernetes.io/ingress.class"
 	// EdgeLBIngressClassAnnotationValue is the value that must be used for the annotation that selects the ingress controller used to satisfy a given Ingress resource.
 	// Only Ingres resources having this as the value of the aforementioned annotation will be provisioned using EdgeLB.
 	EdgeLBIngressClassAnnotationValue = "edgelb"
 
+	// EdgeLBLoadBalancerClassAnnotationKey is the key of the annotation that selects the load balancer implementation used to satisfy a given Service resource.
+	// Only Service resources having the load balancer class configured for dklb (or no load balancer class at all, if so configured) as the value of this annotation will be provisioned using EdgeLB.
+	EdgeLBLoadBalancerClassAnnotationKey = annotationKeyPrefix + "load-balancer-class"
+
+	// DklbExposeAnnotationKey is the key of the annotation that holds whether a given Service resource of type "NodePort" should be provisioned using EdgeLB.
+	// Service resources of type "LoadBalancer" don't need to specify this annotation.
+	DklbExposeAnnotationKey = annotationKeyPrefix + "dklb-expose"
+
 	// DklbConfigAnnotationKey is the key of the annotation that holds the target EdgeLB pool's specification for a given Service/Ingress resource.
 	DklbConfigAnnotationKey = annotationKeyPrefix + "dklb-config"
 
@@ -22,6 +31,20 @@ const (
 	// It also allows for performing end-to-end testing on the admission webhook without the need for provisioning EdgeLB pools.
 	DklbPaused = annotationKeyPrefix + "dklb-paused"
 
+	// DklbReplacedPoolAnnotationKey is the key of the annotation that holds the name of the EdgeLB pool being replaced by the target EdgeLB pool of a given Service/Ingress resource.
+	// It is set by the admission webhook whenever the name of the target EdgeLB pool changes, and removed by dklb once the Service/Ingress resource has been removed from the replaced EdgeLB pool.
+	DklbReplacedPoolAnnotationKey = annotationKeyPrefix + "dklb-replaced-pool"
+
+	// DklbSkipCleanupAnnotationKey is the key of the annotation that holds whether dklb should skip removing a given Service/Ingress resource from the target EdgeLB pool when said resource is deleted.
+	// It is meant to be used as an escape hatch when EdgeLB is unreachable, as in that case the deletion of the Service/Ingress resource would be blocked by dklb's finalizer.
+	DklbSkipCleanupAnnotationKey = annotationKeyPrefix + "dklb-skip-cleanup"
+
+	// DklbBasicAuthSecretAnnotationKey is the key of the annotation that holds the MD5 hash of the translated basic authentication credentials.
+	DklbBasicAuthSecretAnnotationKey = annotationKeyPrefix + "dklb-auth-hash"
+
+	// DklbCASecretAnnotationKey is the key of the annotation that holds the MD5 hash of the base64 decoded CA bundle.
+	DklbCASecretAnnotationKey = annotationKeyPrefix + "dklb-ca-hash"
+
 	// DklbSecretAnnotationKey is the key of the annotation that holds the MD5 hash of the base64 decoded certificate and private key.
 	DklbSecretAnnotationKey = annotationKeyPrefix + "dklb-hash"
 )
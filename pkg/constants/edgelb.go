@@ -5,10 +5,14 @@ const (
 	EdgeLBBackendBackup = "backup"
 	// EdgeLBBackendBalanceLeastConnections holds the value used to request the "leastconn" mode for a backend.
 	EdgeLBBackendBalanceLeastConnections = "leastconn"
-	// EdgeLBBackendInsecureSkipTLSVerify holds the value used as part of "miscStr" in order to disable verification of the TLS certificate presented by a given backend (if any).
-	EdgeLBBackendInsecureSkipTLSVerify = "ssl verify none"
+	// EdgeLBBackendBalanceSource holds the value used to request the "source" mode for a backend.
+	EdgeLBBackendBalanceSource = "source"
+	// EdgeLBBackendH2C holds the value used as part of "miscStr" in order to instruct EdgeLB to talk cleartext HTTP/2 to a given backend.
+	EdgeLBBackendH2C = "proto h2"
 	// EdgeLBBackendTLSCheck holds the value used as part of "miscStr" in order to instruct EdgeLB to perform health-checks over TLS.
 	EdgeLBBackendTLSCheck = "check-ssl"
+	// EdgeLBBackendVerifyTLSFormatString is the format string used to compute the value used as part of "miscStr" in order to instruct EdgeLB to communicate with a given backend over TLS, verifying its certificate against the CA bundle stored in the specified pool secret file.
+	EdgeLBBackendVerifyTLSFormatString = `ssl verify required ca-file "$SECRETS/%s"`
 	// EdgeLBCloudProviderPoolNamePrefix is the prefix used in the names of EdgeLB pools requesting a cloud load-balancer to be configured.
 	EdgeLBCloudProviderPoolNamePrefix = "cloud"
 	// EdgeLBFrontendBindAddress holds the bind address to use in EdgeLB frontends.
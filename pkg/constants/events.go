@@ -3,13 +3,23 @@ package constants
 const (
 	// ReasonNoDefaultBackendSpecified is the reason used in Kubernetes events emitted whenever an Ingress resource doesn't define a default backend.
 	ReasonNoDefaultBackendSpecified = "NoDefaultBackendSpecified"
+	// ReasonFrontendSettingsIgnored is the reason used in Kubernetes events emitted whenever frontend-level settings of an Ingress resource cannot be applied because the EdgeLB frontend is owned by another Ingress resource.
+	ReasonFrontendSettingsIgnored = "FrontendSettingsIgnored"
+	// ReasonHealthCheckPathIgnored is the reason used in Kubernetes events emitted whenever the health-check path configured for an Ingress backend is ignored because the target Service resources have an external traffic policy of "Local".
+	ReasonHealthCheckPathIgnored = "HealthCheckPathIgnored"
 	// ReasonInvalidBackendService is the reason used in Kubernetes events emitted due to a missing or otherwise invalid Service resource referenced by an Ingress resource.
 	ReasonInvalidBackendService = "InvalidBackendService"
+	// ReasonCleanupSkipped is the reason used in Kubernetes events emitted whenever a Service/Ingress resource is deleted without being removed from the target EdgeLB pool.
+	ReasonCleanupSkipped = "CleanupSkipped"
+	// ReasonEdgeLBPoolRedeploying is the reason used in Kubernetes events emitted whenever a change to the settings of an EdgeLB pool (e.g. its CPU request) causes EdgeLB to redeploy its load balancer instances.
+	ReasonEdgeLBPoolRedeploying = "EdgeLBPoolRedeploying"
 	// ReasonTranslationError is the reason used in Kubernetes events emitted due to failed translation of a Service/Ingress resource into an EdgeLB pool.
 	// TODO (@bcustodio) Understand if we should break this down into more fine-grained reasons (e.g. "InvalidSpec", "NetworkingError", ...).
 	ReasonTranslationError = "TranslationError"
 	// ReasonTranslationPaused is the reason used in Kubernetes events emitted while translation for a given Service/Ingress resource is paused.
 	ReasonTranslationPaused = "TranslationPaused"
+	// ReasonUnsupportedServicePort is the reason used in Kubernetes events emitted whenever a Service resource defines a service port that cannot be exposed by EdgeLB (e.g. a UDP port).
+	ReasonUnsupportedServicePort = "UnsupportedServicePort"
 	// ReasonSecretReflectionError is the reason used in Kubernetes events emitted when an error occurs
 	// reflecting the Kubernetes secret to DC/OS.
 	ReasonSecretReflectionError = "SecretReflectionError"
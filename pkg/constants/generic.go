@@ -21,8 +21,17 @@ const (
 	DefaultEdgeLBPoolSize = 1
 	// DefaultEdgeLBScheme is the default scheme to use when communicating with the EdgeLB API server.
 	DefaultEdgeLBScheme = "http"
+	// DefaultEndpointsBatchPeriod is the (default) amount of time during which changes to the Endpoints resource associated with a given Service resource are batched before the Service/Ingress resources targeting its pods directly are synced.
+	DefaultEndpointsBatchPeriod = 5 * time.Second
+	// DefaultLoadBalancerClass is the (default) load balancer class that identifies Service resources meant to be provisioned using EdgeLB.
+	DefaultLoadBalancerClass = "edgelb"
 	// DefaultResyncPeriod is the (default) maximum amount of time that may elapse between two consecutive synchronizations of Ingress/Service resources and the status of EdgeLB pools.
 	DefaultResyncPeriod = 2 * time.Minute
+	// DklbFinalizer is the finalizer added to Service/Ingress resources provisioned using EdgeLB.
+	// It prevents said resources from being removed before dklb removes the corresponding EdgeLB backends and frontends from the target EdgeLB pool.
+	DklbFinalizer = annotationKeyPrefix + "dklb-cleanup"
+	// EdgeLBIngressControllerName is the name of the controller that must be specified in the ".spec.controller" field of IngressClass resources whose Ingress resources are meant to be provisioned using EdgeLB.
+	EdgeLBIngressControllerName = annotationKeyPrefix + "edgelb"
 	// KubeNodeTaskPattern is the pattern used to match Mesos tasks that correspond to Kubernetes nodes (either private or public).
 	KubeNodeTaskPattern = "^kube-node-.*$"
 	// KubeSystemNamespaceName holds the name of the "kube-system" namespace.
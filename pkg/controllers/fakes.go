@@ -3,6 +3,7 @@ package controllers
 import (
 	"context"
 	"sync"
+	"time"
 )
 
 type fakeGenericController struct {
@@ -31,6 +32,12 @@ func (c *fakeGenericController) enqueue(obj interface{}) {
 	c.queue = append(c.queue, obj)
 }
 
+func (c *fakeGenericController) enqueueAfter(obj interface{}, _ time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.queue = append(c.queue, obj)
+}
+
 func (c *fakeGenericController) enqueueTombstone(obj interface{}) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
@@ -10,6 +10,14 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
+
+	"github.com/mesosphere/dklb/pkg/constants"
+)
+
+var (
+	// EndpointsBatchPeriod is the amount of time during which changes to the Endpoints resource associated with a given Service resource are batched before the Service/Ingress resources targeting its pods directly are synced.
+	// It prevents frequent changes to Endpoints resources (e.g. during rolling updates) from causing an update to the target EdgeLB pool for every change.
+	EndpointsBatchPeriod = constants.DefaultEndpointsBatchPeriod
 )
 
 // Controller represents a controller that handles Kubernetes resources.
@@ -22,6 +30,9 @@ type controller interface {
 	Controller
 	// enqueue takes a Kubernetes resource, computes its resource key and puts it as a work item onto the work queue.
 	enqueue(obj interface{})
+	// enqueueAfter takes a Kubernetes resource, computes its resource key and puts it as a work item onto the work queue after the specified delay.
+	// Work items for the same Kubernetes resource that are enqueued while waiting for the delay to elapse are coalesced into a single one.
+	enqueueAfter(obj interface{}, delay time.Duration)
 	// enqueueTombstone takes the tombstone of a Kubernetes resource that has been deleted, computes its resource key and puts it as a work item onto the work queue.
 	// Must only be used to handle cleanup in scenarios where the Kubernetes resource has been deleted.
 	// For all other usage scenarios, "enqueue" should be used instead.
@@ -150,6 +161,18 @@ func (c *genericController) enqueue(obj interface{}) {
 	}
 }
 
+// enqueueAfter takes a Kubernetes resource, computes its resource key and puts it as a work item onto the work queue after the specified delay.
+// Work items for the same Kubernetes resource that are enqueued while waiting for the delay to elapse are coalesced into a single one.
+func (c *genericController) enqueueAfter(obj interface{}, delay time.Duration) {
+	if key, err := cache.MetaNamespaceKeyFunc(obj); err != nil {
+		runtime.HandleError(err)
+	} else {
+		c.workqueue.AddAfter(WorkItem{
+			Key: key,
+		}, delay)
+	}
+}
+
 // enqueueTombstone takes the tombstone of a Kubernetes resource that has been deleted, computes its resource key and puts it as a work item onto the work queue.
 // Must only be used to handle cleanup in scenarios where the Kubernetes resource has been deleted.
 // For all other usage scenarios, "enqueue" should be used instead.
@@ -4,16 +4,17 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
-	extsv1beta1 "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	corev1informers "k8s.io/client-go/informers/core/v1"
-	extsv1beta1informers "k8s.io/client-go/informers/extensions/v1beta1"
+	networkingv1informers "k8s.io/client-go/informers/networking/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
@@ -24,6 +25,7 @@ import (
 	"github.com/mesosphere/dklb/pkg/metrics"
 	secretsreflector "github.com/mesosphere/dklb/pkg/secrets_reflector"
 	"github.com/mesosphere/dklb/pkg/translator"
+	translatorapi "github.com/mesosphere/dklb/pkg/translator/api"
 	kubernetesutil "github.com/mesosphere/dklb/pkg/util/kubernetes"
 )
 
@@ -53,7 +55,7 @@ type IngressController struct {
 }
 
 // NewIngressController creates a new instance of the EdgeLB ingress controller.
-func NewIngressController(kubeClient kubernetes.Interface, er record.EventRecorder, ingressInformer extsv1beta1informers.IngressInformer, serviceInformer corev1informers.ServiceInformer, kubeCache dklbcache.KubernetesResourceCache, edgelbManager manager.EdgeLBManager, secretsReflector secretsreflector.SecretsReflector) *IngressController {
+func NewIngressController(kubeClient kubernetes.Interface, er record.EventRecorder, ingressInformer networkingv1informers.IngressInformer, ingressClassInformer networkingv1informers.IngressClassInformer, serviceInformer corev1informers.ServiceInformer, endpointsInformer corev1informers.EndpointsInformer, kubeCache dklbcache.KubernetesResourceCache, edgelbManager manager.EdgeLBManager, secretsReflector secretsreflector.SecretsReflector) *IngressController {
 	// Create a new instance of the ingress controller with the specified name and threadiness.
 	c := &IngressController{
 		kubeClient:       kubeClient,
@@ -66,42 +68,56 @@ func NewIngressController(kubeClient kubernetes.Interface, er record.EventRecord
 	// Make processQueueItem the handler for items popped out of the work queue.
 	c.base = newGenericController(ingressControllerName, ingressControllerThreadiness, c.processQueueItem, c.logger)
 
-	c.initialize(ingressInformer, serviceInformer)
+	c.initialize(ingressInformer, ingressClassInformer, serviceInformer, endpointsInformer)
 
 	return c
 }
 
-func (c *IngressController) initialize(ingressInformer extsv1beta1informers.IngressInformer, serviceInformer corev1informers.ServiceInformer) {
+func (c *IngressController) initialize(ingressInformer networkingv1informers.IngressInformer, ingressClassInformer networkingv1informers.IngressClassInformer, serviceInformer corev1informers.ServiceInformer, endpointsInformer corev1informers.EndpointsInformer) {
 	// Setup an event handler to inform us when Ingress resources change.
 	// An Ingress resource is enqueued in the following scenarios:
-	// * It was listed ("ADDED") and has the required "kubernetes.io/ingress.class" annotation set to "edgelb".
-	// * It was updated ("MODIFIED") and either the old or the new types - or both - have this same annotation.
-	//   * This allows for handling the cases in which the annotation is changed/removed.
-	// * It was deleted ("DELETED") and has this same annotation.
+	// * It was listed ("ADDED") and is meant to be provisioned by EdgeLB according to its ingress class (or still holds our finalizer).
+	// * It was updated ("MODIFIED") and either the old or the new types - or both - are meant to be provisioned by EdgeLB (or the new type still holds our finalizer).
+	//   * This allows for handling the cases in which the ingress class is changed/removed.
+	// * It was deleted ("DELETED") and is meant to be provisioned by EdgeLB.
 	ingressInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
-			ingress := obj.(*extsv1beta1.Ingress)
-			if !kubernetesutil.IsEdgeLBIngress(ingress) {
+			ingress := obj.(*networkingv1.Ingress)
+			if !c.isEdgeLBIngress(ingress) && !kubernetesutil.HasFinalizer(ingress, constants.DklbFinalizer) {
 				return
 			}
 			c.base.enqueue(ingress)
 		},
 		UpdateFunc: func(oldObj, newObj interface{}) {
-			oldIngress := oldObj.(*extsv1beta1.Ingress)
-			newIngress := newObj.(*extsv1beta1.Ingress)
-			if !kubernetesutil.IsEdgeLBIngress(oldIngress) && !kubernetesutil.IsEdgeLBIngress(newIngress) {
+			oldIngress := oldObj.(*networkingv1.Ingress)
+			newIngress := newObj.(*networkingv1.Ingress)
+			if !c.isEdgeLBIngress(oldIngress) && !c.isEdgeLBIngress(newIngress) && !kubernetesutil.HasFinalizer(newIngress, constants.DklbFinalizer) {
 				return
 			}
 			c.base.enqueue(newIngress)
 		},
 		DeleteFunc: func(obj interface{}) {
-			ingress := obj.(*extsv1beta1.Ingress)
-			if !kubernetesutil.IsEdgeLBIngress(ingress) {
+			ingress := obj.(*networkingv1.Ingress)
+			if !c.isEdgeLBIngress(ingress) {
 				return
 			}
 			c.base.enqueueTombstone(ingress)
 		},
 	})
+	// Setup an event handler to inform us when IngressClass resources change.
+	// This allows us to enqueue all Ingress resources that reference said IngressClass resource (or that don't reference any, in case it is the default one), as they may have started or stopped being provisioned by EdgeLB.
+	ingressClassInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueueIngressesReferencingIngressClass(obj)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			c.enqueueIngressesReferencingIngressClass(oldObj)
+			c.enqueueIngressesReferencingIngressClass(newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			c.enqueueIngressesReferencingIngressClass(obj)
+		},
+	})
 	// Setup an event handler to inform us when Service resources change.
 	// This allows us to enqueue all Ingress resources that reference said Service resource.
 	serviceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -115,6 +131,19 @@ func (c *IngressController) initialize(ingressInformer extsv1beta1informers.Ingr
 			c.enqueueIngressesReferencingService(obj.(*corev1.Service))
 		},
 	})
+	// Setup an event handler to inform us when Endpoints resources change.
+	// This allows us to enqueue all Ingress resources that reference the associated Service resource and for which EdgeLB routes traffic directly to pod IPs.
+	endpointsInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueueIngressesReferencingEndpoints(obj)
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			c.enqueueIngressesReferencingEndpoints(obj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			c.enqueueIngressesReferencingEndpoints(obj)
+		},
+	})
 }
 
 func (c *IngressController) Run(ctx context.Context) error {
@@ -151,46 +180,107 @@ func (c *IngressController) processQueueItem(workItem WorkItem) error {
 			return fmt.Errorf("ingress %q in work queue no longer exists, and no tombstone was recovered", workItem.Key)
 		}
 		// Create a deep copy of the tombstone in order to avoid mutating the cache.
-		ingress = workItem.Tombstone.(*extsv1beta1.Ingress).DeepCopy()
+		ingress = workItem.Tombstone.(*networkingv1.Ingress).DeepCopy()
 		// Set the current timestamp as the value of ".metadata.deletionTimestamp" so the translator can understand that the resource has been deleted.
 		deletionTimestamp := metav1.NewTime(startTime)
 		ingress.ObjectMeta.DeletionTimestamp = &deletionTimestamp
 	}
 
 	// check if ingress is annotated correctly
-	if !kubernetesutil.IsEdgeLBIngress(ingress) {
+	// Ingress resources that aren't annotated correctly anymore but still hold our finalizer must still be removed from the target EdgeLB pool.
+	if !c.isEdgeLBIngress(ingress) && !kubernetesutil.HasFinalizer(ingress, constants.DklbFinalizer) {
 		return nil
 	}
 
+	// ingressDeleted holds whether the Ingress resource has been deleted (or is not meant to be provisioned by EdgeLB anymore), in which case it must be removed from the target EdgeLB pool.
+	ingressDeleted := ingress.ObjectMeta.DeletionTimestamp != nil || !c.isEdgeLBIngress(ingress)
+
+	// Release our finalizer without removing the Ingress resource from the target EdgeLB pool in case we've been asked to do so (e.g. because EdgeLB is unreachable).
+	if ingress.ObjectMeta.DeletionTimestamp != nil && ingress.Annotations[constants.DklbSkipCleanupAnnotationKey] == strconv.FormatBool(true) {
+		c.er.Eventf(ingress, corev1.EventTypeWarning, constants.ReasonCleanupSkipped, "skipping the removal of the ingress from the edgelb pool as requested")
+		c.logger.Warnf("skipping the removal of %q from the edgelb pool as requested", kubernetesutil.Key(ingress))
+		return c.removeFinalizer(ingress)
+	}
+
 	// Return immediately if translation is paused for the current Ingress resource.
+	// In case the Ingress resource has been deleted, we keep our finalizer so that it is only released after the Ingress resource is removed from the target EdgeLB pool (i.e. once translation is resumed).
 	if ingress.Annotations[constants.DklbPaused] == strconv.FormatBool(true) {
 		c.er.Eventf(ingress, corev1.EventTypeWarning, constants.ReasonTranslationPaused, "translation is paused for the resource")
 		c.logger.Warnf("skipping translation of %q as translation is paused for the resource", kubernetesutil.Key(ingress))
 		return nil
 	}
 
-	// Check if we need to reflect any secrets back to DC/OS.
-	for _, ingressTLS := range ingress.Spec.TLS {
-		c.logger.Debugf("reflecting ingress secret UID=%s %s/%s", ingress.UID, ingress.Namespace, ingressTLS.SecretName)
-		if err := c.secretsReflector.Reflect(string(ingress.UID), ingress.Namespace, ingressTLS.SecretName); err != nil {
-			c.er.Eventf(ingress, corev1.EventTypeWarning, constants.ReasonSecretReflectionError, "failed to reflect ingress secret: %v", err)
-			c.logger.Errorf("failed to reflect ingress secret %q: %v", workItem.Key, err)
+	// Make sure that the Ingress resource cannot be removed before it is removed from the target EdgeLB pool.
+	if !ingressDeleted && kubernetesutil.AddFinalizer(ingress, constants.DklbFinalizer) {
+		if ingress, err = c.kubeClient.NetworkingV1().Ingresses(ingress.Namespace).Update(context.TODO(), ingress, metav1.UpdateOptions{}); err != nil {
+			c.logger.Errorf("failed to add finalizer to ingress %q: %v", workItem.Key, err)
 			return err
 		}
 	}
 
+	// Check if we need to reflect any secrets back to DC/OS.
+	// Secrets are not reflected in case the Ingress resource has been deleted, as the Secret resources it references may already be gone (e.g. because its namespace is being deleted) and reflecting them would block the removal of our finalizer.
+	if !ingressDeleted {
+		for _, ingressTLS := range ingress.Spec.TLS {
+			c.logger.Debugf("reflecting ingress secret UID=%s %s/%s", ingress.UID, ingress.Namespace, ingressTLS.SecretName)
+			if err := c.secretsReflector.Reflect(string(ingress.UID), ingress.Namespace, ingressTLS.SecretName); err != nil {
+				c.er.Eventf(ingress, corev1.EventTypeWarning, constants.ReasonSecretReflectionError, "failed to reflect ingress secret: %v", err)
+				c.logger.Errorf("failed to reflect ingress secret %q: %v", workItem.Key, err)
+				return err
+			}
+		}
+
+		// Check if we need to reflect any CA bundles used to verify HTTPS backends or any basic authentication credentials back to DC/OS.
+		// An invalid EdgeLB pool configuration object is reported by the translator, so we just skip this step in that case.
+		if spec, err := translatorapi.GetIngressEdgeLBPoolSpec(ingress); err == nil {
+			for _, caSecretName := range spec.CASecretNames(ingress) {
+				c.logger.Debugf("reflecting ingress ca secret UID=%s %s/%s", ingress.UID, ingress.Namespace, caSecretName)
+				if err := c.secretsReflector.ReflectCA(string(ingress.UID), ingress.Namespace, caSecretName); err != nil {
+					c.er.Eventf(ingress, corev1.EventTypeWarning, constants.ReasonSecretReflectionError, "failed to reflect ingress ca secret: %v", err)
+					c.logger.Errorf("failed to reflect ingress ca secret %q: %v", workItem.Key, err)
+					return err
+				}
+			}
+			for _, basicAuthSecretName := range spec.BasicAuthSecretNames() {
+				c.logger.Debugf("reflecting ingress basic auth secret UID=%s %s/%s", ingress.UID, ingress.Namespace, basicAuthSecretName)
+				if err := c.secretsReflector.ReflectBasicAuth(string(ingress.UID), ingress.Namespace, basicAuthSecretName); err != nil {
+					c.er.Eventf(ingress, corev1.EventTypeWarning, constants.ReasonSecretReflectionError, "failed to reflect ingress basic auth secret: %v", err)
+					c.logger.Errorf("failed to reflect ingress basic auth secret %q: %v", workItem.Key, err)
+					return err
+				}
+			}
+		}
+	}
+
 	// Perform translation of the Ingress resource into an EdgeLB pool.
-	status, err := translator.NewIngressTranslator(ingress, c.kubeCache, c.edgelbManager, c.er).Translate()
+	it := translator.NewIngressTranslator(ingress, c.kubeCache, c.edgelbManager, c.er)
+	status, err := it.Translate()
 	if err != nil {
 		c.er.Eventf(ingress, corev1.EventTypeWarning, constants.ReasonTranslationError, "failed to translate ingress: %v", err)
 		c.logger.Errorf("failed to translate ingress %q: %v", workItem.Key, err)
 		return err
 	}
 
+	// Forget about the EdgeLB pool replaced by the target EdgeLB pool (if any) once the Ingress resource has been removed from it.
+	if ingress.ObjectMeta.DeletionTimestamp == nil && it.ReplacedEdgeLBPool() {
+		delete(ingress.Annotations, constants.DklbReplacedPoolAnnotationKey)
+		if ingress, err = c.kubeClient.NetworkingV1().Ingresses(ingress.Namespace).Update(context.TODO(), ingress, metav1.UpdateOptions{}); err != nil {
+			c.logger.Errorf("failed to update ingress %q: %v", workItem.Key, err)
+			return err
+		}
+	}
+
+	// Release our finalizer now that the Ingress resource has been removed from the target EdgeLB pool.
+	if ingressDeleted {
+		if err := c.removeFinalizer(ingress); err != nil {
+			return err
+		}
+	}
+
 	// Update the status of the Ingress resource if it hasn't been deleted.
 	if ingress.ObjectMeta.DeletionTimestamp == nil && status != nil {
-		ingress.Status = extsv1beta1.IngressStatus{LoadBalancer: *status}
-		if _, err := c.kubeClient.ExtensionsV1beta1().Ingresses(ingress.Namespace).UpdateStatus(ingress); err != nil {
+		ingress.Status = networkingv1.IngressStatus{LoadBalancer: kubernetesutil.IngressLoadBalancerStatus(*status)}
+		if _, err := c.kubeClient.NetworkingV1().Ingresses(ingress.Namespace).UpdateStatus(context.TODO(), ingress, metav1.UpdateOptions{}); err != nil {
 			c.logger.Errorf("failed to update status for ingress %q: %v", workItem.Key, err)
 			return err
 		}
@@ -198,6 +288,54 @@ func (c *IngressController) processQueueItem(workItem WorkItem) error {
 	return nil
 }
 
+// removeFinalizer removes our finalizer from the specified Ingress resource (if present), allowing for its deletion to proceed.
+// The specified Ingress resource is updated in-place.
+func (c *IngressController) removeFinalizer(ingress *networkingv1.Ingress) error {
+	if !kubernetesutil.RemoveFinalizer(ingress, constants.DklbFinalizer) {
+		return nil
+	}
+	updated, err := c.kubeClient.NetworkingV1().Ingresses(ingress.Namespace).Update(context.TODO(), ingress, metav1.UpdateOptions{})
+	if err != nil {
+		c.logger.Errorf("failed to remove finalizer from ingress %q: %v", kubernetesutil.Key(ingress), err)
+		return err
+	}
+	*ingress = *updated
+	return nil
+}
+
+// isEdgeLBIngress returns a value indicating whether the specified Ingress resource is meant to be provisioned by EdgeLB according to its ingress class.
+func (c *IngressController) isEdgeLBIngress(ingress *networkingv1.Ingress) bool {
+	ingressClasses, err := c.kubeCache.GetIngressClasses()
+	if err != nil {
+		c.logger.Errorf("failed to list all ingress classes: %v", err)
+	}
+	return kubernetesutil.IsEdgeLBIngress(ingress, ingressClasses)
+}
+
+// enqueueIngressesReferencingIngressClass enqueues Ingress resources that reference the provided IngressClass resource, as well as Ingress resources that don't reference any IngressClass resource in case the provided one is the default one.
+func (c *IngressController) enqueueIngressesReferencingIngressClass(obj interface{}) {
+	// Recover the IngressClass resource from the tombstone in case it has been deleted.
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	ingressClass, ok := obj.(*networkingv1.IngressClass)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("unexpected object of type %T", obj))
+		return
+	}
+	// Grab a list of all Ingress resources in all namespaces.
+	ingresses, err := c.kubeCache.GetIngresses(metav1.NamespaceAll)
+	if err != nil {
+		c.logger.Errorf("failed to list all ingresses: %v", err)
+		return
+	}
+	for _, ingress := range ingresses {
+		if (ingress.Spec.IngressClassName != nil && *ingress.Spec.IngressClassName == ingressClass.Name) || (ingress.Spec.IngressClassName == nil && kubernetesutil.IsDefaultIngressClass(ingressClass)) {
+			c.base.enqueue(ingress)
+		}
+	}
+}
+
 // enqueueIngressesReferencingService enqueues Ingress resources that reference the provided Service resource.
 func (c *IngressController) enqueueIngressesReferencingService(service *corev1.Service) {
 	// Grab a list of all Ingress resources in the same namespace as the Service resource.
@@ -210,12 +348,62 @@ func (c *IngressController) enqueueIngressesReferencingService(service *corev1.S
 	for _, ingress := range ingresses {
 		obj := ingress
 		// check if ingress is annotated correctly
-		if kubernetesutil.IsEdgeLBIngress(obj) {
-			kubernetesutil.ForEachIngresBackend(obj, func(_, _ *string, backend extsv1beta1.IngressBackend) {
-				if backend.ServiceName == service.Name {
+		if c.isEdgeLBIngress(obj) {
+			kubernetesutil.ForEachIngresBackend(obj, func(_ *string, _ *networkingv1.HTTPIngressPath, backend networkingv1.IngressServiceBackend) {
+				if backend.Name == service.Name {
 					c.base.enqueue(obj)
 				}
 			})
 		}
 	}
 }
+
+// enqueueIngressesReferencingEndpoints enqueues Ingress resources that reference the Service resource associated with the provided Endpoints resource, and for which EdgeLB routes traffic directly to pod IPs.
+// Changes to Endpoints resources are batched so that frequent changes don't cause an update to the target EdgeLB pools for every change.
+func (c *IngressController) enqueueIngressesReferencingEndpoints(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	// Grab a list of all Ingress resources in the same namespace as the Endpoints resource.
+	ingresses, err := c.kubeCache.GetIngresses(namespace)
+	if err != nil {
+		c.logger.Errorf("failed to list all ingresses in namespace %q: %v", namespace, err)
+		return
+	}
+	// Iterate over all Ingress resources in the same namespace, checking whether each one references the associated Service resource and enqueueing it if it does.
+	for _, ingress := range ingresses {
+		obj := ingress
+		if !c.isEdgeLBIngress(obj) {
+			continue
+		}
+		// Endpoints resources change frequently, so we avoid parsing the EdgeLB pool configuration object unless the Ingress resource may reference the associated Service resource.
+		// Canaries are only referenced by the EdgeLB pool configuration object, so its raw value is checked as well.
+		referenced := false
+		kubernetesutil.ForEachIngresBackend(obj, func(_ *string, _ *networkingv1.HTTPIngressPath, backend networkingv1.IngressServiceBackend) {
+			referenced = referenced || backend.Name == name
+		})
+		if !referenced && !strings.Contains(obj.Annotations[constants.DklbConfigAnnotationKey], name) {
+			continue
+		}
+		// An invalid EdgeLB pool configuration object is reported by the translator, so we just skip the Ingress resource in that case.
+		spec, err := translatorapi.GetIngressEdgeLBPoolSpec(obj)
+		if err != nil || !spec.RoutesToPodIPs() {
+			continue
+		}
+		for _, backend := range spec.Backends {
+			for _, canary := range backend.Canaries {
+				referenced = referenced || canary.ServiceName == name
+			}
+		}
+		if referenced {
+			c.base.enqueueAfter(obj, EndpointsBatchPeriod)
+		}
+	}
+}
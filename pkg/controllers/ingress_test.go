@@ -1,23 +1,32 @@
 package controllers
 
 import (
+	"context"
 	"testing"
 
+	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	corev1 "k8s.io/api/core/v1"
-	extsv1beta1 "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/tools/record"
 
 	dklbcache "github.com/mesosphere/dklb/pkg/cache"
+	"github.com/mesosphere/dklb/pkg/cluster"
 	"github.com/mesosphere/dklb/pkg/constants"
+	secretsreflector "github.com/mesosphere/dklb/pkg/secrets_reflector"
+	kubernetesutil "github.com/mesosphere/dklb/pkg/util/kubernetes"
+	"github.com/mesosphere/dklb/pkg/util/pointers"
 	cachetestutil "github.com/mesosphere/dklb/test/util/cache"
+	mockedgelb "github.com/mesosphere/dklb/test/util/edgelb/manager"
+	ingresstestutil "github.com/mesosphere/dklb/test/util/kubernetes/ingress"
+	servicetestutil "github.com/mesosphere/dklb/test/util/kubernetes/service"
 )
 
 func TestIngressController_enqueueIngressesReferecingService(t *testing.T) {
-	dummyIngress := &extsv1beta1.Ingress{
+	dummyIngress := &networkingv1.Ingress{
 		ObjectMeta: metav1.ObjectMeta{
 			Annotations: map[string]string{
 				constants.EdgeLBIngressClassAnnotationKey: constants.EdgeLBIngressClassAnnotationValue,
@@ -25,10 +34,12 @@ func TestIngressController_enqueueIngressesReferecingService(t *testing.T) {
 			Namespace: "namespace-1",
 			Name:      "ingress-1",
 		},
-		Spec: extsv1beta1.IngressSpec{
-			Backend: &extsv1beta1.IngressBackend{
-				ServiceName: "service-1",
-				ServicePort: intstr.FromInt(80),
+		Spec: networkingv1.IngressSpec{
+			DefaultBackend: &networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{
+					Name: "service-1",
+					Port: networkingv1.ServiceBackendPort{Number: 80},
+				},
 			},
 		},
 	}
@@ -42,9 +53,9 @@ func TestIngressController_enqueueIngressesReferecingService(t *testing.T) {
 
 	tests := []struct {
 		description string
-		expected    *extsv1beta1.Ingress
+		expected    *networkingv1.Ingress
 		service     *corev1.Service
-		ingress     *extsv1beta1.Ingress
+		ingress     *networkingv1.Ingress
 	}{
 		{
 			description: "should enqueue ingress: contains required annotation",
@@ -56,15 +67,17 @@ func TestIngressController_enqueueIngressesReferecingService(t *testing.T) {
 			description: "should not enqueue ingress: does not contain required annotation",
 			expected:    nil,
 			service:     dummyService,
-			ingress: &extsv1beta1.Ingress{
+			ingress: &networkingv1.Ingress{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: "namespace-1",
 					Name:      "ingress-1",
 				},
-				Spec: extsv1beta1.IngressSpec{
-					Backend: &extsv1beta1.IngressBackend{
-						ServiceName: "service-1",
-						ServicePort: intstr.FromInt(80),
+				Spec: networkingv1.IngressSpec{
+					DefaultBackend: &networkingv1.IngressBackend{
+						Service: &networkingv1.IngressServiceBackend{
+							Name: "service-1",
+							Port: networkingv1.ServiceBackendPort{Number: 80},
+						},
 					},
 				},
 			},
@@ -76,8 +89,10 @@ func TestIngressController_enqueueIngressesReferecingService(t *testing.T) {
 
 		eventRecorder := record.NewFakeRecorder(10)
 		sharedInformerFactory := cachetestutil.NewFakeSharedInformerFactory(test.ingress)
-		ingressInformer := sharedInformerFactory.Extensions().V1beta1().Ingresses()
+		ingressInformer := sharedInformerFactory.Networking().V1().Ingresses()
+		ingressClassInformer := sharedInformerFactory.Networking().V1().IngressClasses()
 		serviceInformer := sharedInformerFactory.Core().V1().Services()
+		endpointsInformer := sharedInformerFactory.Core().V1().Endpoints()
 		kubeCache := dklbcache.NewInformerBackedResourceCache(sharedInformerFactory)
 		kubeClient := fake.NewSimpleClientset(test.service, test.ingress)
 
@@ -89,7 +104,7 @@ func TestIngressController_enqueueIngressesReferecingService(t *testing.T) {
 
 		fake := newFakeGenericController()
 		ic.base = fake
-		ic.initialize(ingressInformer, serviceInformer)
+		ic.initialize(ingressInformer, ingressClassInformer, serviceInformer, endpointsInformer)
 
 		ic.enqueueIngressesReferencingService(test.service)
 		fake.mutex.Lock()
@@ -101,3 +116,169 @@ func TestIngressController_enqueueIngressesReferecingService(t *testing.T) {
 		}
 	}
 }
+
+func TestIngressController_enqueueIngressesReferencingIngressClass(t *testing.T) {
+	dummyIngressClass := ingresstestutil.DummyEdgeLBIngressClassResource("edgelb")
+
+	tests := []struct {
+		description  string
+		expected     bool
+		ingressClass *networkingv1.IngressClass
+		ingress      *networkingv1.Ingress
+	}{
+		{
+			description:  "should enqueue ingress: references the ingress class",
+			expected:     true,
+			ingressClass: dummyIngressClass,
+			ingress: &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "namespace-1",
+					Name:      "ingress-1",
+				},
+				Spec: networkingv1.IngressSpec{
+					IngressClassName: pointers.NewString(dummyIngressClass.Name),
+				},
+			},
+		},
+		{
+			description:  "should not enqueue ingress: references another ingress class",
+			expected:     false,
+			ingressClass: dummyIngressClass,
+			ingress: &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "namespace-1",
+					Name:      "ingress-1",
+				},
+				Spec: networkingv1.IngressSpec{
+					IngressClassName: pointers.NewString("nginx"),
+				},
+			},
+		},
+		{
+			description:  "should not enqueue ingress: does not reference any ingress class, and the ingress class is not the default one",
+			expected:     false,
+			ingressClass: dummyIngressClass,
+			ingress: &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "namespace-1",
+					Name:      "ingress-1",
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+
+		sharedInformerFactory := cachetestutil.NewFakeSharedInformerFactory(test.ingress)
+		ic := &IngressController{
+			kubeCache: dklbcache.NewInformerBackedResourceCache(sharedInformerFactory),
+		}
+
+		fake := newFakeGenericController()
+		ic.base = fake
+
+		ic.enqueueIngressesReferencingIngressClass(test.ingressClass)
+		fake.mutex.Lock()
+		if test.expected {
+			assert.Equal(t, []interface{}{test.ingress}, fake.queue)
+		} else {
+			assert.Equal(t, 0, len(fake.queue))
+		}
+		fake.mutex.Unlock()
+	}
+}
+
+func TestIngressController_processQueueItemDeletedIngressWithMissingSecret(t *testing.T) {
+	cluster.Name = "test-cluster"
+	// dummyIngress represents an Ingress resource being deleted whose TLS secret no longer exists (e.g. because its namespace is being deleted).
+	deletionTimestamp := metav1.Now()
+	dummyIngress := ingresstestutil.DummyEdgeLBIngressResource("namespace-1", "ingress-1", func(ingress *networkingv1.Ingress) {
+		ingress.DeletionTimestamp = &deletionTimestamp
+		ingress.Finalizers = []string{constants.DklbFinalizer}
+		ingress.Spec.TLS = []networkingv1.IngressTLS{
+			{SecretName: "missing-secret"},
+		}
+	})
+
+	// defaultService represents the Service resource used as the default backend.
+	defaultService := servicetestutil.DummyServiceResource("kube-system", "dklb", func(service *corev1.Service) {
+		service.Spec.Type = corev1.ServiceTypeNodePort
+		service.Spec.Ports = []corev1.ServicePort{
+			{Port: 80, NodePort: 31789},
+		}
+	})
+
+	kubeClient := fake.NewSimpleClientset(dummyIngress)
+	kubeCache := dklbcache.NewInformerBackedResourceCache(cachetestutil.NewFakeSharedInformerFactory(dummyIngress, defaultService))
+	edgelbManager := new(mockedgelb.MockEdgeLBManager)
+	edgelbManager.On("PoolGroup").Return("test-pool-group")
+	edgelbManager.On("GetPool", mock.Anything, mock.Anything).Return(nil, nil)
+
+	ic := &IngressController{
+		kubeClient:       kubeClient,
+		er:               record.NewFakeRecorder(10),
+		kubeCache:        kubeCache,
+		edgelbManager:    edgelbManager,
+		logger:           log.WithField("controller", ingressControllerName),
+		secretsReflector: secretsreflector.New(nil, kubeCache, kubeClient),
+	}
+
+	// Make sure that the missing secret doesn't prevent our finalizer from being removed.
+	assert.NoError(t, ic.processQueueItem(WorkItem{Key: kubernetesutil.Key(dummyIngress)}))
+	ingress, err := kubeClient.NetworkingV1().Ingresses(dummyIngress.Namespace).Get(context.TODO(), dummyIngress.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.False(t, kubernetesutil.HasFinalizer(ingress, constants.DklbFinalizer))
+}
+
+func TestIngressController_processQueueItemPausedDeletedIngress(t *testing.T) {
+	tests := []struct {
+		description       string
+		annotations       map[string]string
+		expectedFinalizer bool
+	}{
+		{
+			description: "should keep the finalizer of a deleted ingress while translation is paused",
+			annotations: map[string]string{
+				constants.DklbPaused: "true",
+			},
+			expectedFinalizer: true,
+		},
+		{
+			description: "should release the finalizer of a deleted ingress when asked to skip cleanup while translation is paused",
+			annotations: map[string]string{
+				constants.DklbPaused:                   "true",
+				constants.DklbSkipCleanupAnnotationKey: "true",
+			},
+			expectedFinalizer: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+
+		deletionTimestamp := metav1.Now()
+		dummyIngress := ingresstestutil.DummyEdgeLBIngressResource("namespace-1", "ingress-1", func(ingress *networkingv1.Ingress) {
+			for key, value := range test.annotations {
+				ingress.Annotations[key] = value
+			}
+			ingress.DeletionTimestamp = &deletionTimestamp
+			ingress.Finalizers = []string{constants.DklbFinalizer}
+		})
+
+		kubeClient := fake.NewSimpleClientset(dummyIngress)
+		// No calls to EdgeLB are expected, as the ingress must not be translated.
+		ic := &IngressController{
+			kubeClient:    kubeClient,
+			er:            record.NewFakeRecorder(10),
+			kubeCache:     dklbcache.NewInformerBackedResourceCache(cachetestutil.NewFakeSharedInformerFactory(dummyIngress)),
+			edgelbManager: new(mockedgelb.MockEdgeLBManager),
+			logger:        log.WithField("controller", ingressControllerName),
+		}
+
+		assert.NoError(t, ic.processQueueItem(WorkItem{Key: kubernetesutil.Key(dummyIngress)}))
+		ingress, err := kubeClient.NetworkingV1().Ingresses(dummyIngress.Namespace).Get(context.TODO(), dummyIngress.Name, metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, test.expectedFinalizer, kubernetesutil.HasFinalizer(ingress, constants.DklbFinalizer))
+	}
+}
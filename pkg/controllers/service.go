@@ -20,7 +20,9 @@ import (
 	"github.com/mesosphere/dklb/pkg/constants"
 	"github.com/mesosphere/dklb/pkg/edgelb/manager"
 	"github.com/mesosphere/dklb/pkg/metrics"
+	secretsreflector "github.com/mesosphere/dklb/pkg/secrets_reflector"
 	"github.com/mesosphere/dklb/pkg/translator"
+	translatorapi "github.com/mesosphere/dklb/pkg/translator/api"
 	kubernetesutil "github.com/mesosphere/dklb/pkg/util/kubernetes"
 )
 
@@ -44,17 +46,20 @@ type ServiceController struct {
 	edgelbManager manager.EdgeLBManager
 	// logger is the logger that the controller will use.
 	logger log.FieldLogger
+	// secretsReflector is the instance used to manage lifecycle of DC/OS secrets
+	secretsReflector secretsreflector.SecretsReflector
 }
 
 // NewServiceController creates a new instance of the EdgeLB service controller.
-func NewServiceController(kubeClient kubernetes.Interface, er record.EventRecorder, serviceInformer corev1informers.ServiceInformer, kubeCache dklbcache.KubernetesResourceCache, edgelbManager manager.EdgeLBManager) *ServiceController {
+func NewServiceController(kubeClient kubernetes.Interface, er record.EventRecorder, serviceInformer corev1informers.ServiceInformer, endpointsInformer corev1informers.EndpointsInformer, kubeCache dklbcache.KubernetesResourceCache, edgelbManager manager.EdgeLBManager, secretsReflector secretsreflector.SecretsReflector) *ServiceController {
 	// Create a new instance of the service controller with the specified name and threadiness.
 	c := &ServiceController{
-		kubeClient:    kubeClient,
-		er:            er,
-		kubeCache:     kubeCache,
-		edgelbManager: edgelbManager,
-		logger:        log.WithField("controller", serviceControllerName),
+		kubeClient:       kubeClient,
+		er:               er,
+		kubeCache:        kubeCache,
+		edgelbManager:    edgelbManager,
+		logger:           log.WithField("controller", serviceControllerName),
+		secretsReflector: secretsReflector,
 	}
 	// Create a new instance of the service controller with the specified name and threadiness.
 	// Make processQueueItem the handler for items popped out of the work queue.
@@ -62,14 +67,14 @@ func NewServiceController(kubeClient kubernetes.Interface, er record.EventRecord
 
 	// Setup an event handler to inform us when Service resources change.
 	// A Service resource is enqueued in the following scenarios:
-	// * It was listed ("ADDED") and is of type "LoadBalancer".
-	// * It was updated ("MODIFIED") and either the old or the new types (or both) are of type "LoadBalancer".
-	//   * This allows for handling the cases in which the type of a service changes.
-	// * It was deleted ("DELETED") and was of type "LoadBalancer".
+	// * It was listed ("ADDED") and is meant to be provisioned by EdgeLB (or still holds our finalizer).
+	// * It was updated ("MODIFIED") and either the old or the new versions (or both) are meant to be provisioned by EdgeLB (or the new version still holds our finalizer).
+	//   * This allows for handling the cases in which the type, load balancer class or opt-in annotation of a service changes.
+	// * It was deleted ("DELETED") and was meant to be provisioned by EdgeLB.
 	serviceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
 			svc := obj.(*corev1.Service)
-			if svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+			if !kubernetesutil.IsEdgeLBService(svc) && !kubernetesutil.HasFinalizer(svc, constants.DklbFinalizer) {
 				return
 			}
 			c.base.enqueue(svc)
@@ -77,19 +82,32 @@ func NewServiceController(kubeClient kubernetes.Interface, er record.EventRecord
 		UpdateFunc: func(oldObj, newObj interface{}) {
 			oldSvc := oldObj.(*corev1.Service)
 			newSvc := newObj.(*corev1.Service)
-			if oldSvc.Spec.Type != corev1.ServiceTypeLoadBalancer && newSvc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+			if !kubernetesutil.IsEdgeLBService(oldSvc) && !kubernetesutil.IsEdgeLBService(newSvc) && !kubernetesutil.HasFinalizer(newSvc, constants.DklbFinalizer) {
 				return
 			}
 			c.base.enqueue(newSvc)
 		},
 		DeleteFunc: func(obj interface{}) {
 			svc := obj.(*corev1.Service)
-			if svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+			if !kubernetesutil.IsEdgeLBService(svc) {
 				return
 			}
 			c.base.enqueueTombstone(svc)
 		},
 	})
+	// Setup an event handler to inform us when Endpoints resources change.
+	// This allows us to enqueue the associated Service resource in case EdgeLB routes traffic directly to the IPs of its pods.
+	endpointsInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueueServiceForEndpoints(obj)
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			c.enqueueServiceForEndpoints(obj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			c.enqueueServiceForEndpoints(obj)
+		},
+	})
 
 	// Return the instance created above.
 	return c
@@ -135,28 +153,127 @@ func (c *ServiceController) processQueueItem(workItem WorkItem) error {
 		service.ObjectMeta.DeletionTimestamp = &deletionTimestamp
 	}
 
-	// Return immediately if translation is paused for the current Ingress resource.
+	// serviceDeleted holds whether the Service resource has been deleted (or is not meant to be provisioned by EdgeLB anymore), in which case it must be removed from the target EdgeLB pool.
+	serviceDeleted := service.ObjectMeta.DeletionTimestamp != nil || !kubernetesutil.IsEdgeLBService(service)
+
+	// Release our finalizer without removing the Service resource from the target EdgeLB pool in case we've been asked to do so (e.g. because EdgeLB is unreachable).
+	if service.ObjectMeta.DeletionTimestamp != nil && service.Annotations[constants.DklbSkipCleanupAnnotationKey] == strconv.FormatBool(true) {
+		c.er.Eventf(service, corev1.EventTypeWarning, constants.ReasonCleanupSkipped, "skipping the removal of the service from the edgelb pool as requested")
+		c.logger.Warnf("skipping the removal of %q from the edgelb pool as requested", kubernetesutil.Key(service))
+		return c.removeFinalizer(service)
+	}
+
+	// Return immediately if translation is paused for the current Service resource.
+	// In case the Service resource has been deleted, we keep our finalizer so that it is only released after the Service resource is removed from the target EdgeLB pool (i.e. once translation is resumed).
 	if service.Annotations[constants.DklbPaused] == strconv.FormatBool(true) {
 		c.er.Eventf(service, corev1.EventTypeWarning, constants.ReasonTranslationPaused, "translation is paused for the resource")
 		c.logger.Warnf("skipping translation of %q as translation is paused for the resource", kubernetesutil.Key(service))
 		return nil
 	}
 
+	// Make sure that the Service resource cannot be removed before it is removed from the target EdgeLB pool.
+	if !serviceDeleted && kubernetesutil.AddFinalizer(service, constants.DklbFinalizer) {
+		if service, err = c.kubeClient.CoreV1().Services(service.Namespace).Update(context.TODO(), service, metav1.UpdateOptions{}); err != nil {
+			c.logger.Errorf("failed to add finalizer to service %q: %v", workItem.Key, err)
+			return err
+		}
+	}
+
+	// Warn about service ports that cannot be exposed by EdgeLB (e.g. UDP ports), as these are ignored during translation.
+	if !serviceDeleted {
+		unsupported, _ := kubernetesutil.UnsupportedServicePorts(service)
+		for _, port := range unsupported {
+			c.er.Eventf(service, corev1.EventTypeWarning, constants.ReasonUnsupportedServicePort, "service port %d uses the %s protocol, which is not supported by edgelb, and will be ignored", port.Port, port.Protocol)
+		}
+	}
+
+	// Check if we need to reflect any secrets used to terminate TLS connections back to DC/OS.
+	// An invalid EdgeLB pool configuration object is reported by the translator, so we just skip this step in that case.
+	if spec, err := translatorapi.GetServiceEdgeLBPoolSpec(service); err == nil && service.ObjectMeta.DeletionTimestamp == nil {
+		for _, tlsSecretName := range spec.TLSSecretNames() {
+			c.logger.Debugf("reflecting service secret UID=%s %s/%s", service.UID, service.Namespace, tlsSecretName)
+			if err := c.secretsReflector.Reflect(string(service.UID), service.Namespace, tlsSecretName); err != nil {
+				c.er.Eventf(service, corev1.EventTypeWarning, constants.ReasonSecretReflectionError, "failed to reflect service secret: %v", err)
+				c.logger.Errorf("failed to reflect service secret %q: %v", workItem.Key, err)
+				return err
+			}
+		}
+	}
+
 	// Perform translation of the Service resource into an EdgeLB pool.
-	status, err := translator.NewServiceTranslator(service, c.kubeCache, c.edgelbManager).Translate()
+	st := translator.NewServiceTranslator(service, c.kubeCache, c.edgelbManager, c.er)
+	status, err := st.Translate()
 	if err != nil {
 		c.er.Eventf(service, corev1.EventTypeWarning, constants.ReasonTranslationError, "failed to translate service: %v", err)
 		c.logger.Errorf("failed to translate service %q: %v", workItem.Key, err)
 		return err
 	}
 
+	// Forget about the EdgeLB pool replaced by the target EdgeLB pool (if any) once the Service resource has been removed from it.
+	if service.ObjectMeta.DeletionTimestamp == nil && st.ReplacedEdgeLBPool() {
+		delete(service.Annotations, constants.DklbReplacedPoolAnnotationKey)
+		if service, err = c.kubeClient.CoreV1().Services(service.Namespace).Update(context.TODO(), service, metav1.UpdateOptions{}); err != nil {
+			c.logger.Errorf("failed to update service %q: %v", workItem.Key, err)
+			return err
+		}
+	}
+
+	// Release our finalizer now that the Service resource has been removed from the target EdgeLB pool.
+	if serviceDeleted {
+		if err := c.removeFinalizer(service); err != nil {
+			return err
+		}
+	}
+
 	// Update the status of the Service resource if it hasn't been deleted.
-	if service.ObjectMeta.DeletionTimestamp == nil && status != nil {
+	// Service resources of type "NodePort" are not expected to report a load balancer status, so their status is left untouched.
+	if service.ObjectMeta.DeletionTimestamp == nil && service.Spec.Type == corev1.ServiceTypeLoadBalancer && status != nil {
 		service.Status = corev1.ServiceStatus{LoadBalancer: *status}
-		if _, err := c.kubeClient.CoreV1().Services(service.Namespace).UpdateStatus(service); err != nil {
+		if _, err := c.kubeClient.CoreV1().Services(service.Namespace).UpdateStatus(context.TODO(), service, metav1.UpdateOptions{}); err != nil {
 			c.logger.Errorf("failed to update status for service %q: %v", workItem.Key, err)
 			return err
 		}
 	}
 	return nil
 }
+
+// removeFinalizer removes our finalizer from the specified Service resource (if present), allowing for its deletion to proceed.
+// The specified Service resource is updated in-place.
+func (c *ServiceController) removeFinalizer(service *corev1.Service) error {
+	if !kubernetesutil.RemoveFinalizer(service, constants.DklbFinalizer) {
+		return nil
+	}
+	updated, err := c.kubeClient.CoreV1().Services(service.Namespace).Update(context.TODO(), service, metav1.UpdateOptions{})
+	if err != nil {
+		c.logger.Errorf("failed to remove finalizer from service %q: %v", kubernetesutil.Key(service), err)
+		return err
+	}
+	*service = *updated
+	return nil
+}
+
+// enqueueServiceForEndpoints enqueues the Service resource associated with the provided Endpoints resource in case EdgeLB routes traffic directly to the IPs of its pods.
+// Changes to Endpoints resources are batched so that frequent changes don't cause an update to the target EdgeLB pool for every change.
+func (c *ServiceController) enqueueServiceForEndpoints(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	// The Endpoints resource has the same namespace and name as the associated Service resource.
+	// In case the Service resource does not exist (anymore), there is nothing to do.
+	service, err := c.kubeCache.GetService(namespace, name)
+	if err != nil || !kubernetesutil.IsEdgeLBService(service) {
+		return
+	}
+	// An invalid EdgeLB pool configuration object is reported by the translator, so we just skip the Service resource in that case.
+	if spec, err := translatorapi.GetServiceEdgeLBPoolSpec(service); err != nil || !spec.RoutesToPodIPs() {
+		return
+	}
+	c.base.enqueueAfter(service, EndpointsBatchPeriod)
+}
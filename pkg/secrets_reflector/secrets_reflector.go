@@ -11,6 +11,7 @@ import (
 	"github.com/dcos/client-go/dcos"
 	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 
 	dklbcache "github.com/mesosphere/dklb/pkg/cache"
@@ -19,6 +20,12 @@ import (
 )
 
 const (
+	// basicAuthKey is the key of the Kubernetes secret field holding htpasswd-formatted credentials.
+	basicAuthKey = "auth"
+	// basicAuthSHAPrefix is the prefix of an htpasswd password hashed using SHA-1.
+	basicAuthSHAPrefix = "{SHA}"
+	// caCertificateKey is the key of the Kubernetes secret field holding a CA bundle.
+	caCertificateKey   = "ca.crt"
 	defaultSecretStore = "default"
 	defaultTimeout     = 5 * time.Second
 )
@@ -26,6 +33,8 @@ const (
 // SecretsReflector defines the interface exposed by this package.
 type SecretsReflector interface {
 	Reflect(uid, namespace, name string) error
+	ReflectBasicAuth(uid, namespace, name string) error
+	ReflectCA(uid, namespace, name string) error
 }
 
 // DCOSSecretsAPI defines the interface required by the SecretsReflector to manage DC/OS secrets.
@@ -69,6 +78,40 @@ func (s *secretsReflector) Reflect(uid, namespace, name string) error {
 	return s.reflect(uid, kubeSecret, dcosSecret)
 }
 
+// ReflectCA reads Kubernetes secret with the provided namespace and name, translates the CA bundle it contains
+// to a DC/OS secret and checks if it needs to be recreated in DC/OS.
+func (s *secretsReflector) ReflectCA(uid, namespace, name string) error {
+	// Get the secret from Kubernetes
+	kubeSecret, err := s.kubeCache.GetSecret(namespace, name)
+	if err != nil {
+		return fmt.Errorf("failed to get secret \"%s/%s\": %s", namespace, name, err)
+	}
+	// Translate Kubernetes secret to a dcos secret
+	dcosSecret, err := s.translateCA(kubeSecret)
+	if err != nil {
+		return fmt.Errorf("failed to translate secret: %s", err)
+	}
+	// Check if we need to update/create the dcos secret
+	return s.reflectCA(uid, kubeSecret, dcosSecret)
+}
+
+// ReflectBasicAuth reads Kubernetes secret with the provided namespace and name, translates the htpasswd-formatted credentials it contains
+// to a DC/OS secret and checks if it needs to be recreated in DC/OS.
+func (s *secretsReflector) ReflectBasicAuth(uid, namespace, name string) error {
+	// Get the secret from Kubernetes
+	kubeSecret, err := s.kubeCache.GetSecret(namespace, name)
+	if err != nil {
+		return fmt.Errorf("failed to get secret \"%s/%s\": %s", namespace, name, err)
+	}
+	// Translate Kubernetes secret to a dcos secret
+	dcosSecret, err := s.translateBasicAuth(kubeSecret)
+	if err != nil {
+		return fmt.Errorf("failed to translate secret: %s", err)
+	}
+	// Check if we need to update/create the dcos secret
+	return s.reflectAs(ComputeDCOSBasicAuthSecretName(uid, kubeSecret.Name), constants.DklbBasicAuthSecretAnnotationKey, kubeSecret, dcosSecret)
+}
+
 // translate Kubernetes secret kubeSecret to structure expected by DC/OS or an error if it failed.
 func (s *secretsReflector) translate(kubeSecret *corev1.Secret) (*dcos.SecretsV1Secret, error) {
 	crt, ok := kubeSecret.Data[corev1.TLSCertKey]
@@ -93,19 +136,80 @@ func (s *secretsReflector) translate(kubeSecret *corev1.Secret) (*dcos.SecretsV1
 	return dcosSecret, nil
 }
 
+// translateCA translates the CA bundle in Kubernetes secret kubeSecret to structure expected by DC/OS or an error if it failed.
+func (s *secretsReflector) translateCA(kubeSecret *corev1.Secret) (*dcos.SecretsV1Secret, error) {
+	ca, ok := kubeSecret.Data[caCertificateKey]
+	if !ok {
+		err := fmt.Errorf("invalid secret: \"%s/%s\" does not contain %s field", kubeSecret.Namespace, kubeSecret.Name, caCertificateKey)
+		return nil, err
+	}
+	dcosSecret := &dcos.SecretsV1Secret{
+		Value: string(ca),
+	}
+	return dcosSecret, nil
+}
+
+// translateBasicAuth translates the htpasswd-formatted credentials in Kubernetes secret kubeSecret to structure expected by DC/OS or an error if it failed.
+// The resulting DC/OS secret is an HAProxy map file associating each user with the base64-encoded SHA-1 hash of its password.
+// Only SHA-1 hashed passwords (i.e. "htpasswd -s") are supported, as HAProxy cannot verify passwords hashed using other algorithms on its own.
+func (s *secretsReflector) translateBasicAuth(kubeSecret *corev1.Secret) (*dcos.SecretsV1Secret, error) {
+	auth, ok := kubeSecret.Data[basicAuthKey]
+	if !ok {
+		err := fmt.Errorf("invalid secret: \"%s/%s\" does not contain %s field", kubeSecret.Namespace, kubeSecret.Name, basicAuthKey)
+		return nil, err
+	}
+	var sb strings.Builder
+	for idx, line := range strings.Split(string(auth), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || strings.ContainsAny(parts[0], " \t") || !strings.HasPrefix(parts[1], basicAuthSHAPrefix) {
+			err := fmt.Errorf("invalid secret: line %d of the %s field of \"%s/%s\" is not of the form \"<user>:%s<hash>\"", idx+1, basicAuthKey, kubeSecret.Namespace, kubeSecret.Name, basicAuthSHAPrefix)
+			return nil, err
+		}
+		sb.WriteString(parts[0])
+		sb.WriteString(" ")
+		sb.WriteString(strings.TrimPrefix(parts[1], basicAuthSHAPrefix))
+		sb.WriteString("\n")
+	}
+	dcosSecret := &dcos.SecretsV1Secret{
+		Value: sb.String(),
+	}
+	return dcosSecret, nil
+}
+
+// ValidateBasicAuthSecret returns an error in case the htpasswd-formatted credentials in Kubernetes secret kubeSecret cannot be reflected.
+// This is the case whenever any of the passwords is not hashed using SHA-1 (i.e. "htpasswd -s"), which notably includes the formats produced by default by "htpasswd" (i.e. MD5 "apr1", bcrypt and crypt).
+func ValidateBasicAuthSecret(kubeSecret *corev1.Secret) error {
+	_, err := (&secretsReflector{}).translateBasicAuth(kubeSecret)
+	return err
+}
+
 // reflect checks if Kubernetes secret was updated by verifying if the MD5 hash of certificate
 // and private key changed, and, if required proceeds to re-create the DC/OS secret.
 func (s *secretsReflector) reflect(uid string, kubeSecret *corev1.Secret, dcosSecret *dcos.SecretsV1Secret) error {
+	return s.reflectAs(ComputeDCOSSecretName(uid, kubeSecret.Name), constants.DklbSecretAnnotationKey, kubeSecret, dcosSecret)
+}
+
+// reflectCA checks if Kubernetes secret was updated by verifying if the MD5 hash of the CA bundle
+// changed, and, if required proceeds to re-create the DC/OS secret.
+func (s *secretsReflector) reflectCA(uid string, kubeSecret *corev1.Secret, dcosSecret *dcos.SecretsV1Secret) error {
+	return s.reflectAs(ComputeDCOSCASecretName(uid, kubeSecret.Name), constants.DklbCASecretAnnotationKey, kubeSecret, dcosSecret)
+}
+
+// reflectAs checks if the MD5 hash of dcosSecret differs from the one recorded in the hashAnnotationKey annotation
+// of kubeSecret, and, if required proceeds to re-create the DC/OS secret named dcosSecretName.
+func (s *secretsReflector) reflectAs(dcosSecretName, hashAnnotationKey string, kubeSecret *corev1.Secret, dcosSecret *dcos.SecretsV1Secret) error {
 	hashRaw := md5.Sum([]byte(dcosSecret.Value))
 	hash := fmt.Sprintf("%x", hashRaw)
-	expectedHash, withPreviousAnnotation := kubeSecret.Annotations[constants.DklbSecretAnnotationKey]
+	expectedHash, withPreviousAnnotation := kubeSecret.Annotations[hashAnnotationKey]
 	// Hash did not change so we can exit now
 	if len(dcosSecret.Value) > 0 && withPreviousAnnotation && hash == expectedHash {
 		s.logger.Infof("no changes to secret \"%s/%s\" detected, skipping update", kubeSecret.Namespace, kubeSecret.Name)
 		return nil
 	}
-	// Generate the DC/OS secret name
-	dcosSecretName := ComputeDCOSSecretName(uid, kubeSecret.Name)
 	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
 	defer cancel()
 	// Check if we need to update or create the DC/OS secret
@@ -135,9 +239,9 @@ func (s *secretsReflector) reflect(uid string, kubeSecret *corev1.Secret, dcosSe
 	if kubeSecret.Annotations == nil {
 		kubeSecret.Annotations = make(map[string]string)
 	}
-	kubeSecret.Annotations[constants.DklbSecretAnnotationKey] = hash
+	kubeSecret.Annotations[hashAnnotationKey] = hash
 	s.logger.Infof("detected changes to secret \"%s/%s\": updating annotation", kubeSecret.Namespace, kubeSecret.Name)
-	if _, err := s.kubeClient.CoreV1().Secrets(kubeSecret.Namespace).Update(kubeSecret); err != nil {
+	if _, err := s.kubeClient.CoreV1().Secrets(kubeSecret.Namespace).Update(ctx, kubeSecret, metav1.UpdateOptions{}); err != nil {
 		return fmt.Errorf("failed to update Kubernetes secret \"%s/%s\": %s", kubeSecret.Namespace, kubeSecret.Name, err)
 	}
 	return nil
@@ -147,6 +251,16 @@ func ComputeDCOSSecretName(uid, kubeSecretName string) string {
 	return fmt.Sprintf("%s__%s", uid, kubeSecretName)
 }
 
+// ComputeDCOSBasicAuthSecretName computes the name of the DC/OS secret holding the credentials contained in the specified Kubernetes secret.
+func ComputeDCOSBasicAuthSecretName(uid, kubeSecretName string) string {
+	return fmt.Sprintf("%s__%s__auth", uid, kubeSecretName)
+}
+
+// ComputeDCOSCASecretName computes the name of the DC/OS secret holding the CA bundle contained in the specified Kubernetes secret.
+func ComputeDCOSCASecretName(uid, kubeSecretName string) string {
+	return fmt.Sprintf("%s__%s__ca", uid, kubeSecretName)
+}
+
 func ComputeDCOSSecretFileName(dcosSecretName string) string {
 	return dklbstrings.ReplaceForwardSlashes(dcosSecretName, "_")
 }
@@ -301,6 +301,157 @@ func TestSecretReflector(t *testing.T) {
 	}
 }
 
+func TestSecretReflector_ReflectCA(t *testing.T) {
+	caKubeSecret := secrettestutil.DummySecretResource("namespace-1", "ca-1", func(secret *corev1.Secret) {
+		secret.Data[caCertificateKey] = []byte("aGVsbG8K") // hello
+	})
+
+	tests := []struct {
+		description       string
+		dcosSecretsClient DCOSSecretsClient
+		expectedError     error
+		kubeSecret        *corev1.Secret
+	}{
+		{
+			description: "should reflect a ca bundle",
+			dcosSecretsClient: &fakeDCOSSecretsClient{
+				OnCreate: func(path string) error {
+					if path != "uid__ca-1__ca" {
+						return fmt.Errorf("error expected 'uid__ca-1__ca' got '%s'", path)
+					}
+					return nil
+				},
+			},
+			expectedError: nil,
+			kubeSecret:    caKubeSecret,
+		},
+		{
+			description:       "should fail to translate a secret without a ca bundle",
+			dcosSecretsClient: newFakeDCOSSecretsClient(),
+			expectedError:     errors.New("failed to translate secret: invalid secret: \"namespace-1/name-1\" does not contain ca.crt field"),
+			kubeSecret:        defaultTestKubeSecret,
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+
+		sr := secretsReflector{
+			dcosSecretsClient: test.dcosSecretsClient,
+			logger:            defaultTestLogger,
+			kubeCache:         dklbcache.NewInformerBackedResourceCache(cachetestutil.NewFakeSharedInformerFactory(test.kubeSecret)),
+			kubeClient:        fake.NewSimpleClientset(test.kubeSecret),
+		}
+		err := sr.ReflectCA("uid", test.kubeSecret.Namespace, test.kubeSecret.Name)
+
+		assert.Equal(t, test.expectedError, err)
+	}
+}
+
+func TestSecretReflector_ReflectBasicAuth(t *testing.T) {
+	authKubeSecret := secrettestutil.DummySecretResource("namespace-1", "auth-1", func(secret *corev1.Secret) {
+		secret.Data[basicAuthKey] = []byte("# admins\nalice:{SHA}qUqP5cyxm6YcTAhz05Hph5gvu9M=\n\nbob:{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g=\n")
+	})
+	bcryptKubeSecret := secrettestutil.DummySecretResource("namespace-1", "auth-2", func(secret *corev1.Secret) {
+		secret.Data[basicAuthKey] = []byte("alice:$2y$05$6Qz9Tq5pUoXnQ5J9wX7x8e")
+	})
+
+	tests := []struct {
+		description       string
+		dcosSecretsClient DCOSSecretsClient
+		expectedError     error
+		kubeSecret        *corev1.Secret
+	}{
+		{
+			description: "should reflect sha-1 hashed credentials",
+			dcosSecretsClient: &fakeDCOSSecretsClient{
+				OnCreate: func(path string) error {
+					if path != "uid__auth-1__auth" {
+						return fmt.Errorf("error expected 'uid__auth-1__auth' got '%s'", path)
+					}
+					return nil
+				},
+			},
+			expectedError: nil,
+			kubeSecret:    authKubeSecret,
+		},
+		{
+			description:       "should fail to translate credentials not hashed with sha-1",
+			dcosSecretsClient: newFakeDCOSSecretsClient(),
+			expectedError:     errors.New("failed to translate secret: invalid secret: line 1 of the auth field of \"namespace-1/auth-2\" is not of the form \"<user>:{SHA}<hash>\""),
+			kubeSecret:        bcryptKubeSecret,
+		},
+		{
+			description:       "should fail to translate a secret without credentials",
+			dcosSecretsClient: newFakeDCOSSecretsClient(),
+			expectedError:     errors.New("failed to translate secret: invalid secret: \"namespace-1/name-1\" does not contain auth field"),
+			kubeSecret:        defaultTestKubeSecret,
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+
+		sr := secretsReflector{
+			dcosSecretsClient: test.dcosSecretsClient,
+			logger:            defaultTestLogger,
+			kubeCache:         dklbcache.NewInformerBackedResourceCache(cachetestutil.NewFakeSharedInformerFactory(test.kubeSecret)),
+			kubeClient:        fake.NewSimpleClientset(test.kubeSecret),
+		}
+		err := sr.ReflectBasicAuth("uid", test.kubeSecret.Namespace, test.kubeSecret.Name)
+
+		assert.Equal(t, test.expectedError, err)
+	}
+}
+
+func TestSecretReflector_translateBasicAuth(t *testing.T) {
+	kubeSecret := secrettestutil.DummySecretResource("namespace-1", "auth-1", func(secret *corev1.Secret) {
+		secret.Data[basicAuthKey] = []byte("alice:{SHA}qUqP5cyxm6YcTAhz05Hph5gvu9M=\r\nbob:{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g=")
+	})
+
+	dcosSecret, err := (&secretsReflector{}).translateBasicAuth(kubeSecret)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice qUqP5cyxm6YcTAhz05Hph5gvu9M=\nbob W6ph5Mm5Pz8GgiULbPgzG37mj9g=\n", dcosSecret.Value)
+}
+
+// TestValidateBasicAuthSecret tests the "ValidateBasicAuthSecret" function.
+func TestValidateBasicAuthSecret(t *testing.T) {
+	tests := []struct {
+		description   string
+		auth          string
+		expectedError bool
+	}{
+		{
+			description: "should accept sha-1 hashed passwords",
+			auth:        "alice:{SHA}qUqP5cyxm6YcTAhz05Hph5gvu9M=\n",
+		},
+		{
+			description:   "should reject md5 (apr1) hashed passwords",
+			auth:          "alice:$apr1$4Bfnb3nx$ZqYTSqHGVqbG2z5Xh8GzX/\n",
+			expectedError: true,
+		},
+		{
+			description:   "should reject bcrypt hashed passwords",
+			auth:          "alice:$2y$05$6Qz9Tq5pUoXnQ5J9wX7x8e\n",
+			expectedError: true,
+		},
+		{
+			description:   "should reject crypt hashed passwords",
+			auth:          "alice:rl0qLVtsY0vhw\n",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+		kubeSecret := secrettestutil.DummySecretResource("namespace-1", "auth-1", func(secret *corev1.Secret) {
+			secret.Data[basicAuthKey] = []byte(test.auth)
+		})
+		err := ValidateBasicAuthSecret(kubeSecret)
+		assert.Equal(t, test.expectedError, err != nil)
+	}
+}
+
 // Mostly for the test coverage increase :)
 func TestSecretReflectorNew(t *testing.T) {
 	t.Log("test case: constructor")
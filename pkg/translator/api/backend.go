@@ -0,0 +1,125 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/mesosphere/dklb/pkg/constants"
+)
+
+var (
+	// balanceHeaderRegex is the regular expression used to validate the "hdr(<name>)" balance algorithm.
+	balanceHeaderRegex = regexp.MustCompile(`^hdr\([A-Za-z0-9-]+\)$`)
+)
+
+// BaseEdgeLBPoolBackendSpec contains EdgeLB backend configuration properties that are common to both Service and Ingress resources.
+type BaseEdgeLBPoolBackendSpec struct {
+	// Balance is the load-balancing algorithm (one of "roundrobin", "leastconn", "source", "uri" or "hdr(<name>)") to use for the EdgeLB backend.
+	Balance *string `yaml:"balance"`
+	// HealthCheck contains the specification of the health-checks performed against the EdgeLB backend's servers.
+	HealthCheck *EdgeLBBackendHealthCheckSpec `yaml:"healthCheck"`
+	// SendProxy is the version of the PROXY protocol (one of "v1" or "v2") to use in order to pass client connection information to the EdgeLB backend's servers.
+	// If not specified, the PROXY protocol is not used.
+	SendProxy *string `yaml:"sendProxy"`
+	// Timeouts contains the specification of the timeouts to use for the EdgeLB backend.
+	// Any value specified here takes precedence over the corresponding pool-level value.
+	Timeouts *EdgeLBTimeoutsSpec `yaml:"timeouts"`
+}
+
+// EdgeLBBackendHealthCheckSpec contains the specification of the health-checks performed against the servers of a given EdgeLB backend.
+type EdgeLBBackendHealthCheckSpec struct {
+	// Path is the HTTP path to request when performing health-checks.
+	// If not specified, health-checks are performed at the TCP level.
+	Path *string `yaml:"path"`
+	// Interval is the interval between two consecutive health-checks (e.g. "2s").
+	Interval *string `yaml:"interval"`
+	// Rise is the number of consecutive successful health-checks after which a server is considered healthy.
+	Rise *int32 `yaml:"rise"`
+	// Fall is the number of consecutive failed health-checks after which a server is considered unhealthy.
+	Fall *int32 `yaml:"fall"`
+	// Timeout is the maximum amount of time to wait for a health-check to complete (e.g. "1s").
+	Timeout *string `yaml:"timeout"`
+}
+
+// BalanceOrDefault returns the load-balancing algorithm to use for the EdgeLB backend, falling back to the default one in case none was specified.
+func (o *BaseEdgeLBPoolBackendSpec) BalanceOrDefault() string {
+	if o == nil || o.Balance == nil || *o.Balance == "" {
+		return constants.EdgeLBBackendBalanceLeastConnections
+	}
+	return *o.Balance
+}
+
+// Validate checks whether the current object is valid.
+func (o *BaseEdgeLBPoolBackendSpec) Validate() error {
+	if o == nil {
+		return nil
+	}
+	if o.Balance != nil {
+		if err := isValidBalance(*o.Balance); err != nil {
+			return err
+		}
+	}
+	if o.HealthCheck != nil {
+		if err := o.HealthCheck.validate(); err != nil {
+			return fmt.Errorf(".healthCheck%v", err)
+		}
+	}
+	if o.SendProxy != nil && *o.SendProxy != ProxyProtocolV1 && *o.SendProxy != ProxyProtocolV2 {
+		return fmt.Errorf(".sendProxy %q must be either %q or %q", *o.SendProxy, ProxyProtocolV1, ProxyProtocolV2)
+	}
+	if o.Timeouts != nil {
+		if err := o.Timeouts.validate(); err != nil {
+			return fmt.Errorf(".timeouts%v", err)
+		}
+	}
+	return nil
+}
+
+// validate checks whether the current object is valid.
+func (o *EdgeLBBackendHealthCheckSpec) validate() error {
+	if o.Path != nil && (len(*o.Path) == 0 || (*o.Path)[0] != '/') {
+		return fmt.Errorf(".path %q must start with \"/\"", *o.Path)
+	}
+	if o.Interval != nil {
+		if err := isValidPositiveDuration(*o.Interval); err != nil {
+			return fmt.Errorf(".interval %v", err)
+		}
+	}
+	if o.Rise != nil && *o.Rise <= 0 {
+		return fmt.Errorf(".rise %d must be positive", *o.Rise)
+	}
+	if o.Fall != nil && *o.Fall <= 0 {
+		return fmt.Errorf(".fall %d must be positive", *o.Fall)
+	}
+	if o.Timeout != nil {
+		if err := isValidPositiveDuration(*o.Timeout); err != nil {
+			return fmt.Errorf(".timeout %v", err)
+		}
+	}
+	return nil
+}
+
+// isValidBalance returns an error if balance is not a supported load-balancing algorithm.
+func isValidBalance(balance string) error {
+	switch balance {
+	case "roundrobin", constants.EdgeLBBackendBalanceLeastConnections, constants.EdgeLBBackendBalanceSource, "uri":
+		return nil
+	}
+	if balanceHeaderRegex.MatchString(balance) {
+		return nil
+	}
+	return fmt.Errorf(".balance %q is not a valid balance algorithm", balance)
+}
+
+// isValidPositiveDuration returns an error if value is not a valid, positive duration.
+func isValidPositiveDuration(value string) error {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("%q is not a valid duration", value)
+	}
+	if d <= 0 {
+		return fmt.Errorf("%q must be positive", value)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mesosphere/dklb/pkg/util/pointers"
+)
+
+func TestBaseEdgeLBPoolBackendSpec_Validate(t *testing.T) {
+	tests := []struct {
+		description   string
+		spec          *BaseEdgeLBPoolBackendSpec
+		expectedError bool
+	}{
+		{
+			description: "should accept an empty spec",
+			spec:        nil,
+		},
+		{
+			description: "should accept a header-based balance algorithm",
+			spec: &BaseEdgeLBPoolBackendSpec{
+				Balance: pointers.NewString("hdr(X-Tenant)"),
+			},
+		},
+		{
+			description: "should reject an unknown balance algorithm",
+			spec: &BaseEdgeLBPoolBackendSpec{
+				Balance: pointers.NewString("random"),
+			},
+			expectedError: true,
+		},
+		{
+			description: "should accept v2 of the proxy protocol",
+			spec: &BaseEdgeLBPoolBackendSpec{
+				SendProxy: pointers.NewString("v2"),
+			},
+		},
+		{
+			description: "should reject an unknown version of the proxy protocol",
+			spec: &BaseEdgeLBPoolBackendSpec{
+				SendProxy: pointers.NewString("v3"),
+			},
+			expectedError: true,
+		},
+		{
+			description: "should accept a complete health-check",
+			spec: &BaseEdgeLBPoolBackendSpec{
+				HealthCheck: &EdgeLBBackendHealthCheckSpec{
+					Path:     pointers.NewString("/healthz"),
+					Interval: pointers.NewString("5s"),
+					Rise:     pointers.NewInt32(2),
+					Fall:     pointers.NewInt32(3),
+					Timeout:  pointers.NewString("500ms"),
+				},
+			},
+		},
+		{
+			description: "should reject a relative health-check path",
+			spec: &BaseEdgeLBPoolBackendSpec{
+				HealthCheck: &EdgeLBBackendHealthCheckSpec{
+					Path: pointers.NewString("healthz"),
+				},
+			},
+			expectedError: true,
+		},
+		{
+			description: "should reject an invalid health-check interval",
+			spec: &BaseEdgeLBPoolBackendSpec{
+				HealthCheck: &EdgeLBBackendHealthCheckSpec{
+					Interval: pointers.NewString("5"),
+				},
+			},
+			expectedError: true,
+		},
+		{
+			description: "should reject a non-positive fall count",
+			spec: &BaseEdgeLBPoolBackendSpec{
+				HealthCheck: &EdgeLBBackendHealthCheckSpec{
+					Fall: pointers.NewInt32(0),
+				},
+			},
+			expectedError: true,
+		},
+		{
+			description: "should accept valid timeouts",
+			spec: &BaseEdgeLBPoolBackendSpec{
+				Timeouts: &EdgeLBTimeoutsSpec{
+					Connect: pointers.NewString("5s"),
+					Server:  pointers.NewString("1m"),
+					Tunnel:  pointers.NewString("1h"),
+				},
+			},
+		},
+		{
+			description: "should reject a non-positive timeout",
+			spec: &BaseEdgeLBPoolBackendSpec{
+				Timeouts: &EdgeLBTimeoutsSpec{
+					Server: pointers.NewString("0s"),
+				},
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+		assert.Equal(t, test.expectedError, test.spec.Validate() != nil)
+	}
+}
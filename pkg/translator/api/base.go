@@ -13,6 +13,11 @@ import (
 	"github.com/mesosphere/dklb/pkg/util/pointers"
 )
 
+var (
+	// edgeLBPoolNameRegex is the regular expression used to validate the name of the target EdgeLB pool.
+	edgeLBPoolNameRegex = regexp.MustCompile(constants.EdgeLBPoolNameRegex)
+)
+
 // BaseEdgeLBPoolSpec contains EdgeLB pool configuration properties that are common to both Service and Ingress resources.
 type BaseEdgeLBPoolSpec struct {
 	// CloudProviderConfiguration is the raw, JSON-encoded configuration to set on the target EdgeLB pool's ".cloudProvider" field.
@@ -29,10 +34,14 @@ type BaseEdgeLBPoolSpec struct {
 	Network *string `yaml:"network"`
 	// Role is the role to request for the target EdgeLB pool.
 	Role *string `yaml:"role"`
+	// RoutingMode is the way in which EdgeLB routes traffic to the pods backing the target Service resources (one of "NodePort" or "PodIP").
+	RoutingMode *string `yaml:"routingMode"`
 	// Size is the size to request for the target EdgeLB pool.
 	Size *int32 `yaml:"size"`
 	// Strategies groups together strategies used to customize the management of the target EdgeLB pool.
 	Strategies *EdgeLBPoolManagementStrategies `yaml:"strategies"`
+	// Timeouts contains the specification of the timeouts to use for every EdgeLB frontend and backend created in the target EdgeLB pool.
+	Timeouts *EdgeLBTimeoutsSpec `yaml:"timeouts"`
 }
 
 // SetDefaults sets default values wherever a value hasn't been specifically provided.
@@ -53,6 +62,9 @@ func (o *BaseEdgeLBPoolSpec) setDefaults() {
 	if o.Role == nil {
 		o.Role = pointers.NewString(DefaultEdgeLBPoolRole)
 	}
+	if o.RoutingMode == nil {
+		o.RoutingMode = pointers.NewString(DefaultEdgeLBPoolRoutingMode)
+	}
 	if o.Network == nil && *o.Role == constants.EdgeLBRolePublic {
 		o.Network = pointers.NewString(constants.EdgeLBHostNetwork)
 	}
@@ -63,9 +75,16 @@ func (o *BaseEdgeLBPoolSpec) setDefaults() {
 		o.Size = pointers.NewInt32(int32(DefaultEdgeLBPoolSize))
 	}
 	if o.Strategies == nil {
-		o.Strategies = &EdgeLBPoolManagementStrategies{
-			Creation: &DefaultEdgeLBPoolCreationStrategy,
-		}
+		o.Strategies = &EdgeLBPoolManagementStrategies{}
+	}
+	if o.Strategies.Creation == nil {
+		o.Strategies.Creation = &DefaultEdgeLBPoolCreationStrategy
+	}
+	if o.Strategies.Deletion == nil {
+		o.Strategies.Deletion = &DefaultEdgeLBPoolDeletionStrategy
+	}
+	if o.Strategies.Replacement == nil {
+		o.Strategies.Replacement = &DefaultEdgeLBPoolReplacementStrategy
 	}
 	// Check whether cloud-provider configuration is being specified, and override the defaults where necessary.
 	if *o.CloudProviderConfiguration != "" {
@@ -86,7 +105,7 @@ func (o *BaseEdgeLBPoolSpec) Validate() error {
 	o.setDefaults()
 
 	// Make sure that the name of the target EdgeLB pool is valid.
-	if !regexp.MustCompile(constants.EdgeLBPoolNameRegex).MatchString(*o.Name) {
+	if !edgeLBPoolNameRegex.MatchString(*o.Name) {
 		return fmt.Errorf("%q is not a valid edgelb pool name", *o.Name)
 	}
 	// Validate the CPU request.
@@ -101,6 +120,19 @@ func (o *BaseEdgeLBPoolSpec) Validate() error {
 	if *o.Size <= 0 {
 		return fmt.Errorf("%d is not a valid size request", *o.Size)
 	}
+	// Validate the timeouts.
+	if o.Timeouts != nil {
+		if err := o.Timeouts.validate(); err != nil {
+			return fmt.Errorf(".timeouts%v", err)
+		}
+	}
+	// Validate the routing mode, making sure that the target EdgeLB pool joins a DC/OS virtual network (from which pod IPs are reachable) whenever pod IPs are to be targeted directly.
+	if *o.RoutingMode != EdgeLBPoolRoutingModeNodePort && *o.RoutingMode != EdgeLBPoolRoutingModePodIP {
+		return fmt.Errorf("%q is not a valid routing mode", *o.RoutingMode)
+	}
+	if *o.RoutingMode == EdgeLBPoolRoutingModePodIP && *o.Network == constants.EdgeLBHostNetwork {
+		return fmt.Errorf("the %q routing mode requires the target edgelb pool to join a dc/os virtual network", *o.RoutingMode)
+	}
 	// Validate the cloud-provider configuration.
 	if *o.CloudProviderConfiguration != "" {
 		cp := &models.V2CloudProvider{}
@@ -126,12 +158,21 @@ func (o *BaseEdgeLBPoolSpec) Validate() error {
 	return nil
 }
 
+// RoutesToPodIPs returns a value indicating whether EdgeLB routes traffic directly to the IPs of the pods backing the target Service resources.
+func (o *BaseEdgeLBPoolSpec) RoutesToPodIPs() bool {
+	return o.RoutingMode != nil && *o.RoutingMode == EdgeLBPoolRoutingModePodIP
+}
+
 // ValidateTransition validates the transition between "previous" and the current object.
 func (o *BaseEdgeLBPoolSpec) ValidateTransition(previous *BaseEdgeLBPoolSpec) error {
 	// If we're transitioning to a cloud-provider configuration, we don't need to perform any additional validations, as a new EdgeLB pool will always be created.
 	if *previous.CloudProviderConfiguration == "" && *o.CloudProviderConfiguration != "" {
 		return nil
 	}
+	// If the target EdgeLB pool is being replaced by a new one, we don't need to perform any additional validations either.
+	if *o.Strategies.Replacement == EdgeLBPoolReplacementStrategyBlueGreen && *previous.Name != *o.Name {
+		return nil
+	}
 	// Prevent the cloud-provider configuration from being removed.
 	if *previous.CloudProviderConfiguration != "" && *o.CloudProviderConfiguration == "" {
 		return fmt.Errorf("the cloud-provider configuration cannot be removed")
@@ -140,10 +181,6 @@ func (o *BaseEdgeLBPoolSpec) ValidateTransition(previous *BaseEdgeLBPoolSpec) er
 	if *previous.Name != *o.Name {
 		return errors.New("the name of the target edgelb pool cannot be changed")
 	}
-	// Prevent the role of the EdgeLB pool from changing.
-	if *previous.Role != *o.Role {
-		return errors.New("the role of the target edgelb pool cannot be changed")
-	}
 	// Prevent the virtual network of the target EdgeLB pool from changing.
 	if *previous.Network != *o.Network {
 		return errors.New("the virtual network of the target edgelb pool cannot be changed")
@@ -153,5 +190,10 @@ func (o *BaseEdgeLBPoolSpec) ValidateTransition(previous *BaseEdgeLBPoolSpec) er
 
 // EdgeLBPoolManagementStrategies groups together strategies used to customize the management of EdgeLB pools.
 type EdgeLBPoolManagementStrategies struct {
+	// Creation is the strategy used to create the target EdgeLB pool.
 	Creation *EdgeLBPoolCreationStrategy `yaml:"creation"`
+	// Deletion is the strategy used to delete the target EdgeLB pool (or to clean it up) whenever an Ingress/Service resource is removed from it.
+	Deletion *EdgeLBPoolDeletionStrategy `yaml:"deletion"`
+	// Replacement is the strategy used to replace the target EdgeLB pool whenever its name changes.
+	Replacement *EdgeLBPoolReplacementStrategy `yaml:"replacement"`
 }
@@ -5,3 +5,51 @@ const (
 	IngressEdgeLBHTTPModeEnabled  = "enabled"
 	IngressEdgeLBHTTPModeRedirect = "redirect"
 )
+
+const (
+	// IngressPathTypeExact denotes that an Ingress path matches the URL path exactly and with case sensitivity.
+	IngressPathTypeExact = "Exact"
+	// IngressPathTypeImplementationSpecific denotes that an Ingress path is an egrep (IEEE Std 1003.1) regular expression matched against the whole URL path.
+	IngressPathTypeImplementationSpecific = "ImplementationSpecific"
+	// IngressPathTypePrefix denotes that an Ingress path matches based on a URL path prefix split by "/", on an element-by-element basis.
+	IngressPathTypePrefix = "Prefix"
+)
+
+const (
+	// IngressBackendProtocolH2C denotes that the target of an Ingress backend speaks cleartext HTTP/2.
+	IngressBackendProtocolH2C = "H2C"
+	// IngressBackendProtocolHTTP denotes that the target of an Ingress backend speaks plain HTTP.
+	IngressBackendProtocolHTTP = "HTTP"
+	// IngressBackendProtocolHTTPS denotes that the target of an Ingress backend speaks HTTPS.
+	IngressBackendProtocolHTTPS = "HTTPS"
+)
+
+const (
+	// IngressAffinityModeInsert denotes that EdgeLB inserts a cookie identifying the server that handled the first request of a client.
+	IngressAffinityModeInsert = "insert"
+	// IngressAffinityModeNone denotes that session affinity is disabled.
+	IngressAffinityModeNone = "none"
+	// IngressAffinityModePrefix denotes that EdgeLB prefixes a cookie set by the application with an identifier of the server that set it.
+	IngressAffinityModePrefix = "prefix"
+)
+
+const (
+	// IngressClientAuthVerifyOptional denotes that clients may present a certificate, which is verified if present.
+	IngressClientAuthVerifyOptional = "optional"
+	// IngressClientAuthVerifyRequired denotes that clients must present a valid certificate.
+	IngressClientAuthVerifyRequired = "required"
+)
+
+const (
+	// ProxyProtocolV1 denotes version 1 (human-readable) of the PROXY protocol.
+	ProxyProtocolV1 = "v1"
+	// ProxyProtocolV2 denotes version 2 (binary) of the PROXY protocol.
+	ProxyProtocolV2 = "v2"
+)
+
+const (
+	// EdgeLBPoolRoutingModeNodePort denotes that EdgeLB routes traffic to the node port of the target Service resource on every Kubernetes node.
+	EdgeLBPoolRoutingModeNodePort = "NodePort"
+	// EdgeLBPoolRoutingModePodIP denotes that EdgeLB routes traffic directly to the IPs of the (ready) pods backing the target Service resource.
+	EdgeLBPoolRoutingModePodIP = "PodIP"
+)
@@ -9,14 +9,38 @@ var (
 	DefaultEdgeLBPoolCpus = float64(0.1)
 	// DefaultEdgeLBPoolCreationStrategy is the strategy to use for creating an EdgeLB pool when a value is not provided.
 	DefaultEdgeLBPoolCreationStrategy = EdgeLBPoolCreationStrategyIfNotPresent
+	// DefaultEdgeLBPoolDeletionStrategy is the strategy to use for deleting an EdgeLB pool when a value is not provided.
+	DefaultEdgeLBPoolDeletionStrategy = EdgeLBPoolDeletionStrategyDeleteWhenEmpty
 	// DefaultEdgeLBPoolMemory is the amount of memory to request for an EdgeLB pool when a value is not provided.
 	DefaultEdgeLBPoolMemory = int32(128)
 	// DefaultEdgeLBPoolHTTPPort is the HTTP port to use as the frontend bind port for an EdgeLB pool used to provision an Ingress resource when a value is not provided.
 	DefaultEdgeLBPoolHTTPPort = int32(80)
 	// DefaultEdgeLBPoolHTTPSPort is the HTTPS port to use as the frontend bind port for an EdgeLB pool used to provision an Ingress resource when a value is not provided.
 	DefaultEdgeLBPoolHTTPSPort = int32(443)
+	// DefaultEdgeLBPoolReplacementStrategy is the strategy to use for replacing an EdgeLB pool when a value is not provided.
+	DefaultEdgeLBPoolReplacementStrategy = EdgeLBPoolReplacementStrategyNever
 	// DefaultEdgeLBPoolRole is the role to use for an EdgeLB pool when a value is not provided.
 	DefaultEdgeLBPoolRole = constants.EdgeLBRolePublic
+	// DefaultEdgeLBPoolRoutingMode is the way in which EdgeLB routes traffic to the pods backing the target Service resources when a value is not provided.
+	DefaultEdgeLBPoolRoutingMode = EdgeLBPoolRoutingModeNodePort
 	// DefaultEdgeLBPoolSize is the size to use for an EdgeLB pool when a value is not provided.
 	DefaultEdgeLBPoolSize = int(1)
+	// DefaultIngressAffinityCookieName is the name of the cookie used for session affinity when a value is not provided.
+	DefaultIngressAffinityCookieName = "DKLB_AFFINITY"
+	// DefaultIngressAffinityMode is the way in which the cookie used for session affinity is handled when a value is not provided.
+	DefaultIngressAffinityMode = IngressAffinityModeInsert
+	// DefaultIngressBackendProtocol is the protocol used to talk to the target of an Ingress backend when a value is not provided.
+	DefaultIngressBackendProtocol = IngressBackendProtocolHTTP
+	// DefaultIngressBasicAuthRealm is the realm presented to clients when requesting basic authentication when a value is not provided.
+	DefaultIngressBasicAuthRealm = "dklb"
+	// DefaultIngressClientAuthSubjectHeader is the name of the request header used to pass the subject of a client certificate to the backends of an Ingress resource when a value is not provided.
+	DefaultIngressClientAuthSubjectHeader = "X-SSL-Client-Subject"
+	// DefaultIngressClientAuthVerify is the verification mode used for client certificates when a value is not provided.
+	DefaultIngressClientAuthVerify = IngressClientAuthVerifyRequired
+	// DefaultIngressHSTSMaxAge is the time (in seconds) during which clients should only access an Ingress resource over HTTPS when a value is not provided.
+	DefaultIngressHSTSMaxAge = int32(31536000)
+	// DefaultIngressPathType is the path type used to match the paths of an Ingress resource when a value is not provided.
+	DefaultIngressPathType = IngressPathTypeImplementationSpecific
+	// DefaultIngressRateLimitPeriod is the period over which the requests sent by a client to an Ingress resource are counted when a value is not provided.
+	DefaultIngressRateLimitPeriod = "1s"
 )
@@ -2,16 +2,32 @@ package api
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
-	extsv1beta1 "k8s.io/api/extensions/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/util/validation"
 
+	kubernetesutil "github.com/mesosphere/dklb/pkg/util/kubernetes"
 	"github.com/mesosphere/dklb/pkg/util/pointers"
 )
 
+var (
+	// basicAuthRealmRegex is the regular expression used to validate the realm presented to clients when requesting basic authentication.
+	basicAuthRealmRegex = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+	// headerNameRegex is the regular expression used to validate the name of an HTTP header (i.e. an RFC 7230 token).
+	headerNameRegex = regexp.MustCompile("^[A-Za-z0-9!#$%&'*+.^_`|~-]+$")
+	// canaryMatchValueRegex is the regular expression used to validate the value of the header or cookie used to force requests to a canary.
+	canaryMatchValueRegex = regexp.MustCompile("^[A-Za-z0-9!#$%&'*+.^_`|~-]+$")
+	// cookieNameRegex is the regular expression used to validate the name of the cookie used for session affinity (i.e. an RFC 6265 token).
+	cookieNameRegex = regexp.MustCompile("^[A-Za-z0-9!#$%&'*+.^_`|~-]+$")
+)
+
 // IngressEdgeLBPoolHTTPFrontendSpec contains the specification of the HTTP EdgeLB frontend associated with a given Ingress resource.
 type IngressEdgeLBPoolHTTPFrontendSpec struct {
+	// MaxConn is the maximum number of concurrent connections accepted by the frontend.
+	MaxConn *int32 `yaml:"maxConn"`
 	// Mode describes if this frontend is disabled, enabled or in redirect mode.
 	Mode *string `yaml:"mode"`
 	// Port is the port to use as the frontend bind port for HTTP traffic.
@@ -20,10 +36,36 @@ type IngressEdgeLBPoolHTTPFrontendSpec struct {
 
 // IngressEdgeLBPoolHTTPSFrontendSpec contains the specification of the HTTP EdgeLB frontend associated with a given Ingress resource.
 type IngressEdgeLBPoolHTTPSFrontendSpec struct {
+	// ClientAuth contains the specification of the client certificate authentication required to access the frontend.
+	ClientAuth *IngressEdgeLBPoolClientAuthSpec `yaml:"clientAuth"`
+	// HSTS contains the specification of the HTTP Strict Transport Security policy to advertise to clients.
+	HSTS *IngressEdgeLBPoolHSTSSpec `yaml:"hsts"`
+	// MaxConn is the maximum number of concurrent connections accepted by the frontend.
+	MaxConn *int32 `yaml:"maxConn"`
 	// Port is the port to use as the frontend bind port for HTTP traffic.
 	Port *int32 `yaml:"port"`
 }
 
+// IngressEdgeLBPoolClientAuthSpec contains the specification of the client certificate (mTLS) authentication required by the HTTPS EdgeLB frontend associated with a given Ingress resource.
+type IngressEdgeLBPoolClientAuthSpec struct {
+	// CASecretName is the name of the Secret resource holding the CA bundle (under the "ca.crt" key) used to verify the certificates presented by clients.
+	CASecretName *string `yaml:"caSecretName"`
+	// SubjectHeader is the name of the request header used to pass the subject of the certificate presented by the client to the backends.
+	SubjectHeader *string `yaml:"subjectHeader"`
+	// Verify is the verification mode (one of "required" or "optional") to use for client certificates.
+	Verify *string `yaml:"verify"`
+}
+
+// IngressEdgeLBPoolHSTSSpec contains the specification of the HTTP Strict Transport Security policy advertised by the HTTPS EdgeLB frontend associated with a given Ingress resource.
+type IngressEdgeLBPoolHSTSSpec struct {
+	// IncludeSubDomains indicates whether the policy applies to subdomains as well.
+	IncludeSubDomains *bool `yaml:"includeSubDomains"`
+	// MaxAge is the time (in seconds) during which clients should only access the host over HTTPS.
+	MaxAge *int32 `yaml:"maxAge"`
+	// Preload indicates whether consent is given to have the host included in browsers' HSTS preload lists.
+	Preload *bool `yaml:"preload"`
+}
+
 // IngressEdgeLBPoolFrontendsSpec contains the specification of the EdgeLB frontends associated with a given Ingress resource.
 type IngressEdgeLBPoolFrontendsSpec struct {
 	// HTTP contains the specification of the HTTP EdgeLB frontend associated with the Ingress resource.
@@ -31,22 +73,151 @@ type IngressEdgeLBPoolFrontendsSpec struct {
 	HTTPS *IngressEdgeLBPoolHTTPSFrontendSpec `yaml:"https"`
 }
 
+// IngressEdgeLBPoolBackendSpec contains the specification of the EdgeLB backend associated with a given Ingress backend.
+type IngressEdgeLBPoolBackendSpec struct {
+	BaseEdgeLBPoolBackendSpec `yaml:",inline"`
+	// ServiceName is the name of the Service resource targeted by the Ingress backend.
+	ServiceName string `yaml:"serviceName"`
+	// ServicePort is the port (name or number) of the Service resource targeted by the Ingress backend.
+	ServicePort string `yaml:"servicePort"`
+	// BackendProtocol is the protocol (one of "HTTP", "HTTPS" or "H2C") spoken by the target of the Ingress backend.
+	BackendProtocol *string `yaml:"backendProtocol"`
+	// CASecretName is the name of the Secret resource holding the CA bundle (under the "ca.crt" key) used to verify the certificate presented by an HTTPS backend.
+	CASecretName *string `yaml:"caSecretName"`
+	// MaxConn is the maximum number of concurrent connections established to each server of the EdgeLB backend.
+	// Additional requests are queued until a connection becomes available.
+	MaxConn *int32 `yaml:"maxConn"`
+	// Rewrite contains the specification of the HTTP rewriting to perform on requests and responses going through the Ingress backend.
+	// Any value specified here takes precedence over the corresponding Ingress-level value.
+	Rewrite *IngressEdgeLBPoolRewriteSpec `yaml:"rewrite"`
+	// Affinity contains the specification of the cookie-based session affinity to use for the Ingress backend.
+	// Any value specified here takes precedence over the corresponding Ingress-level value.
+	Affinity *IngressEdgeLBPoolAffinitySpec `yaml:"affinity"`
+	// Canaries is the list of canary Service resources to which a share of the traffic sent to the Ingress backend is routed.
+	Canaries []*IngressEdgeLBPoolCanarySpec `yaml:"canaries"`
+}
+
+// IngressEdgeLBPoolCanarySpec contains the specification of a canary Service resource to which a share of the traffic sent to a given Ingress backend is routed.
+type IngressEdgeLBPoolCanarySpec struct {
+	// ServiceName is the name of the canary Service resource.
+	ServiceName string `yaml:"serviceName"`
+	// ServicePort is the port (name or number) of the canary Service resource.
+	ServicePort string `yaml:"servicePort"`
+	// Weight is the percentage (between 0 and 100) of the traffic sent to the Ingress backend that is routed to the canary.
+	Weight *int32 `yaml:"weight"`
+	// Header contains the specification of the header that forces requests to the canary regardless of its weight.
+	Header *IngressEdgeLBPoolCanaryMatchSpec `yaml:"header"`
+	// Cookie contains the specification of the cookie that forces requests to the canary regardless of its weight.
+	Cookie *IngressEdgeLBPoolCanaryMatchSpec `yaml:"cookie"`
+}
+
+// IngressEdgeLBPoolCanaryMatchSpec contains the specification of a header or cookie that forces requests to a canary.
+type IngressEdgeLBPoolCanaryMatchSpec struct {
+	// Name is the name of the header or cookie.
+	Name string `yaml:"name"`
+	// Value is the value the header or cookie must have for requests to be forced to the canary.
+	Value string `yaml:"value"`
+}
+
+// IngressEdgeLBPoolAffinitySpec contains the specification of the cookie-based session affinity to use for a given Ingress backend.
+type IngressEdgeLBPoolAffinitySpec struct {
+	// CookieName is the name of the cookie used to pin clients to a given server.
+	CookieName *string `yaml:"cookieName"`
+	// Mode is the way (one of "insert", "prefix" or "none") in which the cookie is handled.
+	Mode *string `yaml:"mode"`
+	// TTL is the maximum lifetime of an inserted cookie (e.g. "1h").
+	TTL *string `yaml:"ttl"`
+}
+
+// IngressEdgeLBPoolRewriteSpec contains the specification of the HTTP rewriting to perform on requests and responses going through a given Ingress backend.
+type IngressEdgeLBPoolRewriteSpec struct {
+	// ForwardedFor indicates whether the "X-Forwarded-For" header should be added to requests.
+	ForwardedFor *bool `yaml:"forwardedFor"`
+	// RewriteTarget is the path with which to replace the path prefix through which the Ingress backend is referenced (e.g. "/" causes "/app/foo" to be forwarded as "/foo" for a path of "/app").
+	RewriteTarget *string `yaml:"rewriteTarget"`
+	// RewriteLocation indicates whether the "Location" header of responses should be rewritten to undo the host and path rewriting performed on requests.
+	RewriteLocation *bool `yaml:"rewriteLocation"`
+	// UpstreamHost is the value to set the "Host" header of requests to.
+	UpstreamHost *string `yaml:"upstreamHost"`
+}
+
+// IngressEdgeLBPoolBasicAuthSpec contains the specification of the HTTP basic authentication required to access a given Ingress resource.
+type IngressEdgeLBPoolBasicAuthSpec struct {
+	// Realm is the realm presented to clients when requesting authentication.
+	Realm *string `yaml:"realm"`
+	// SecretName is the name of the Secret resource holding the htpasswd-formatted credentials (under the "auth" key) of the users allowed to access the Ingress resource.
+	SecretName *string `yaml:"secretName"`
+}
+
+// IngressEdgeLBPoolHeaderSpec contains the specification of a single HTTP header.
+type IngressEdgeLBPoolHeaderSpec struct {
+	// Name is the name of the HTTP header.
+	Name string `yaml:"name"`
+	// Value is the value of the HTTP header.
+	Value string `yaml:"value"`
+}
+
+// IngressEdgeLBPoolHeaderActionsSpec contains the specification of the changes to perform on the HTTP headers of requests or responses.
+type IngressEdgeLBPoolHeaderActionsSpec struct {
+	// Add is the list of HTTP headers to add, regardless of whether an HTTP header with the same name is already present.
+	Add []IngressEdgeLBPoolHeaderSpec `yaml:"add"`
+	// Remove is the list of names of the HTTP headers to remove.
+	Remove []string `yaml:"remove"`
+	// Set is the list of HTTP headers to set, replacing any HTTP header with the same name.
+	Set []IngressEdgeLBPoolHeaderSpec `yaml:"set"`
+}
+
+// IngressEdgeLBPoolHeadersSpec contains the specification of the changes to perform on the HTTP headers of requests and responses going through a given Ingress resource.
+type IngressEdgeLBPoolHeadersSpec struct {
+	// Request contains the specification of the changes to perform on the HTTP headers of requests.
+	Request *IngressEdgeLBPoolHeaderActionsSpec `yaml:"request"`
+	// Response contains the specification of the changes to perform on the HTTP headers of responses.
+	Response *IngressEdgeLBPoolHeaderActionsSpec `yaml:"response"`
+}
+
+// IngressEdgeLBPoolRateLimitSpec contains the specification of the rate at which a single client IP is allowed to send requests to a given Ingress resource.
+type IngressEdgeLBPoolRateLimitSpec struct {
+	// Burst is the number of requests a client is allowed to send in excess of "Requests" during a single period.
+	Burst *int32 `yaml:"burst"`
+	// Period is the period (e.g. "10s") over which requests are counted.
+	Period *string `yaml:"period"`
+	// Requests is the number of requests a client is allowed to send during a single period.
+	Requests *int32 `yaml:"requests"`
+}
+
 // IngressEdgeLBPoolSpec contains the specification of the target EdgeLB pool for a given Ingress resource.
 type IngressEdgeLBPoolSpec struct {
 	BaseEdgeLBPoolSpec `yaml:",inline"`
+	// Backends contains the specification of the EdgeLB backends associated with the Ingress backends.
+	Backends []*IngressEdgeLBPoolBackendSpec `yaml:"backends"`
+	// BasicAuth contains the specification of the HTTP basic authentication required to access the Ingress resource.
+	BasicAuth *IngressEdgeLBPoolBasicAuthSpec `yaml:"basicAuth"`
 	// Frontends contains the specification of the EdgeLB frontends associated with the Ingress resource.
 	Frontends *IngressEdgeLBPoolFrontendsSpec `yaml:"frontends"`
+	// Headers contains the specification of the changes to perform on the HTTP headers of requests and responses going through the Ingress resource.
+	Headers *IngressEdgeLBPoolHeadersSpec `yaml:"headers"`
+	// PathType is the type of matching (one of "Exact", "Prefix" or "ImplementationSpecific") to perform on the paths of the Ingress resource whose own path type is "ImplementationSpecific".
+	PathType *string `yaml:"pathType"`
+	// Rewrite contains the specification of the HTTP rewriting to perform on requests and responses going through every Ingress backend.
+	Rewrite *IngressEdgeLBPoolRewriteSpec `yaml:"rewrite"`
+	// Affinity contains the specification of the cookie-based session affinity to use for every Ingress backend.
+	Affinity *IngressEdgeLBPoolAffinitySpec `yaml:"affinity"`
+	// AllowedSourceRanges is the list of CIDRs from which clients are allowed to access the Ingress resource.
+	// If empty, clients are allowed to connect from any address.
+	AllowedSourceRanges []string `yaml:"allowedSourceRanges"`
+	// RateLimit contains the specification of the rate at which a single client IP is allowed to send requests to the Ingress resource.
+	RateLimit *IngressEdgeLBPoolRateLimitSpec `yaml:"rateLimit"`
 }
 
 // NewDefaultIngressEdgeLBPoolSpecForIngress returns a new EdgeLB pool specification for the provided Ingress resource that uses default values.
-func NewDefaultIngressEdgeLBPoolSpecForIngress(ingress *extsv1beta1.Ingress) *IngressEdgeLBPoolSpec {
+func NewDefaultIngressEdgeLBPoolSpecForIngress(ingress *networkingv1.Ingress) *IngressEdgeLBPoolSpec {
 	r := &IngressEdgeLBPoolSpec{}
 	r.SetDefaults(ingress)
 	return r
 }
 
 // SetDefaults sets default values whenever a value hasn't been specifically provided.
-func (o *IngressEdgeLBPoolSpec) SetDefaults(ingress *extsv1beta1.Ingress) {
+func (o *IngressEdgeLBPoolSpec) SetDefaults(ingress *networkingv1.Ingress) {
 	// Set defaults on the base object.
 	o.BaseEdgeLBPoolSpec.setDefaults()
 
@@ -63,18 +234,53 @@ func (o *IngressEdgeLBPoolSpec) SetDefaults(ingress *extsv1beta1.Ingress) {
 	if o.Frontends.HTTP.Mode == nil || *o.Frontends.HTTP.Mode == "" {
 		o.Frontends.HTTP.Mode = pointers.NewString(IngressEdgeLBHTTPModeEnabled)
 	}
-	if IsIngressTLSEnabled(ingress) {
-		if o.Frontends.HTTPS == nil {
-			o.Frontends.HTTPS = &IngressEdgeLBPoolHTTPSFrontendSpec{}
+	for _, backend := range o.Backends {
+		if backend.BackendProtocol == nil || *backend.BackendProtocol == "" {
+			backend.BackendProtocol = pointers.NewString(DefaultIngressBackendProtocol)
 		}
-		if o.Frontends.HTTPS.Port == nil {
-			o.Frontends.HTTPS.Port = &DefaultEdgeLBPoolHTTPSPort
+		for _, canary := range backend.Canaries {
+			if canary.Weight == nil {
+				canary.Weight = pointers.NewInt32(0)
+			}
+		}
+	}
+	if o.PathType == nil || *o.PathType == "" {
+		o.PathType = pointers.NewString(DefaultIngressPathType)
+	}
+	if o.BasicAuth != nil {
+		if o.BasicAuth.Realm == nil || *o.BasicAuth.Realm == "" {
+			o.BasicAuth.Realm = pointers.NewString(DefaultIngressBasicAuthRealm)
+		}
+	}
+	if o.RateLimit != nil {
+		if o.RateLimit.Burst == nil {
+			o.RateLimit.Burst = pointers.NewInt32(0)
+		}
+		if o.RateLimit.Period == nil || *o.RateLimit.Period == "" {
+			o.RateLimit.Period = pointers.NewString(DefaultIngressRateLimitPeriod)
+		}
+	}
+	if IsIngressTLSEnabled(ingress) && o.Frontends.HTTPS == nil {
+		o.Frontends.HTTPS = &IngressEdgeLBPoolHTTPSFrontendSpec{}
+	}
+	if o.Frontends.HTTPS != nil && o.Frontends.HTTPS.Port == nil {
+		o.Frontends.HTTPS.Port = &DefaultEdgeLBPoolHTTPSPort
+	}
+	if o.Frontends.HTTPS != nil && o.Frontends.HTTPS.HSTS != nil && o.Frontends.HTTPS.HSTS.MaxAge == nil {
+		o.Frontends.HTTPS.HSTS.MaxAge = pointers.NewInt32(DefaultIngressHSTSMaxAge)
+	}
+	if o.Frontends.HTTPS != nil && o.Frontends.HTTPS.ClientAuth != nil {
+		if o.Frontends.HTTPS.ClientAuth.SubjectHeader == nil || *o.Frontends.HTTPS.ClientAuth.SubjectHeader == "" {
+			o.Frontends.HTTPS.ClientAuth.SubjectHeader = pointers.NewString(DefaultIngressClientAuthSubjectHeader)
+		}
+		if o.Frontends.HTTPS.ClientAuth.Verify == nil || *o.Frontends.HTTPS.ClientAuth.Verify == "" {
+			o.Frontends.HTTPS.ClientAuth.Verify = pointers.NewString(DefaultIngressClientAuthVerify)
 		}
 	}
 }
 
 // Validate checks whether the current object is valid.
-func (o *IngressEdgeLBPoolSpec) Validate(obj *extsv1beta1.Ingress) error {
+func (o *IngressEdgeLBPoolSpec) Validate(obj *networkingv1.Ingress) error {
 	// Set default values where applicable for easier validation.
 	o.SetDefaults(obj)
 
@@ -90,13 +296,271 @@ func (o *IngressEdgeLBPoolSpec) Validate(obj *extsv1beta1.Ingress) error {
 	if err := isValidHTTPMode(*o.Frontends.HTTP.Mode); err != nil {
 		return fmt.Errorf(".frontends.http.mode %s is not a valid HTTP mode", *o.Frontends.HTTP.Mode)
 	}
+	// Validate that the maximum number of connections accepted by the HTTP frontend is valid.
+	if o.Frontends.HTTP.MaxConn != nil && *o.Frontends.HTTP.MaxConn <= 0 {
+		return fmt.Errorf(".frontends.http.maxConn %d must be positive", *o.Frontends.HTTP.MaxConn)
+	}
 	// Validate that the HTTPS port is valid.
 	if o.Frontends.HTTPS != nil {
 		if err := validation.IsValidPortNum(int(*o.Frontends.HTTPS.Port)); err != nil {
 			return fmt.Errorf(".frontends.https.port %d is not a valid HTTPS port number (valid range is between 1 and 65535)", *o.Frontends.HTTPS.Port)
 		}
+		if o.Frontends.HTTPS.MaxConn != nil && *o.Frontends.HTTPS.MaxConn <= 0 {
+			return fmt.Errorf(".frontends.https.maxConn %d must be positive", *o.Frontends.HTTPS.MaxConn)
+		}
+		if o.Frontends.HTTPS.HSTS != nil && *o.Frontends.HTTPS.HSTS.MaxAge < 0 {
+			return fmt.Errorf(".frontends.https.hsts.maxAge %d must not be negative", *o.Frontends.HTTPS.HSTS.MaxAge)
+		}
+		if o.Frontends.HTTPS.ClientAuth != nil {
+			if err := o.Frontends.HTTPS.ClientAuth.validate(); err != nil {
+				return fmt.Errorf(".frontends.https.clientAuth%v", err)
+			}
+			// Client certificates cannot be requested over plain HTTP, so requests must not be served by the HTTP frontend or else client certificate authentication could be bypassed.
+			if *o.Frontends.HTTP.Mode == IngressEdgeLBHTTPModeEnabled {
+				return fmt.Errorf(".frontends.https.clientAuth requires .frontends.http.mode to be either %q or %q", IngressEdgeLBHTTPModeDisabled, IngressEdgeLBHTTPModeRedirect)
+			}
+		}
 	}
-	return nil
+	// Validate the specification of the changes to perform on HTTP headers.
+	if o.Headers != nil {
+		if err := o.Headers.Request.validate(); err != nil {
+			return fmt.Errorf(".headers.request%v", err)
+		}
+		if err := o.Headers.Response.validate(); err != nil {
+			return fmt.Errorf(".headers.response%v", err)
+		}
+	}
+	// Validate the basic authentication specification.
+	if o.BasicAuth != nil {
+		if err := o.BasicAuth.validate(); err != nil {
+			return fmt.Errorf(".basicAuth%v", err)
+		}
+	}
+	// Validate the rate limiting specification.
+	if o.RateLimit != nil {
+		if err := o.RateLimit.validate(); err != nil {
+			return fmt.Errorf(".rateLimit%v", err)
+		}
+	}
+	// Validate that the source ranges allowed to access the Ingress resource are valid.
+	if err := validateSourceRanges(o.AllowedSourceRanges); err != nil {
+		return fmt.Errorf(".allowedSourceRanges: %v", err)
+	}
+	// Validate the Ingress-level rewriting specification.
+	if o.Rewrite != nil {
+		if err := o.Rewrite.validate(); err != nil {
+			return fmt.Errorf(".rewrite%v", err)
+		}
+	}
+	// Validate the Ingress-level session affinity specification.
+	if o.Affinity != nil {
+		if err := o.Affinity.validate(); err != nil {
+			return fmt.Errorf(".affinity%v", err)
+		}
+	}
+	// Validate the specification of each EdgeLB backend.
+	seenBackends := make(map[string]bool, len(o.Backends))
+	for idx, backend := range o.Backends {
+		if err := backend.validate(); err != nil {
+			return fmt.Errorf(".backends[%d]: %v", idx, err)
+		}
+		key := backend.ServiceName + ":" + backend.ServicePort
+		if seenBackends[key] {
+			return fmt.Errorf(".backends[%d]: duplicate backend for service port %q", idx, key)
+		}
+		seenBackends[key] = true
+	}
+	// Validate that the path type is valid.
+	if err := isValidPathType(*o.PathType); err != nil {
+		return fmt.Errorf(".pathType %s is not a valid path type", *o.PathType)
+	}
+	// Validate that every wildcard host on the Ingress resource is valid.
+	for _, rule := range obj.Spec.Rules {
+		if err := isValidHost(rule.Host); err != nil {
+			return err
+		}
+	}
+	// Validate that the Ingress resource doesn't reference any "resource" Ingress backends, as EdgeLB can only route traffic to Service resources.
+	var err error
+	kubernetesutil.ForEachIngressResourceBackend(obj, func(resource corev1.TypedLocalObjectReference) {
+		if err == nil {
+			err = fmt.Errorf("resource backend %s %q is not supported by edgelb, as it can only route traffic to services", resource.Kind, resource.Name)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	// Validate that every path on the Ingress resource is valid according to its path type.
+	kubernetesutil.ForEachIngresBackend(obj, func(_ *string, path *networkingv1.HTTPIngressPath, _ networkingv1.IngressServiceBackend) {
+		if err != nil || path == nil || path.Path == "" {
+			return
+		}
+		err = isValidPath(path.Path, o.PathTypeFor(path))
+	})
+	if err != nil {
+		return err
+	}
+	// Validate that every Ingress backend for which a rewrite target has been specified can be unambiguously rewritten.
+	// Validate also that the session affinity resulting from combining the Ingress-level and backend-level specifications is consistent.
+	kubernetesutil.ForEachIngresBackend(obj, func(_ *string, path *networkingv1.HTTPIngressPath, backend networkingv1.IngressServiceBackend) {
+		if err != nil {
+			return
+		}
+		if affinitySpec := o.AffinitySpecFor(backend); affinitySpec != nil && affinitySpec.TTL != nil && *affinitySpec.Mode != IngressAffinityModeInsert {
+			err = fmt.Errorf(".affinity.ttl can only be specified for backend \"%s:%s\" when .affinity.mode is %s", backend.Name, kubernetesutil.IngressServiceBackendPort(backend), IngressAffinityModeInsert)
+			return
+		}
+		rewriteSpec := o.RewriteSpecFor(backend)
+		if rewriteSpec.RewriteTarget == nil {
+			return
+		}
+		if path != nil && path.Path != "" && o.PathTypeFor(path) == IngressPathTypeImplementationSpecific {
+			err = fmt.Errorf(".rewrite.rewriteTarget can only be specified when .pathType is %s or %s", IngressPathTypeExact, IngressPathTypePrefix)
+			return
+		}
+		if paths := kubernetesutil.IngressBackendPaths(obj, backend); len(paths) > 1 {
+			err = fmt.Errorf(".rewrite.rewriteTarget cannot be specified for backend \"%s:%s\" as it is referenced by more than one path (%s)", backend.Name, kubernetesutil.IngressServiceBackendPort(backend), strings.Join(paths, ", "))
+		}
+	})
+	return err
+}
+
+// BackendSpecFor returns the specification of the EdgeLB backend associated with the specified Ingress backend.
+// If none has been provided, a specification containing default values is returned.
+func (o *IngressEdgeLBPoolSpec) BackendSpecFor(backend networkingv1.IngressServiceBackend) *IngressEdgeLBPoolBackendSpec {
+	for _, b := range o.Backends {
+		if b.ServiceName == backend.Name && b.ServicePort == kubernetesutil.IngressServiceBackendPort(backend) {
+			return b
+		}
+	}
+	return &IngressEdgeLBPoolBackendSpec{
+		ServiceName:     backend.Name,
+		ServicePort:     kubernetesutil.IngressServiceBackendPort(backend),
+		BackendProtocol: pointers.NewString(DefaultIngressBackendProtocol),
+	}
+}
+
+// PrimaryWeight returns the percentage of the traffic sent to the Ingress backend that is routed to the target Service resource rather than to its canaries.
+func (o *IngressEdgeLBPoolBackendSpec) PrimaryWeight() int32 {
+	res := int32(100)
+	for _, canary := range o.Canaries {
+		res -= *canary.Weight
+	}
+	return res
+}
+
+// IngressBackend returns the Ingress backend that corresponds to the canary.
+func (o *IngressEdgeLBPoolCanarySpec) IngressBackend() networkingv1.IngressServiceBackend {
+	return kubernetesutil.NewIngressServiceBackend(o.ServiceName, o.ServicePort)
+}
+
+// PathTypeFor returns the type of matching to perform on the specified path of the Ingress resource.
+// Paths of type "Exact" or "Prefix" are matched as dictated by the Ingress spec, while paths of type "ImplementationSpecific" (or without a type) are matched according to ".pathType".
+func (o *IngressEdgeLBPoolSpec) PathTypeFor(path *networkingv1.HTTPIngressPath) string {
+	if path.PathType != nil {
+		switch *path.PathType {
+		case networkingv1.PathTypeExact:
+			return IngressPathTypeExact
+		case networkingv1.PathTypePrefix:
+			return IngressPathTypePrefix
+		}
+	}
+	return *o.PathType
+}
+
+// IsForceable indicates whether requests can be forced to the canary using a header or a cookie.
+func (o *IngressEdgeLBPoolCanarySpec) IsForceable() bool {
+	return o.Header != nil || o.Cookie != nil
+}
+
+// TimeoutsSpecFor returns the specification of the timeouts to use for the EdgeLB backend associated with the specified Ingress backend.
+// Values specified for the Ingress backend take precedence over the ones specified for the whole Ingress resource.
+func (o *IngressEdgeLBPoolSpec) TimeoutsSpecFor(backend networkingv1.IngressServiceBackend) *EdgeLBTimeoutsSpec {
+	return o.Timeouts.MergedWith(o.BackendSpecFor(backend).Timeouts)
+}
+
+// RewriteSpecFor returns the specification of the HTTP rewriting to perform on requests and responses going through the specified Ingress backend.
+// Backend-level values take precedence over Ingress-level ones, and the returned object is never nil.
+func (o *IngressEdgeLBPoolSpec) RewriteSpecFor(backend networkingv1.IngressServiceBackend) *IngressEdgeLBPoolRewriteSpec {
+	res := &IngressEdgeLBPoolRewriteSpec{}
+	for _, r := range []*IngressEdgeLBPoolRewriteSpec{o.Rewrite, o.BackendSpecFor(backend).Rewrite} {
+		if r == nil {
+			continue
+		}
+		if r.ForwardedFor != nil {
+			res.ForwardedFor = r.ForwardedFor
+		}
+		if r.RewriteTarget != nil {
+			res.RewriteTarget = r.RewriteTarget
+		}
+		if r.RewriteLocation != nil {
+			res.RewriteLocation = r.RewriteLocation
+		}
+		if r.UpstreamHost != nil {
+			res.UpstreamHost = r.UpstreamHost
+		}
+	}
+	return res
+}
+
+// AffinitySpecFor returns the specification of the cookie-based session affinity to use for the specified Ingress backend, or nil if none has been provided.
+// Backend-level values take precedence over Ingress-level ones, and default values are set whenever a value hasn't been specifically provided.
+func (o *IngressEdgeLBPoolSpec) AffinitySpecFor(backend networkingv1.IngressServiceBackend) *IngressEdgeLBPoolAffinitySpec {
+	var res *IngressEdgeLBPoolAffinitySpec
+	for _, a := range []*IngressEdgeLBPoolAffinitySpec{o.Affinity, o.BackendSpecFor(backend).Affinity} {
+		if a == nil {
+			continue
+		}
+		if res == nil {
+			res = &IngressEdgeLBPoolAffinitySpec{}
+		}
+		if a.CookieName != nil {
+			res.CookieName = a.CookieName
+		}
+		if a.Mode != nil {
+			res.Mode = a.Mode
+		}
+		if a.TTL != nil {
+			res.TTL = a.TTL
+		}
+	}
+	if res == nil {
+		return nil
+	}
+	if res.CookieName == nil || *res.CookieName == "" {
+		res.CookieName = pointers.NewString(DefaultIngressAffinityCookieName)
+	}
+	if res.Mode == nil || *res.Mode == "" {
+		res.Mode = pointers.NewString(DefaultIngressAffinityMode)
+	}
+	return res
+}
+
+// BasicAuthSecretNames returns the names of the Secret resources holding the credentials of the users allowed to access the Ingress resource.
+func (o *IngressEdgeLBPoolSpec) BasicAuthSecretNames() []string {
+	if o.BasicAuth == nil {
+		return []string{}
+	}
+	return []string{*o.BasicAuth.SecretName}
+}
+
+// CASecretNames returns the (unique) names of the Secret resources holding the CA bundles used to verify the HTTPS backends referenced by the specified Ingress resource, as well as the certificates presented by its clients.
+func (o *IngressEdgeLBPoolSpec) CASecretNames(ingress *networkingv1.Ingress) []string {
+	res := make([]string, 0)
+	seen := make(map[string]bool)
+	if o.Frontends.HTTPS != nil && o.Frontends.HTTPS.ClientAuth != nil {
+		seen[*o.Frontends.HTTPS.ClientAuth.CASecretName] = true
+		res = append(res, *o.Frontends.HTTPS.ClientAuth.CASecretName)
+	}
+	kubernetesutil.ForEachIngresBackend(ingress, func(_ *string, _ *networkingv1.HTTPIngressPath, backend networkingv1.IngressServiceBackend) {
+		backendSpec := o.BackendSpecFor(backend)
+		if *backendSpec.BackendProtocol != IngressBackendProtocolHTTPS || seen[*backendSpec.CASecretName] {
+			return
+		}
+		seen[*backendSpec.CASecretName] = true
+		res = append(res, *backendSpec.CASecretName)
+	})
+	return res
 }
 
 // ValidateTransition validates the transition between "previous" and the current object.
@@ -113,3 +577,329 @@ func isValidHTTPMode(mode string) error {
 	}
 	return fmt.Errorf("invalid mode")
 }
+
+// validate checks whether the current object is valid.
+func (o *IngressEdgeLBPoolBackendSpec) validate() error {
+	if o.ServiceName == "" {
+		return fmt.Errorf(".serviceName must be specified")
+	}
+	if o.ServicePort == "" {
+		return fmt.Errorf(".servicePort must be specified")
+	}
+	if err := o.BaseEdgeLBPoolBackendSpec.Validate(); err != nil {
+		return err
+	}
+	if o.Rewrite != nil {
+		if err := o.Rewrite.validate(); err != nil {
+			return fmt.Errorf(".rewrite%v", err)
+		}
+	}
+	if o.Affinity != nil {
+		if err := o.Affinity.validate(); err != nil {
+			return fmt.Errorf(".affinity%v", err)
+		}
+	}
+	if o.MaxConn != nil && *o.MaxConn <= 0 {
+		return fmt.Errorf(".maxConn %d must be positive", *o.MaxConn)
+	}
+	// Client-side timeouts are applied to EdgeLB frontends, which are shared by every Ingress backend.
+	if o.Timeouts != nil && (o.Timeouts.Client != nil || o.Timeouts.HTTPRequest != nil) {
+		return fmt.Errorf(".timeouts.client and .timeouts.httpRequest can only be specified for the whole ingress")
+	}
+	seenCanaries := make(map[string]bool, len(o.Canaries))
+	for idx, canary := range o.Canaries {
+		if err := canary.validate(); err != nil {
+			return fmt.Errorf(".canaries[%d]%v", idx, err)
+		}
+		key := canary.ServiceName + ":" + canary.ServicePort
+		if key == o.ServiceName+":"+o.ServicePort {
+			return fmt.Errorf(".canaries[%d]: the target service port %q cannot be its own canary", idx, key)
+		}
+		if seenCanaries[key] {
+			return fmt.Errorf(".canaries[%d]: duplicate canary for service port %q", idx, key)
+		}
+		seenCanaries[key] = true
+	}
+	if weight := o.PrimaryWeight(); weight < 0 {
+		return fmt.Errorf(".canaries: the sum of the weights of all canaries must not exceed 100 (got %d)", 100-weight)
+	}
+	switch *o.BackendProtocol {
+	case IngressBackendProtocolHTTPS:
+		if o.CASecretName == nil || *o.CASecretName == "" {
+			return fmt.Errorf(".caSecretName must be specified when .backendProtocol is %s", *o.BackendProtocol)
+		}
+	case IngressBackendProtocolH2C, IngressBackendProtocolHTTP:
+		if o.CASecretName != nil {
+			return fmt.Errorf(".caSecretName can only be specified when .backendProtocol is %s", IngressBackendProtocolHTTPS)
+		}
+	default:
+		return fmt.Errorf(".backendProtocol %s is not a valid backend protocol", *o.BackendProtocol)
+	}
+	return nil
+}
+
+// validate checks whether the current object is valid.
+func (o *IngressEdgeLBPoolCanarySpec) validate() error {
+	if o.ServiceName == "" {
+		return fmt.Errorf(".serviceName must be specified")
+	}
+	if o.ServicePort == "" {
+		return fmt.Errorf(".servicePort must be specified")
+	}
+	if *o.Weight < 0 || *o.Weight > 100 {
+		return fmt.Errorf(".weight %d must be between 0 and 100", *o.Weight)
+	}
+	if o.Header != nil {
+		if err := o.Header.validate(headerNameRegex); err != nil {
+			return fmt.Errorf(".header%v", err)
+		}
+	}
+	if o.Cookie != nil {
+		if err := o.Cookie.validate(cookieNameRegex); err != nil {
+			return fmt.Errorf(".cookie%v", err)
+		}
+	}
+	return nil
+}
+
+// validate checks whether the current object is valid, using the specified regular expression to validate its name.
+func (o *IngressEdgeLBPoolCanaryMatchSpec) validate(nameRegex *regexp.Regexp) error {
+	if !nameRegex.MatchString(o.Name) {
+		return fmt.Errorf(".name %q is not a valid name", o.Name)
+	}
+	if !canaryMatchValueRegex.MatchString(o.Value) {
+		return fmt.Errorf(".value %q is not a valid value (must be a non-empty token)", o.Value)
+	}
+	return nil
+}
+
+// validate checks whether the current object is valid.
+func (o *IngressEdgeLBPoolRewriteSpec) validate() error {
+	if o.RewriteTarget != nil && !strings.HasPrefix(*o.RewriteTarget, "/") {
+		return fmt.Errorf(".rewriteTarget %q must start with \"/\"", *o.RewriteTarget)
+	}
+	if o.UpstreamHost != nil {
+		if errs := validation.IsDNS1123Subdomain(*o.UpstreamHost); len(errs) > 0 {
+			return fmt.Errorf(".upstreamHost %q is not a valid host: %s", *o.UpstreamHost, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+// validate checks whether the current object is valid.
+func (o *IngressEdgeLBPoolAffinitySpec) validate() error {
+	if o.CookieName != nil && !cookieNameRegex.MatchString(*o.CookieName) {
+		return fmt.Errorf(".cookieName %q is not a valid cookie name", *o.CookieName)
+	}
+	if o.Mode != nil {
+		switch *o.Mode {
+		case IngressAffinityModeInsert, IngressAffinityModeNone, IngressAffinityModePrefix:
+		default:
+			return fmt.Errorf(".mode %s is not a valid affinity mode", *o.Mode)
+		}
+	}
+	if o.TTL != nil {
+		if err := isValidPositiveDuration(*o.TTL); err != nil {
+			return fmt.Errorf(".ttl %v", err)
+		}
+	}
+	return nil
+}
+
+// validate checks whether the current object is valid.
+func (o *IngressEdgeLBPoolBasicAuthSpec) validate() error {
+	if o.SecretName == nil || *o.SecretName == "" {
+		return fmt.Errorf(".secretName must be specified")
+	}
+	if !basicAuthRealmRegex.MatchString(*o.Realm) {
+		return fmt.Errorf(".realm %q is not a valid realm (must consist of alphanumeric characters, '-', '_' or '.')", *o.Realm)
+	}
+	return nil
+}
+
+// validate checks whether the current object is valid.
+func (o *IngressEdgeLBPoolClientAuthSpec) validate() error {
+	if o.CASecretName == nil || *o.CASecretName == "" {
+		return fmt.Errorf(".caSecretName must be specified")
+	}
+	if !headerNameRegex.MatchString(*o.SubjectHeader) {
+		return fmt.Errorf(".subjectHeader %q is not a valid header name", *o.SubjectHeader)
+	}
+	switch *o.Verify {
+	case IngressClientAuthVerifyOptional, IngressClientAuthVerifyRequired:
+		return nil
+	}
+	return fmt.Errorf(".verify %q is not a valid verification mode (must be one of %q or %q)", *o.Verify, IngressClientAuthVerifyRequired, IngressClientAuthVerifyOptional)
+}
+
+// validate checks whether the current object is valid.
+func (o *IngressEdgeLBPoolHeaderActionsSpec) validate() error {
+	if o == nil {
+		return nil
+	}
+	for _, list := range []struct {
+		field   string
+		headers []IngressEdgeLBPoolHeaderSpec
+	}{
+		{field: "add", headers: o.Add},
+		{field: "set", headers: o.Set},
+	} {
+		for idx, header := range list.headers {
+			if !headerNameRegex.MatchString(header.Name) {
+				return fmt.Errorf(".%s[%d].name %q is not a valid header name", list.field, idx, header.Name)
+			}
+			if strings.ContainsAny(header.Value, "\r\n") {
+				return fmt.Errorf(".%s[%d].value must not contain line breaks", list.field, idx)
+			}
+		}
+	}
+	for idx, name := range o.Remove {
+		if !headerNameRegex.MatchString(name) {
+			return fmt.Errorf(".remove[%d] %q is not a valid header name", idx, name)
+		}
+	}
+	return nil
+}
+
+// validate checks whether the current object is valid.
+func (o *IngressEdgeLBPoolRateLimitSpec) validate() error {
+	if o.Requests == nil || *o.Requests <= 0 {
+		return fmt.Errorf(".requests must be specified and positive")
+	}
+	if *o.Burst < 0 {
+		return fmt.Errorf(".burst %d must not be negative", *o.Burst)
+	}
+	if err := isValidPositiveDuration(*o.Period); err != nil {
+		return fmt.Errorf(".period %v", err)
+	}
+	return nil
+}
+
+// isValidHost returns an error if host is a malformed wildcard host.
+// A wildcard host must consist of a single "*" label followed by a valid DNS subdomain (e.g. "*.example.com").
+func isValidHost(host string) error {
+	if !strings.Contains(host, "*") {
+		return nil
+	}
+	if errs := validation.IsWildcardDNS1123Subdomain(host); len(errs) > 0 {
+		return fmt.Errorf("host %q is not a valid wildcard host: %s", host, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// isValidPathType returns an error if pathType is not one of: Exact, Prefix or ImplementationSpecific.
+func isValidPathType(pathType string) error {
+	switch pathType {
+	case IngressPathTypeExact, IngressPathTypeImplementationSpecific, IngressPathTypePrefix:
+		return nil
+	}
+	return fmt.Errorf("invalid path type")
+}
+
+// isValidPath returns an error if path cannot be used with the specified path type.
+func isValidPath(path, pathType string) error {
+	switch pathType {
+	case IngressPathTypeExact, IngressPathTypePrefix:
+		// "Exact" and "Prefix" paths must be absolute.
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("path %q must start with \"/\" when the path type is %s", path, pathType)
+		}
+	default:
+		// "ImplementationSpecific" paths must be egrep (IEEE Std 1003.1) regular expressions, which are converted to PCRE before being handed over to EdgeLB.
+		// As Go's regular expression syntax (RE2) is only a subset of PCRE, only paths whose converted regular expression is valid in RE2 and has the same meaning in both syntaxes are supported.
+		// It is the converted regular expression that is checked, as egrep bracket expressions such as "[a-z\]" are not valid in Go's POSIX syntax.
+		expr := ConvertEgrepToPCRE(path)
+		if _, err := regexp.Compile(expr); err != nil {
+			return fmt.Errorf("path %q is not a supported regular expression: %v", path, err)
+		}
+		if escape := computeAmbiguousEscape(expr); escape != "" {
+			return fmt.Errorf("path %q is not a supported regular expression: escape sequence %q has a different meaning in pcre", path, escape)
+		}
+	}
+	return nil
+}
+
+// computeAmbiguousEscape returns the first escape sequence in the specified regular expression that is valid in RE2 but has a different meaning in PCRE, or an empty string in case there is none.
+// These are "\v" (a vertical tab in RE2, but any vertical whitespace character in PCRE) and "\1" to "\9" (octal character codes in RE2, but possibly back-references in PCRE).
+func computeAmbiguousEscape(expr string) string {
+	for i := 0; i+1 < len(expr); i++ {
+		if expr[i] != '\\' {
+			continue
+		}
+		if c := expr[i+1]; c == 'v' || (c >= '1' && c <= '9') {
+			return expr[i : i+2]
+		}
+		// Skip the escaped character, so that an escaped backslash is not mistaken for the start of an escape sequence.
+		i++
+	}
+	return ""
+}
+
+// ConvertEgrepToPCRE converts the specified egrep (IEEE Std 1003.1) regular expression into an equivalent PCRE regular expression.
+// The syntaxes are mostly compatible, except for bracket expressions, where a backslash is a literal character in egrep and an escape character in PCRE, and where collating symbols and equivalence classes are not supported by PCRE.
+func ConvertEgrepToPCRE(expr string) string {
+	b := strings.Builder{}
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		// Copy escaped characters outside bracket expressions as-is.
+		if c == '\\' && i+1 < len(expr) {
+			b.WriteString(expr[i : i+2])
+			i++
+			continue
+		}
+		if c != '[' {
+			b.WriteByte(c)
+			continue
+		}
+		// We're at the start of a bracket expression.
+		b.WriteByte(c)
+		i++
+		if i < len(expr) && expr[i] == '^' {
+			b.WriteByte('^')
+			i++
+		}
+		// A "]" appearing first in a bracket expression is a literal character.
+		if i < len(expr) && expr[i] == ']' {
+			b.WriteString(`\]`)
+			i++
+		}
+		for ; i < len(expr) && expr[i] != ']'; i++ {
+			switch {
+			case expr[i] == '\\':
+				b.WriteString(`\\`)
+			case expr[i] == '[' && i+1 < len(expr) && strings.IndexByte(":.=", expr[i+1]) >= 0:
+				delim := expr[i+1]
+				end := strings.Index(expr[i+2:], string(delim)+"]")
+				if end < 0 {
+					b.WriteString(`\[`)
+					continue
+				}
+				inner := expr[i+2 : i+2+end]
+				if delim == ':' {
+					// Character classes (e.g. "[:alpha:]") are supported by PCRE.
+					b.WriteString("[:" + inner + ":]")
+				} else {
+					// Replace collating symbols and equivalence classes with the (escaped) characters they contain.
+					for j := 0; j < len(inner); j++ {
+						if !isAlphanumeric(inner[j]) {
+							b.WriteByte('\\')
+						}
+						b.WriteByte(inner[j])
+					}
+				}
+				i += end + 3
+			default:
+				b.WriteByte(expr[i])
+			}
+		}
+		if i < len(expr) {
+			b.WriteByte(']')
+		}
+	}
+	return b.String()
+}
+
+// isAlphanumeric indicates whether the specified character is an ASCII letter or digit.
+func isAlphanumeric(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
@@ -4,11 +4,13 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
-	extsv1beta1 "k8s.io/api/extensions/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/mesosphere/dklb/pkg/cluster"
 	"github.com/mesosphere/dklb/pkg/constants"
+	"github.com/mesosphere/dklb/pkg/util/pointers"
 )
 
 func TestGetIngressEdgeLBPoolSpec(t *testing.T) {
@@ -17,19 +19,19 @@ func TestGetIngressEdgeLBPoolSpec(t *testing.T) {
 	tests := []struct {
 		description   string
 		expectedError error
-		ingress       *extsv1beta1.Ingress
+		ingress       *networkingv1.Ingress
 		validate      func(t *testing.T, spec *IngressEdgeLBPoolSpec)
 	}{
 		{
 			description:   "should translate to an edgelb pool with defaults",
 			expectedError: nil,
-			ingress: &extsv1beta1.Ingress{
+			ingress: &networkingv1.Ingress{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: "test-namespace",
 					Name:      "test-ingress",
 				},
-				Spec: extsv1beta1.IngressSpec{
-					TLS: []extsv1beta1.IngressTLS{
+				Spec: networkingv1.IngressSpec{
+					TLS: []networkingv1.IngressTLS{
 						{SecretName: "test-secret"},
 					},
 				},
@@ -43,7 +45,7 @@ func TestGetIngressEdgeLBPoolSpec(t *testing.T) {
 		{
 			description:   "should translate to an edgelb pool with custom frontend",
 			expectedError: nil,
-			ingress: &extsv1beta1.Ingress{
+			ingress: &networkingv1.Ingress{
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{
 						constants.DklbConfigAnnotationKey: `
@@ -57,8 +59,8 @@ func TestGetIngressEdgeLBPoolSpec(t *testing.T) {
 					Namespace: "test-namespace",
 					Name:      "test-ingress",
 				},
-				Spec: extsv1beta1.IngressSpec{
-					TLS: []extsv1beta1.IngressTLS{
+				Spec: networkingv1.IngressSpec{
+					TLS: []networkingv1.IngressTLS{
 						{SecretName: "test-secret"},
 					},
 				},
@@ -86,12 +88,12 @@ func TestGetIngressEdgeLBPoolSpecConstraints(t *testing.T) {
 	tests := []struct {
 		description   string
 		expectedError error
-		ingress       *extsv1beta1.Ingress
+		ingress       *networkingv1.Ingress
 		validate      func(t *testing.T, spec *IngressEdgeLBPoolSpec)
 	}{
 		{
 			description: "should translate to an edgelb pool without a constraint",
-			ingress: &extsv1beta1.Ingress{
+			ingress: &networkingv1.Ingress{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: "test-namespace",
 					Name:      "test-ingress",
@@ -106,7 +108,7 @@ func TestGetIngressEdgeLBPoolSpecConstraints(t *testing.T) {
 		},
 		{
 			description: "should translate to an edgelb pool with a constraint",
-			ingress: &extsv1beta1.Ingress{
+			ingress: &networkingv1.Ingress{
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{
 						constants.DklbConfigAnnotationKey: `
@@ -133,3 +135,932 @@ constraints: "[[\"hostname\",\"MAX_PER\",\"1\"],[\"@zone\",\"GROUP_BY\",\"3\"]]"
 		test.validate(t, spec)
 	}
 }
+
+func TestGetIngressEdgeLBPoolSpecPathType(t *testing.T) {
+	// cluster name really shouldn't be a global
+	cluster.Name = "test-cluster"
+	newIngress := func(config, path string, fns ...func(path *networkingv1.HTTPIngressPath)) *networkingv1.Ingress {
+		p := networkingv1.HTTPIngressPath{
+			Path: path,
+			Backend: networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{
+					Name: "test-service",
+					Port: networkingv1.ServiceBackendPort{Number: 80},
+				},
+			},
+		}
+		for _, fn := range fns {
+			fn(&p)
+		}
+		return &networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					constants.DklbConfigAnnotationKey: config,
+				},
+				Namespace: "test-namespace",
+				Name:      "test-ingress",
+			},
+			Spec: networkingv1.IngressSpec{
+				Rules: []networkingv1.IngressRule{
+					{
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{
+									p,
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	tests := []struct {
+		description      string
+		ingress          *networkingv1.Ingress
+		expectedError    bool
+		expectedPathType string
+	}{
+		{
+			description:      "should default to the implementation-specific path type",
+			ingress:          newIngress("", "/foo/.*"),
+			expectedPathType: IngressPathTypeImplementationSpecific,
+		},
+		{
+			description:      "should accept the prefix path type",
+			ingress:          newIngress("pathType: Prefix", "/foo"),
+			expectedPathType: IngressPathTypePrefix,
+		},
+		{
+			description:   "should reject an invalid path type",
+			ingress:       newIngress("pathType: Regex", "/foo"),
+			expectedError: true,
+		},
+		{
+			description:   "should reject a relative exact path",
+			ingress:       newIngress("pathType: Exact", "foo"),
+			expectedError: true,
+		},
+		{
+			description:      "should accept a backslash inside a bracket expression",
+			ingress:          newIngress("pathType: ImplementationSpecific", `/foo/[a-z\]+`),
+			expectedPathType: IngressPathTypeImplementationSpecific,
+		},
+		{
+			description:   "should reject an invalid regular expression",
+			ingress:       newIngress("pathType: ImplementationSpecific", "/foo/[a-"),
+			expectedError: true,
+		},
+		{
+			description:   "should reject a back-reference",
+			ingress:       newIngress("pathType: ImplementationSpecific", `/(foo)/\1`),
+			expectedError: true,
+		},
+		{
+			description:   "should reject a lookahead",
+			ingress:       newIngress("pathType: ImplementationSpecific", "/foo(?=bar)"),
+			expectedError: true,
+		},
+		{
+			description:   "should reject a vertical tab escape sequence, which has a different meaning in pcre",
+			ingress:       newIngress("pathType: ImplementationSpecific", `/foo\v`),
+			expectedError: true,
+		},
+		{
+			description:   "should reject an octal escape sequence, which may be a back-reference in pcre",
+			ingress:       newIngress("pathType: ImplementationSpecific", `/foo\12`),
+			expectedError: true,
+		},
+		{
+			description:      "should accept an escaped backslash followed by a digit",
+			ingress:          newIngress("pathType: ImplementationSpecific", `/foo\\1`),
+			expectedPathType: IngressPathTypeImplementationSpecific,
+		},
+		{
+			description: "should honor the path type of the path over the configured one",
+			ingress: newIngress("pathType: ImplementationSpecific", "foo", func(path *networkingv1.HTTPIngressPath) {
+				pathType := networkingv1.PathTypeExact
+				path.PathType = &pathType
+			}),
+			expectedError: true,
+		},
+		{
+			description: "should use the configured path type for an implementation-specific path",
+			ingress: newIngress("pathType: Exact", "foo", func(path *networkingv1.HTTPIngressPath) {
+				pathType := networkingv1.PathTypeImplementationSpecific
+				path.PathType = &pathType
+			}),
+			expectedError: true,
+		},
+		{
+			description: "should reject a resource backend",
+			ingress: newIngress("", "/foo", func(path *networkingv1.HTTPIngressPath) {
+				path.Backend = networkingv1.IngressBackend{
+					Resource: &corev1.TypedLocalObjectReference{Kind: "Bucket", Name: "test-bucket"},
+				}
+			}),
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+
+		spec, err := GetIngressEdgeLBPoolSpec(test.ingress)
+		if test.expectedError {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, test.expectedPathType, *spec.PathType)
+	}
+}
+
+func TestGetIngressEdgeLBPoolSpecWildcardHost(t *testing.T) {
+	// cluster name really shouldn't be a global
+	cluster.Name = "test-cluster"
+	tests := []struct {
+		description   string
+		host          string
+		expectedError bool
+	}{
+		{
+			description: "should accept an exact host",
+			host:        "foo.example.com",
+		},
+		{
+			description: "should accept a wildcard host",
+			host:        "*.example.com",
+		},
+		{
+			description:   "should reject a wildcard in the middle of the host",
+			host:          "foo.*.example.com",
+			expectedError: true,
+		},
+		{
+			description:   "should reject a partial wildcard label",
+			host:          "foo*.example.com",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+
+		_, err := GetIngressEdgeLBPoolSpec(&networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					constants.DklbConfigAnnotationKey: "name: test-pool",
+				},
+				Namespace: "test-namespace",
+				Name:      "test-ingress",
+			},
+			Spec: networkingv1.IngressSpec{
+				Rules: []networkingv1.IngressRule{
+					{Host: test.host},
+				},
+			},
+		})
+		assert.Equal(t, test.expectedError, err != nil)
+	}
+}
+
+func TestGetIngressEdgeLBPoolSpecBackends(t *testing.T) {
+	// cluster name really shouldn't be a global
+	cluster.Name = "test-cluster"
+	tests := []struct {
+		description   string
+		config        string
+		expectedError bool
+		validate      func(t *testing.T, spec *IngressEdgeLBPoolSpec)
+	}{
+		{
+			description: "should default the backend protocol to http",
+			config: `
+backends:
+- serviceName: test-service
+  servicePort: 80
+`,
+			validate: func(t *testing.T, spec *IngressEdgeLBPoolSpec) {
+				assert.Equal(t, IngressBackendProtocolHTTP, *spec.Backends[0].BackendProtocol)
+				assert.Equal(t, "80", spec.Backends[0].ServicePort)
+			},
+		},
+		{
+			description: "should accept an https backend with a ca secret",
+			config: `
+backends:
+- serviceName: test-service
+  servicePort: https
+  backendProtocol: HTTPS
+  caSecretName: test-ca
+`,
+			validate: func(t *testing.T, spec *IngressEdgeLBPoolSpec) {
+				assert.Equal(t, IngressBackendProtocolHTTPS, *spec.Backends[0].BackendProtocol)
+				assert.Equal(t, "test-ca", *spec.Backends[0].CASecretName)
+			},
+		},
+		{
+			description: "should reject an https backend without a ca secret",
+			config: `
+backends:
+- serviceName: test-service
+  servicePort: https
+  backendProtocol: HTTPS
+`,
+			expectedError: true,
+		},
+		{
+			description: "should reject an invalid backend protocol",
+			config: `
+backends:
+- serviceName: test-service
+  servicePort: 80
+  backendProtocol: GRPC
+`,
+			expectedError: true,
+		},
+		{
+			description: "should accept valid allowed source ranges",
+			config: `
+allowedSourceRanges:
+- 10.0.0.0/8
+- 2001:db8::/32
+`,
+			validate: func(t *testing.T, spec *IngressEdgeLBPoolSpec) {
+				assert.Equal(t, []string{"10.0.0.0/8", "2001:db8::/32"}, spec.AllowedSourceRanges)
+			},
+		},
+		{
+			description: "should reject an invalid allowed source range",
+			config: `
+allowedSourceRanges:
+- 10.0.0.0/33
+`,
+			expectedError: true,
+		},
+		{
+			description: "should default the rate limiting period and burst",
+			config: `
+rateLimit:
+  requests: 100
+frontends:
+  http:
+    maxConn: 1000
+backends:
+- serviceName: test-service
+  servicePort: 80
+  maxConn: 50
+`,
+			validate: func(t *testing.T, spec *IngressEdgeLBPoolSpec) {
+				assert.Equal(t, int32(100), *spec.RateLimit.Requests)
+				assert.Equal(t, int32(0), *spec.RateLimit.Burst)
+				assert.Equal(t, DefaultIngressRateLimitPeriod, *spec.RateLimit.Period)
+				assert.Equal(t, int32(1000), *spec.Frontends.HTTP.MaxConn)
+				assert.Equal(t, int32(50), *spec.Backends[0].MaxConn)
+			},
+		},
+		{
+			description: "should reject a rate limit without a number of requests",
+			config: `
+rateLimit:
+  period: 10s
+`,
+			expectedError: true,
+		},
+		{
+			description: "should reject a negative burst",
+			config: `
+rateLimit:
+  requests: 100
+  burst: -1
+`,
+			expectedError: true,
+		},
+		{
+			description: "should reject a non-positive backend maxconn",
+			config: `
+backends:
+- serviceName: test-service
+  servicePort: 80
+  maxConn: 0
+`,
+			expectedError: true,
+		},
+		{
+			description: "should default the basic auth realm",
+			config: `
+basicAuth:
+  secretName: test-auth
+`,
+			validate: func(t *testing.T, spec *IngressEdgeLBPoolSpec) {
+				assert.Equal(t, DefaultIngressBasicAuthRealm, *spec.BasicAuth.Realm)
+				assert.Equal(t, []string{"test-auth"}, spec.BasicAuthSecretNames())
+			},
+		},
+		{
+			description: "should reject basic auth without a secret",
+			config: `
+basicAuth:
+  realm: dashboards
+`,
+			expectedError: true,
+		},
+		{
+			description: "should reject an invalid basic auth realm",
+			config: `
+basicAuth:
+  secretName: test-auth
+  realm: "my dashboards"
+`,
+			expectedError: true,
+		},
+		{
+			description: "should default the hsts max age",
+			config: `
+frontends:
+  https:
+    hsts:
+      includeSubDomains: true
+headers:
+  request:
+    set:
+    - name: X-Request-ID
+      value: "%[uuid()]"
+  response:
+    remove:
+    - Server
+`,
+			validate: func(t *testing.T, spec *IngressEdgeLBPoolSpec) {
+				assert.Equal(t, DefaultIngressHSTSMaxAge, *spec.Frontends.HTTPS.HSTS.MaxAge)
+				assert.True(t, *spec.Frontends.HTTPS.HSTS.IncludeSubDomains)
+				assert.Equal(t, "X-Request-ID", spec.Headers.Request.Set[0].Name)
+				assert.Equal(t, []string{"Server"}, spec.Headers.Response.Remove)
+			},
+		},
+		{
+			description: "should default the hsts max age of an empty policy on an ingress without tls",
+			config: `
+frontends:
+  https:
+    port: 443
+    hsts: {}
+`,
+			validate: func(t *testing.T, spec *IngressEdgeLBPoolSpec) {
+				assert.Equal(t, DefaultIngressHSTSMaxAge, *spec.Frontends.HTTPS.HSTS.MaxAge)
+			},
+		},
+		{
+			description: "should reject a negative hsts max age",
+			config: `
+frontends:
+  https:
+    hsts:
+      maxAge: -1
+`,
+			expectedError: true,
+		},
+		{
+			description: "should reject an invalid header name",
+			config: `
+headers:
+  response:
+    add:
+    - name: "X Frame Options"
+      value: DENY
+`,
+			expectedError: true,
+		},
+		{
+			description: "should reject a header value containing a line break",
+			config: `
+headers:
+  request:
+    set:
+    - name: X-Custom
+      value: "foo\r\nbar: baz"
+`,
+			expectedError: true,
+		},
+		{
+			description: "should default the canary weight and compute the primary weight",
+			config: `
+backends:
+- serviceName: test-service
+  servicePort: 80
+  canaries:
+  - serviceName: test-canary
+    servicePort: 80
+    weight: 10
+  - serviceName: test-beta
+    servicePort: http
+    header:
+      name: X-Canary
+      value: beta
+`,
+			validate: func(t *testing.T, spec *IngressEdgeLBPoolSpec) {
+				assert.Equal(t, int32(0), *spec.Backends[0].Canaries[1].Weight)
+				assert.Equal(t, int32(90), spec.Backends[0].PrimaryWeight())
+				assert.False(t, spec.Backends[0].Canaries[0].IsForceable())
+				assert.True(t, spec.Backends[0].Canaries[1].IsForceable())
+				assert.Equal(t, "http", spec.Backends[0].Canaries[1].IngressBackend().Port.Name)
+			},
+		},
+		{
+			description: "should reject canary weights exceeding 100 in total",
+			config: `
+backends:
+- serviceName: test-service
+  servicePort: 80
+  canaries:
+  - serviceName: test-canary
+    servicePort: 80
+    weight: 60
+  - serviceName: test-beta
+    servicePort: 80
+    weight: 50
+`,
+			expectedError: true,
+		},
+		{
+			description: "should reject a backend being its own canary",
+			config: `
+backends:
+- serviceName: test-service
+  servicePort: 80
+  canaries:
+  - serviceName: test-service
+    servicePort: 80
+    weight: 10
+`,
+			expectedError: true,
+		},
+		{
+			description: "should reject an invalid canary cookie value",
+			config: `
+backends:
+- serviceName: test-service
+  servicePort: 80
+  canaries:
+  - serviceName: test-canary
+    servicePort: 80
+    cookie:
+      name: canary
+      value: "a b"
+`,
+			expectedError: true,
+		},
+		{
+			description: "should reject duplicate backends",
+			config: `
+backends:
+- serviceName: test-service
+  servicePort: 80
+- serviceName: test-service
+  servicePort: 80
+`,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+
+		spec, err := GetIngressEdgeLBPoolSpec(&networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					constants.DklbConfigAnnotationKey: "name: test-pool\n" + test.config,
+				},
+				Namespace: "test-namespace",
+				Name:      "test-ingress",
+			},
+		})
+		if test.expectedError {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		test.validate(t, spec)
+	}
+}
+
+func TestGetIngressEdgeLBPoolSpecRewrite(t *testing.T) {
+	// cluster name really shouldn't be a global
+	cluster.Name = "test-cluster"
+	newIngress := func(config string, paths ...string) *networkingv1.Ingress {
+		ingressPaths := make([]networkingv1.HTTPIngressPath, 0, len(paths))
+		for _, path := range paths {
+			ingressPaths = append(ingressPaths, networkingv1.HTTPIngressPath{
+				Path: path,
+				Backend: networkingv1.IngressBackend{
+					Service: &networkingv1.IngressServiceBackend{
+						Name: "test-service",
+						Port: networkingv1.ServiceBackendPort{Number: 80},
+					},
+				},
+			})
+		}
+		return &networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					constants.DklbConfigAnnotationKey: "name: test-pool\n" + config,
+				},
+				Namespace: "test-namespace",
+				Name:      "test-ingress",
+			},
+			Spec: networkingv1.IngressSpec{
+				Rules: []networkingv1.IngressRule{
+					{
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: ingressPaths,
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	tests := []struct {
+		description   string
+		ingress       *networkingv1.Ingress
+		expectedError bool
+	}{
+		{
+			description: "should accept a rewrite target for a prefix path",
+			ingress: newIngress(`
+pathType: Prefix
+rewrite:
+  rewriteTarget: /
+  rewriteLocation: true
+`, "/app"),
+		},
+		{
+			description: "should accept a backend-level upstream host",
+			ingress: newIngress(`
+backends:
+- serviceName: test-service
+  servicePort: 80
+  rewrite:
+    upstreamHost: app.internal
+`, "/app/.*"),
+		},
+		{
+			description: "should reject a relative rewrite target",
+			ingress: newIngress(`
+pathType: Prefix
+rewrite:
+  rewriteTarget: foo
+`, "/app"),
+			expectedError: true,
+		},
+		{
+			description: "should reject an invalid upstream host",
+			ingress: newIngress(`
+rewrite:
+  upstreamHost: "not a host"
+`, "/app"),
+			expectedError: true,
+		},
+		{
+			description: "should reject a rewrite target for implementation-specific paths",
+			ingress: newIngress(`
+rewrite:
+  rewriteTarget: /
+`, "/app"),
+			expectedError: true,
+		},
+		{
+			description: "should reject a rewrite target for a backend referenced by more than one path",
+			ingress: newIngress(`
+pathType: Prefix
+backends:
+- serviceName: test-service
+  servicePort: 80
+  rewrite:
+    rewriteTarget: /
+`, "/foo", "/bar"),
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+
+		_, err := GetIngressEdgeLBPoolSpec(test.ingress)
+		assert.Equal(t, test.expectedError, err != nil)
+	}
+}
+
+func TestIngressEdgeLBPoolSpec_RewriteSpecFor(t *testing.T) {
+	backend := networkingv1.IngressServiceBackend{
+		Name: "test-service",
+		Port: networkingv1.ServiceBackendPort{Number: 80},
+	}
+	spec := &IngressEdgeLBPoolSpec{
+		Backends: []*IngressEdgeLBPoolBackendSpec{
+			{
+				ServiceName: "test-service",
+				ServicePort: "80",
+				Rewrite: &IngressEdgeLBPoolRewriteSpec{
+					RewriteTarget: pointers.NewString("/backend"),
+				},
+			},
+		},
+		Rewrite: &IngressEdgeLBPoolRewriteSpec{
+			RewriteTarget: pointers.NewString("/ingress"),
+			UpstreamHost:  pointers.NewString("example.com"),
+		},
+	}
+
+	// Backend-level values must take precedence over Ingress-level ones.
+	res := spec.RewriteSpecFor(backend)
+	assert.Equal(t, "/backend", *res.RewriteTarget)
+	assert.Equal(t, "example.com", *res.UpstreamHost)
+	assert.Nil(t, res.ForwardedFor)
+	// Ingress-level values must apply to Ingress backends for which no specification has been provided.
+	res = spec.RewriteSpecFor(networkingv1.IngressServiceBackend{Name: "other-service", Port: networkingv1.ServiceBackendPort{Number: 80}})
+	assert.Equal(t, "/ingress", *res.RewriteTarget)
+}
+
+func TestGetIngressEdgeLBPoolSpecAffinity(t *testing.T) {
+	// cluster name really shouldn't be a global
+	cluster.Name = "test-cluster"
+	tests := []struct {
+		description   string
+		config        string
+		expectedError bool
+	}{
+		{
+			description: "should accept an ingress-level affinity with a ttl",
+			config: `
+affinity:
+  cookieName: SERVERID
+  ttl: 1h
+`,
+		},
+		{
+			description: "should accept a backend-level prefix affinity",
+			config: `
+backends:
+- serviceName: test-service
+  servicePort: 80
+  affinity:
+    cookieName: JSESSIONID
+    mode: prefix
+`,
+		},
+		{
+			description: "should reject an invalid affinity mode",
+			config: `
+affinity:
+  mode: rewrite
+`,
+			expectedError: true,
+		},
+		{
+			description: "should reject an invalid cookie name",
+			config: `
+affinity:
+  cookieName: "my cookie"
+`,
+			expectedError: true,
+		},
+		{
+			description: "should reject a ttl for a backend using prefix affinity",
+			config: `
+affinity:
+  ttl: 1h
+backends:
+- serviceName: test-service
+  servicePort: 80
+  affinity:
+    mode: prefix
+`,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+
+		_, err := GetIngressEdgeLBPoolSpec(&networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					constants.DklbConfigAnnotationKey: "name: test-pool\n" + test.config,
+				},
+				Namespace: "test-namespace",
+				Name:      "test-ingress",
+			},
+			Spec: networkingv1.IngressSpec{
+				DefaultBackend: &networkingv1.IngressBackend{
+					Service: &networkingv1.IngressServiceBackend{
+						Name: "test-service",
+						Port: networkingv1.ServiceBackendPort{Number: 80},
+					},
+				},
+			},
+		})
+		assert.Equal(t, test.expectedError, err != nil)
+	}
+}
+
+func TestIngressEdgeLBPoolSpec_AffinitySpecFor(t *testing.T) {
+	backend := networkingv1.IngressServiceBackend{
+		Name: "test-service",
+		Port: networkingv1.ServiceBackendPort{Number: 80},
+	}
+
+	// No session affinity must be used when none has been specified.
+	assert.Nil(t, (&IngressEdgeLBPoolSpec{}).AffinitySpecFor(backend))
+
+	// Backend-level values must take precedence over Ingress-level ones, and defaults must be set.
+	spec := &IngressEdgeLBPoolSpec{
+		Affinity: &IngressEdgeLBPoolAffinitySpec{
+			TTL: pointers.NewString("1h"),
+		},
+		Backends: []*IngressEdgeLBPoolBackendSpec{
+			{
+				ServiceName: "test-service",
+				ServicePort: "80",
+				Affinity: &IngressEdgeLBPoolAffinitySpec{
+					TTL: pointers.NewString("30m"),
+				},
+			},
+		},
+	}
+	res := spec.AffinitySpecFor(backend)
+	assert.Equal(t, DefaultIngressAffinityCookieName, *res.CookieName)
+	assert.Equal(t, IngressAffinityModeInsert, *res.Mode)
+	assert.Equal(t, "30m", *res.TTL)
+}
+
+func TestIngressEdgeLBPoolSpec_TimeoutsSpecFor(t *testing.T) {
+	backend := networkingv1.IngressServiceBackend{
+		Name: "test-service",
+		Port: networkingv1.ServiceBackendPort{Number: 80},
+	}
+
+	// No timeouts must be used when none have been specified.
+	assert.Nil(t, (&IngressEdgeLBPoolSpec{}).TimeoutsSpecFor(backend))
+
+	// Backend-level values must take precedence over Ingress-level ones.
+	spec := &IngressEdgeLBPoolSpec{
+		BaseEdgeLBPoolSpec: BaseEdgeLBPoolSpec{
+			Timeouts: &EdgeLBTimeoutsSpec{
+				Connect: pointers.NewString("5s"),
+				Server:  pointers.NewString("30s"),
+			},
+		},
+		Backends: []*IngressEdgeLBPoolBackendSpec{
+			{
+				ServiceName: "test-service",
+				ServicePort: "80",
+				BaseEdgeLBPoolBackendSpec: BaseEdgeLBPoolBackendSpec{
+					Timeouts: &EdgeLBTimeoutsSpec{
+						Tunnel: pointers.NewString("1h"),
+						Server: pointers.NewString("5m"),
+					},
+				},
+			},
+		},
+	}
+	res := spec.TimeoutsSpecFor(backend)
+	assert.Equal(t, "5s", *res.Connect)
+	assert.Equal(t, "5m", *res.Server)
+	assert.Equal(t, "1h", *res.Tunnel)
+}
+
+func TestGetIngressEdgeLBPoolSpecClientAuth(t *testing.T) {
+	// cluster name really shouldn't be a global
+	cluster.Name = "test-cluster"
+	tests := []struct {
+		description   string
+		config        string
+		expectedError bool
+		validate      func(t *testing.T, spec *IngressEdgeLBPoolSpec)
+	}{
+		{
+			description: "should apply default values and include the client ca secret",
+			config: `
+frontends:
+  http:
+    mode: disabled
+  https:
+    clientAuth:
+      caSecretName: client-ca
+`,
+			validate: func(t *testing.T, spec *IngressEdgeLBPoolSpec) {
+				assert.Equal(t, DefaultIngressClientAuthSubjectHeader, *spec.Frontends.HTTPS.ClientAuth.SubjectHeader)
+				assert.Equal(t, IngressClientAuthVerifyRequired, *spec.Frontends.HTTPS.ClientAuth.Verify)
+			},
+		},
+		{
+			description: "should accept optional verification and a custom header",
+			config: `
+frontends:
+  http:
+    mode: disabled
+  https:
+    clientAuth:
+      caSecretName: client-ca
+      subjectHeader: X-Client-DN
+      verify: optional
+`,
+			validate: func(t *testing.T, spec *IngressEdgeLBPoolSpec) {
+				assert.Equal(t, "X-Client-DN", *spec.Frontends.HTTPS.ClientAuth.SubjectHeader)
+				assert.Equal(t, IngressClientAuthVerifyOptional, *spec.Frontends.HTTPS.ClientAuth.Verify)
+			},
+		},
+		{
+			description: "should accept redirecting http requests to the https frontend",
+			config: `
+frontends:
+  http:
+    mode: redirect
+  https:
+    clientAuth:
+      caSecretName: client-ca
+`,
+			validate: func(t *testing.T, spec *IngressEdgeLBPoolSpec) {
+				assert.Equal(t, IngressEdgeLBHTTPModeRedirect, *spec.Frontends.HTTP.Mode)
+			},
+		},
+		{
+			description: "should reject serving requests over plain http",
+			config: `
+frontends:
+  https:
+    clientAuth:
+      caSecretName: client-ca
+`,
+			expectedError: true,
+		},
+		{
+			description: "should reject a missing client ca secret",
+			config: `
+frontends:
+  http:
+    mode: disabled
+  https:
+    clientAuth:
+      verify: optional
+`,
+			expectedError: true,
+		},
+		{
+			description: "should reject an unknown verification mode",
+			config: `
+frontends:
+  http:
+    mode: disabled
+  https:
+    clientAuth:
+      caSecretName: client-ca
+      verify: none
+`,
+			expectedError: true,
+		},
+		{
+			description: "should reject an invalid header name",
+			config: `
+frontends:
+  http:
+    mode: disabled
+  https:
+    clientAuth:
+      caSecretName: client-ca
+      subjectHeader: "X Client"
+`,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+
+		ingress := &networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					constants.DklbConfigAnnotationKey: test.config,
+				},
+				Namespace: "test-namespace",
+				Name:      "test-ingress",
+			},
+			Spec: networkingv1.IngressSpec{
+				TLS: []networkingv1.IngressTLS{
+					{
+						SecretName: "test-tls",
+					},
+				},
+			},
+		}
+		spec, err := GetIngressEdgeLBPoolSpec(ingress)
+		assert.Equal(t, test.expectedError, err != nil)
+		if err != nil {
+			continue
+		}
+		test.validate(t, spec)
+		assert.Equal(t, []string{"client-ca"}, spec.CASecretNames(ingress))
+	}
+}
@@ -5,19 +5,31 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/validation"
+
+	kubernetesutil "github.com/mesosphere/dklb/pkg/util/kubernetes"
 )
 
 // ServiceEdgeLBPoolFrontendSpec contains the specification of a single EdgeLB frontend associated with a given Service resource.
 type ServiceEdgeLBPoolFrontendSpec struct {
+	// Backend contains the specification of the EdgeLB backend associated with the current service port.
+	Backend *BaseEdgeLBPoolBackendSpec `yaml:"backend"`
 	// Port is the frontend bind port to use when exposing the current service port.
 	Port *int32 `yaml:"port"`
 	// ServicePort is the current service port.
 	ServicePort int32 `yaml:"servicePort"`
+	// SNIHostnames is the set of TLS server names (SNI) for which connections are routed to the current service port.
+	// When specified, the frontend bind port may be shared with other service ports (of the same or different Service resources) that specify different server names.
+	SNIHostnames []string `yaml:"sniHostnames"`
+	// TLSSecretName is the name of the Secret resource (of type "kubernetes.io/tls") holding the certificate and private key used to terminate TLS connections to the current service port.
+	TLSSecretName *string `yaml:"tlsSecretName"`
 }
 
 // ServiceEdgeLBPoolSpec contains the specification of the target EdgeLB pool for a given Service resource.
 type ServiceEdgeLBPoolSpec struct {
 	BaseEdgeLBPoolSpec `yaml:",inline"`
+	// AcceptProxy indicates whether the EdgeLB frontends associated with the Service resource expect client connection information to be passed using the PROXY protocol (v1 or v2).
+	// This is only allowed when a cloud-provider configuration is specified, as the cloud load balancer is expected to be the only client of said EdgeLB frontends.
+	AcceptProxy *bool `yaml:"acceptProxy"`
 	// Frontends contains the specification of the EdgeLB frontends associated with the Service resource.
 	Frontends []ServiceEdgeLBPoolFrontendSpec `yaml:"frontends"`
 }
@@ -38,16 +50,29 @@ func (o *ServiceEdgeLBPoolSpec) SetDefaults(service *corev1.Service) {
 	// By default, the frontend port is taken to be the same as service port.
 	// If a custom frontend port is specified for a given service port, that custom frontend port is used instead.
 	// During this process, frontends that don't correspond to any port defined on the Service resource are trimmed.
-	frontends := make([]ServiceEdgeLBPoolFrontendSpec, 0, len(service.Spec.Ports))
-	for _, port := range service.Spec.Ports {
+	// Service ports that cannot be exposed by EdgeLB (i.e. non-TCP ones) are ignored.
+	ports := kubernetesutil.SupportedServicePorts(service)
+	frontends := make([]ServiceEdgeLBPoolFrontendSpec, 0, len(ports))
+	// Any backend configuration specified for a given service port is preserved.
+	for _, port := range ports {
 		frontendPort := port.Port
+		var (
+			backend       *BaseEdgeLBPoolBackendSpec
+			sniHostnames  []string
+			tlsSecretName *string
+		)
 		for _, frontendSpec := range o.Frontends {
-			if frontendSpec.ServicePort == port.Port && frontendSpec.Port != nil {
-				frontendPort = *frontendSpec.Port
+			if frontendSpec.ServicePort == port.Port {
+				if frontendSpec.Port != nil {
+					frontendPort = *frontendSpec.Port
+				}
+				backend = frontendSpec.Backend
+				sniHostnames = frontendSpec.SNIHostnames
+				tlsSecretName = frontendSpec.TLSSecretName
 				break
 			}
 		}
-		frontends = append(frontends, ServiceEdgeLBPoolFrontendSpec{Port: &frontendPort, ServicePort: port.Port})
+		frontends = append(frontends, ServiceEdgeLBPoolFrontendSpec{Backend: backend, Port: &frontendPort, ServicePort: port.Port, SNIHostnames: sniHostnames, TLSSecretName: tlsSecretName})
 	}
 	o.Frontends = frontends
 }
@@ -62,12 +87,17 @@ func (o *ServiceEdgeLBPoolSpec) Validate(svc *corev1.Service) error {
 		return err
 	}
 
+	// Make sure that every service port can be exposed by EdgeLB, unless it shares its port number with one that can (e.g. DNS over UDP and TCP).
+	if _, unmatched := kubernetesutil.UnsupportedServicePorts(svc); len(unmatched) > 0 {
+		return fmt.Errorf("service port %d uses the %s protocol, which is not supported by edgelb (only TCP is supported)", unmatched[0].Port, unmatched[0].Protocol)
+	}
+
 	// visitedServicePorts contains the set of service ports that have already been visited.
 	// It is used to prevent duplicate mappings.
 	visitedServicePorts := make(map[int32]bool)
-	// visitedFrontendPorts contains the set of frontend ports that have already been visited.
-	// It is used to prevent duplicate mappings.
-	visitedFrontendPorts := make(map[int32]bool)
+	// visitedFrontendPorts contains the set of frontend ports that have already been visited, together with the SNI hostnames specified for each of them.
+	// It is used to prevent duplicate mappings, which are only allowed between service ports routed based on different SNI hostnames.
+	visitedFrontendPorts := make(map[int32]map[string]bool)
 	// Iterate over the set of frontends, validating each service and frontend port.
 	for _, fe := range o.Frontends {
 		// Make sure that the current service port is valid.
@@ -84,16 +114,120 @@ func (o *ServiceEdgeLBPoolSpec) Validate(svc *corev1.Service) error {
 		if validation.IsValidPortNum(int(*fe.Port)) != nil {
 			return fmt.Errorf("%d is not a valid port number", *fe.Port)
 		}
-		// Make sure that the current frontend port has not already been specified.
-		if _, exists := visitedFrontendPorts[*fe.Port]; exists {
+		// Make sure that the current frontend port has not already been specified, unless both service ports are routed based on SNI hostnames.
+		visitedSNIHostnames, exists := visitedFrontendPorts[*fe.Port]
+		if exists && (len(visitedSNIHostnames) == 0 || len(fe.SNIHostnames) == 0) {
 			return fmt.Errorf("frontend port %d has been specified twice", *fe.Port)
 		}
+		if !exists {
+			visitedSNIHostnames = make(map[string]bool, len(fe.SNIHostnames))
+		}
+		// Make sure that the SNI hostnames for the current service port are valid and have not already been specified for the current frontend port.
+		for _, hostname := range fe.SNIHostnames {
+			if errs := validation.IsDNS1123Subdomain(hostname); len(errs) > 0 {
+				return fmt.Errorf("service port %d: .sniHostnames: %q is not a valid hostname", fe.ServicePort, hostname)
+			}
+			if visitedSNIHostnames[hostname] {
+				return fmt.Errorf("service port %d: .sniHostnames: %q has been specified twice for frontend port %d", fe.ServicePort, hostname, *fe.Port)
+			}
+			visitedSNIHostnames[hostname] = true
+		}
+		if len(fe.SNIHostnames) > 0 && *o.CloudProviderConfiguration != "" {
+			return fmt.Errorf("service port %d: .sniHostnames cannot be specified when a cloud-provider configuration is specified", fe.ServicePort)
+		}
 		// Mark the current frontend port as having been visited.
-		visitedFrontendPorts[*fe.Port] = true
+		visitedFrontendPorts[*fe.Port] = visitedSNIHostnames
+		// Make sure that the secret used to terminate TLS connections (if any) has been specified, and that TLS connections are not routed based on SNI as well.
+		if fe.TLSSecretName != nil {
+			if *fe.TLSSecretName == "" {
+				return fmt.Errorf("service port %d: .tlsSecretName must not be empty", fe.ServicePort)
+			}
+			if len(fe.SNIHostnames) > 0 {
+				return fmt.Errorf("service port %d: .tlsSecretName and .sniHostnames cannot be specified together", fe.ServicePort)
+			}
+		}
+		// Make sure that the backend configuration for the current service port is valid.
+		if err := fe.Backend.Validate(); err != nil {
+			return fmt.Errorf("service port %d: .backend%v", fe.ServicePort, err)
+		}
+		// Make sure that no HTTP-specific timeout has been specified for the current service port.
+		if fe.Backend != nil && fe.Backend.Timeouts != nil && fe.Backend.Timeouts.HTTPRequest != nil {
+			return fmt.Errorf("service port %d: .backend.timeouts.httpRequest cannot be specified for services", fe.ServicePort)
+		}
+		// Make sure that no health-check path has been specified for the current service port in case nodes are health-checked using the Service resource's health-check node port.
+		if fe.Backend != nil && fe.Backend.HealthCheck != nil && fe.Backend.HealthCheck.Path != nil && !o.RoutesToPodIPs() && svc.Spec.ExternalTrafficPolicy == corev1.ServiceExternalTrafficPolicyTypeLocal {
+			return fmt.Errorf("service port %d: .backend.healthCheck.path cannot be specified for services whose external traffic policy is %q", fe.ServicePort, corev1.ServiceExternalTrafficPolicyTypeLocal)
+		}
+	}
+	// Make sure that the PROXY protocol is only accepted from a cloud load balancer.
+	if o.AcceptProxy != nil && *o.AcceptProxy && *o.CloudProviderConfiguration == "" {
+		return fmt.Errorf(".acceptProxy can only be specified when a cloud-provider configuration is specified")
+	}
+	// Make sure that no HTTP-specific timeout has been specified for the Service resource.
+	if o.Timeouts != nil && o.Timeouts.HTTPRequest != nil {
+		return fmt.Errorf(".timeouts.httpRequest cannot be specified for services")
+	}
+	// Make sure that the source ranges allowed to access the Service resource are valid.
+	if err := validateSourceRanges(svc.Spec.LoadBalancerSourceRanges); err != nil {
+		return fmt.Errorf(".spec.loadBalancerSourceRanges: %v", err)
+	}
+	return nil
+}
+
+// BackendSpecFor returns the specification of the EdgeLB backend associated with the specified service port, or nil if none has been provided.
+func (o *ServiceEdgeLBPoolSpec) BackendSpecFor(servicePort int32) *BaseEdgeLBPoolBackendSpec {
+	for _, fe := range o.Frontends {
+		if fe.ServicePort == servicePort {
+			return fe.Backend
+		}
+	}
+	return nil
+}
+
+// SNIHostnamesFor returns the SNI hostnames for which connections are routed to the specified service port, or nil if none have been provided.
+func (o *ServiceEdgeLBPoolSpec) SNIHostnamesFor(servicePort int32) []string {
+	for _, fe := range o.Frontends {
+		if fe.ServicePort == servicePort {
+			return fe.SNIHostnames
+		}
+	}
+	return nil
+}
+
+// TLSSecretNameFor returns the name of the Secret resource used to terminate TLS connections to the specified service port, or nil if TLS connections are not to be terminated.
+func (o *ServiceEdgeLBPoolSpec) TLSSecretNameFor(servicePort int32) *string {
+	for _, fe := range o.Frontends {
+		if fe.ServicePort == servicePort {
+			return fe.TLSSecretName
+		}
 	}
 	return nil
 }
 
+// TLSSecretNames returns the (unique) names of the Secret resources used to terminate TLS connections to the service ports of the Service resource.
+func (o *ServiceEdgeLBPoolSpec) TLSSecretNames() []string {
+	res := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, fe := range o.Frontends {
+		if fe.TLSSecretName == nil || seen[*fe.TLSSecretName] {
+			continue
+		}
+		seen[*fe.TLSSecretName] = true
+		res = append(res, *fe.TLSSecretName)
+	}
+	return res
+}
+
+// TimeoutsSpecFor returns the specification of the timeouts to use for the EdgeLB frontend and backend associated with the specified service port.
+// Values specified for the service port take precedence over the ones specified for the whole Service resource.
+func (o *ServiceEdgeLBPoolSpec) TimeoutsSpecFor(servicePort int32) *EdgeLBTimeoutsSpec {
+	var override *EdgeLBTimeoutsSpec
+	if backendSpec := o.BackendSpecFor(servicePort); backendSpec != nil {
+		override = backendSpec.Timeouts
+	}
+	return o.Timeouts.MergedWith(override)
+}
+
 // ValidateTransition validates the transition between "previous" and the current object.
 func (o *ServiceEdgeLBPoolSpec) ValidateTransition(previous *ServiceEdgeLBPoolSpec) error {
 	return o.BaseEdgeLBPoolSpec.ValidateTransition(&previous.BaseEdgeLBPoolSpec)
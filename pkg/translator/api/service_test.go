@@ -9,6 +9,7 @@ import (
 
 	"github.com/mesosphere/dklb/pkg/cluster"
 	"github.com/mesosphere/dklb/pkg/constants"
+	"github.com/mesosphere/dklb/pkg/util/pointers"
 )
 
 func TestGetServiceEdgeLBPoolSpecConstraints(t *testing.T) {
@@ -60,3 +61,552 @@ constraints: "[[\"hostname\",\"MAX_PER\",\"1\"],[\"@zone\",\"GROUP_BY\",\"3\"]]"
 		test.validate(t, spec)
 	}
 }
+
+func TestGetServiceEdgeLBPoolSpecSourceRanges(t *testing.T) {
+	// cluster name really shouldn't be a global
+	cluster.Name = "test-cluster"
+	tests := []struct {
+		description   string
+		sourceRanges  []string
+		expectedError bool
+	}{
+		{
+			description:  "should accept valid ipv4 and ipv6 source ranges",
+			sourceRanges: []string{"10.0.0.0/8", " 192.168.1.0/24", "2001:db8::/32"},
+		},
+		{
+			description:   "should reject a source range that is not a cidr",
+			sourceRanges:  []string{"10.0.0.1"},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+
+		_, err := GetServiceEdgeLBPoolSpec(&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "test-namespace",
+				Name:      "test-service",
+			},
+			Spec: corev1.ServiceSpec{
+				LoadBalancerSourceRanges: test.sourceRanges,
+			},
+		})
+		assert.Equal(t, test.expectedError, err != nil)
+	}
+}
+
+func TestServiceEdgeLBPoolSpec_TimeoutsSpecFor(t *testing.T) {
+	// No timeouts must be used when none have been specified.
+	assert.Nil(t, (&ServiceEdgeLBPoolSpec{}).TimeoutsSpecFor(80))
+
+	// Port-level values must take precedence over Service-level ones.
+	spec := &ServiceEdgeLBPoolSpec{
+		BaseEdgeLBPoolSpec: BaseEdgeLBPoolSpec{
+			Timeouts: &EdgeLBTimeoutsSpec{
+				Client: pointers.NewString("30s"),
+				Server: pointers.NewString("30s"),
+			},
+		},
+		Frontends: []ServiceEdgeLBPoolFrontendSpec{
+			{
+				ServicePort: 80,
+				Backend: &BaseEdgeLBPoolBackendSpec{
+					Timeouts: &EdgeLBTimeoutsSpec{
+						Server: pointers.NewString("5m"),
+					},
+				},
+			},
+		},
+	}
+	res := spec.TimeoutsSpecFor(80)
+	assert.Equal(t, "30s", *res.Client)
+	assert.Equal(t, "5m", *res.Server)
+	res = spec.TimeoutsSpecFor(443)
+	assert.Equal(t, "30s", *res.Client)
+	assert.Equal(t, "30s", *res.Server)
+}
+
+func TestGetServiceEdgeLBPoolSpecSNIHostnames(t *testing.T) {
+	// cluster name really shouldn't be a global
+	cluster.Name = "test-cluster"
+	tests := []struct {
+		description   string
+		config        string
+		expectedError bool
+	}{
+		{
+			description: "should accept service ports sharing a frontend port with different sni hostnames",
+			config: `
+frontends:
+- servicePort: 5432
+  port: 443
+  sniHostnames: ["db.example.com"]
+- servicePort: 8883
+  port: 443
+  sniHostnames: ["mqtt.example.com"]
+`,
+		},
+		{
+			description: "should reject service ports sharing a frontend port without sni hostnames",
+			config: `
+frontends:
+- servicePort: 5432
+  port: 443
+  sniHostnames: ["db.example.com"]
+- servicePort: 8883
+  port: 443
+`,
+			expectedError: true,
+		},
+		{
+			description: "should reject service ports sharing a frontend port with the same sni hostname",
+			config: `
+frontends:
+- servicePort: 5432
+  port: 443
+  sniHostnames: ["db.example.com"]
+- servicePort: 8883
+  port: 443
+  sniHostnames: ["db.example.com"]
+`,
+			expectedError: true,
+		},
+		{
+			description: "should reject terminating tls connections routed based on sni",
+			config: `
+frontends:
+- servicePort: 5432
+  sniHostnames: ["db.example.com"]
+  tlsSecretName: db-tls
+`,
+			expectedError: true,
+		},
+		{
+			description: "should reject an invalid sni hostname",
+			config: `
+frontends:
+- servicePort: 5432
+  sniHostnames: ["DB_example"]
+`,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+
+		spec, err := GetServiceEdgeLBPoolSpec(&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					constants.DklbConfigAnnotationKey: test.config,
+				},
+				Namespace: "test-namespace",
+				Name:      "test-service",
+			},
+			Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{
+					{Port: 5432},
+					{Port: 8883},
+				},
+			},
+		})
+		assert.Equal(t, test.expectedError, err != nil)
+		if err == nil {
+			assert.Equal(t, []string{"db.example.com"}, spec.SNIHostnamesFor(5432))
+		}
+	}
+}
+
+func TestGetServiceEdgeLBPoolSpecUnsupportedPorts(t *testing.T) {
+	// cluster name really shouldn't be a global
+	cluster.Name = "test-cluster"
+	tests := []struct {
+		description       string
+		ports             []corev1.ServicePort
+		expectedError     bool
+		expectedFrontends int
+	}{
+		{
+			description: "should reject a service exposing a udp-only port",
+			ports: []corev1.ServicePort{
+				{Port: 80, Protocol: corev1.ProtocolTCP},
+				{Port: 514, Protocol: corev1.ProtocolUDP},
+			},
+			expectedError: true,
+		},
+		{
+			description: "should ignore a udp port sharing its port number with a tcp port",
+			ports: []corev1.ServicePort{
+				{Port: 53, Protocol: corev1.ProtocolTCP},
+				{Port: 53, Protocol: corev1.ProtocolUDP},
+			},
+			expectedFrontends: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+
+		spec, err := GetServiceEdgeLBPoolSpec(&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "test-namespace",
+				Name:      "test-service",
+			},
+			Spec: corev1.ServiceSpec{
+				Ports: test.ports,
+			},
+		})
+		assert.Equal(t, test.expectedError, err != nil)
+		if err == nil {
+			assert.Len(t, spec.Frontends, test.expectedFrontends)
+		}
+	}
+}
+
+func TestGetServiceEdgeLBPoolSpecAcceptProxy(t *testing.T) {
+	// cluster name really shouldn't be a global
+	cluster.Name = "test-cluster"
+	tests := []struct {
+		description   string
+		config        string
+		expectedError bool
+	}{
+		{
+			description: "should accept the proxy protocol from a cloud load balancer",
+			config: `
+acceptProxy: true
+cloudProviderConfiguration: '{"aws":{"elbs":[]}}'
+`,
+		},
+		{
+			description: "should reject accepting the proxy protocol without a cloud load balancer",
+			config: `
+acceptProxy: true
+`,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+
+		_, err := GetServiceEdgeLBPoolSpec(&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					constants.DklbConfigAnnotationKey: test.config,
+				},
+				Namespace: "test-namespace",
+				Name:      "test-service",
+			},
+			Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{
+					{Port: 5432},
+				},
+			},
+		})
+		assert.Equal(t, test.expectedError, err != nil)
+	}
+}
+
+func TestGetServiceEdgeLBPoolSpecRoutingMode(t *testing.T) {
+	// cluster name really shouldn't be a global
+	cluster.Name = "test-cluster"
+	tests := []struct {
+		description   string
+		config        string
+		expectedError bool
+	}{
+		{
+			description: "should route to pod ips from a dc/os virtual network",
+			config: `
+role: "*"
+routingMode: PodIP
+`,
+		},
+		{
+			description: "should reject routing to pod ips from the host network",
+			config: `
+routingMode: PodIP
+`,
+			expectedError: true,
+		},
+		{
+			description: "should reject an unknown routing mode",
+			config: `
+routingMode: ClusterIP
+`,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+
+		_, err := GetServiceEdgeLBPoolSpec(&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					constants.DklbConfigAnnotationKey: test.config,
+				},
+				Namespace: "test-namespace",
+				Name:      "test-service",
+			},
+			Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{
+					{Port: 80},
+				},
+			},
+		})
+		assert.Equal(t, test.expectedError, err != nil)
+	}
+}
+
+func TestGetServiceEdgeLBPoolSpecHealthCheckPath(t *testing.T) {
+	// cluster name really shouldn't be a global
+	cluster.Name = "test-cluster"
+	tests := []struct {
+		description           string
+		config                string
+		externalTrafficPolicy corev1.ServiceExternalTrafficPolicyType
+		expectedError         bool
+	}{
+		{
+			description: "should accept a health-check path for a service whose external traffic policy is \"Cluster\"",
+			config: `
+frontends:
+- servicePort: 80
+  backend:
+    healthCheck:
+      path: /ready
+`,
+			externalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeCluster,
+		},
+		{
+			description: "should accept a health-check interval for a service whose external traffic policy is \"Local\"",
+			config: `
+frontends:
+- servicePort: 80
+  backend:
+    healthCheck:
+      interval: 2s
+`,
+			externalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal,
+		},
+		{
+			description: "should accept a health-check path for a service whose external traffic policy is \"Local\" when routing to pod ips",
+			config: `
+role: "*"
+routingMode: PodIP
+frontends:
+- servicePort: 80
+  backend:
+    healthCheck:
+      path: /ready
+`,
+			externalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal,
+		},
+		{
+			description: "should reject a health-check path for a service whose external traffic policy is \"Local\"",
+			config: `
+frontends:
+- servicePort: 80
+  backend:
+    healthCheck:
+      path: /ready
+`,
+			externalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal,
+			expectedError:         true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+
+		_, err := GetServiceEdgeLBPoolSpec(&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					constants.DklbConfigAnnotationKey: test.config,
+				},
+				Namespace: "test-namespace",
+				Name:      "test-service",
+			},
+			Spec: corev1.ServiceSpec{
+				ExternalTrafficPolicy: test.externalTrafficPolicy,
+				Ports: []corev1.ServicePort{
+					{Port: 80},
+				},
+			},
+		})
+		assert.Equal(t, test.expectedError, err != nil)
+	}
+}
+
+func TestServiceEdgeLBPoolSpec_ValidateTransitionReplacement(t *testing.T) {
+	// cluster name really shouldn't be a global
+	cluster.Name = "test-cluster"
+	tests := []struct {
+		description    string
+		previousConfig string
+		currentConfig  string
+		expectedError  bool
+	}{
+		{
+			description: "should reject changing the name of the edgelb pool by default",
+			previousConfig: `
+name: "old-pool"
+`,
+			currentConfig: `
+name: "new-pool"
+`,
+			expectedError: true,
+		},
+		{
+			description: "should allow changing the name of the edgelb pool with the blue/green replacement strategy",
+			previousConfig: `
+name: "old-pool"
+`,
+			currentConfig: `
+name: "new-pool"
+strategies:
+  replacement: BlueGreen
+`,
+		},
+		{
+			description: "should allow changing the name and virtual network of the edgelb pool with the blue/green replacement strategy",
+			previousConfig: `
+name: "old-pool"
+role: "*"
+`,
+			currentConfig: `
+name: "new-pool"
+role: "*"
+network: "other"
+strategies:
+  replacement: BlueGreen
+`,
+		},
+		{
+			description: "should reject changing the virtual network of the edgelb pool without changing its name",
+			previousConfig: `
+name: "old-pool"
+role: "*"
+`,
+			currentConfig: `
+name: "old-pool"
+role: "*"
+network: "other"
+strategies:
+  replacement: BlueGreen
+`,
+			expectedError: true,
+		},
+		{
+			description: "should reject an unknown replacement strategy",
+			previousConfig: `
+name: "old-pool"
+`,
+			currentConfig: `
+name: "new-pool"
+strategies:
+  replacement: Rolling
+`,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+
+		newService := func(config string) *corev1.Service {
+			return &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						constants.DklbConfigAnnotationKey: config,
+					},
+					Namespace: "test-namespace",
+					Name:      "test-service",
+				},
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{
+						{Port: 80},
+					},
+				},
+			}
+		}
+		previousSpec, err := GetServiceEdgeLBPoolSpec(newService(test.previousConfig))
+		assert.NoError(t, err)
+		currentSpec, err := GetServiceEdgeLBPoolSpec(newService(test.currentConfig))
+		if err == nil {
+			err = currentSpec.ValidateTransition(previousSpec)
+		}
+		assert.Equal(t, test.expectedError, err != nil)
+	}
+}
+
+func TestGetServiceEdgeLBPoolSpecDeletionStrategy(t *testing.T) {
+	// cluster name really shouldn't be a global
+	cluster.Name = "test-cluster"
+	tests := []struct {
+		description      string
+		config           string
+		expectedStrategy EdgeLBPoolDeletionStrategy
+		expectedError    bool
+	}{
+		{
+			description:      "should default to deleting the edgelb pool when it becomes empty",
+			config:           ``,
+			expectedStrategy: EdgeLBPoolDeletionStrategyDeleteWhenEmpty,
+		},
+		{
+			description: "should keep the creation strategy when only the deletion strategy is specified",
+			config: `
+strategies:
+  deletion: Retain
+`,
+			expectedStrategy: EdgeLBPoolDeletionStrategyRetain,
+		},
+		{
+			description: "should accept the orphan deletion strategy",
+			config: `
+strategies:
+  creation: Never
+  deletion: Orphan
+`,
+			expectedStrategy: EdgeLBPoolDeletionStrategyOrphan,
+		},
+		{
+			description: "should reject an unknown deletion strategy",
+			config: `
+strategies:
+  deletion: Always
+`,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+
+		spec, err := GetServiceEdgeLBPoolSpec(&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					constants.DklbConfigAnnotationKey: test.config,
+				},
+				Namespace: "test-namespace",
+				Name:      "test-service",
+			},
+			Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{
+					{Port: 80},
+				},
+			},
+		})
+		assert.Equal(t, test.expectedError, err != nil)
+		if err == nil {
+			assert.Equal(t, test.expectedStrategy, *spec.Strategies.Deletion)
+			assert.NotNil(t, spec.Strategies.Creation)
+			assert.NotNil(t, spec.Strategies.Replacement)
+		}
+	}
+}
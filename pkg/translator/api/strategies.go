@@ -31,3 +31,58 @@ func (s *EdgeLBPoolCreationStrategy) UnmarshalYAML(fn func(interface{}) error) e
 	}
 	return nil
 }
+
+var (
+	// EdgeLBPoolReplacementStrategyBlueGreen denotes the strategy that replaces an EdgeLB pool whenever one of its immutable fields (e.g. its name) changes.
+	// The replacement EdgeLB pool is created alongside the replaced one, which is only emptied after the replacement EdgeLB pool has become ready.
+	EdgeLBPoolReplacementStrategyBlueGreen = EdgeLBPoolReplacementStrategy("BlueGreen")
+	// EdgeLBPoolReplacementStrategyNever denotes the strategy that never replaces an EdgeLB pool, rejecting any changes to its immutable fields.
+	EdgeLBPoolReplacementStrategyNever = EdgeLBPoolReplacementStrategy("Never")
+)
+
+// EdgeLBPoolReplacementStrategy represents a strategy used to replace EdgeLB pools.
+type EdgeLBPoolReplacementStrategy string
+
+// UnmarshalYAML unmarshals the underlying value as an "EdgeLBPoolReplacementStrategy" object.
+func (s *EdgeLBPoolReplacementStrategy) UnmarshalYAML(fn func(interface{}) error) error {
+	var buf string
+	if err := fn(&buf); err != nil {
+		return err
+	}
+	v := EdgeLBPoolReplacementStrategy(buf)
+	switch v {
+	case EdgeLBPoolReplacementStrategyBlueGreen, EdgeLBPoolReplacementStrategyNever:
+		*s = v
+	default:
+		return fmt.Errorf("failed to parse %q as an edgelb pool replacement strategy", buf)
+	}
+	return nil
+}
+
+var (
+	// EdgeLBPoolDeletionStrategyDeleteWhenEmpty denotes the strategy that deletes an EdgeLB pool as soon as it becomes empty (i.e. as soon as it has no frontends or backends).
+	EdgeLBPoolDeletionStrategyDeleteWhenEmpty = EdgeLBPoolDeletionStrategy("DeleteWhenEmpty")
+	// EdgeLBPoolDeletionStrategyOrphan denotes the strategy that leaves the frontends and backends of an Ingress/Service resource in an EdgeLB pool after said Ingress/Service resource is removed.
+	EdgeLBPoolDeletionStrategyOrphan = EdgeLBPoolDeletionStrategy("Orphan")
+	// EdgeLBPoolDeletionStrategyRetain denotes the strategy that never deletes an EdgeLB pool, leaving the frontends and backends of the last Ingress/Service resource removed from it in place instead of emptying it.
+	EdgeLBPoolDeletionStrategyRetain = EdgeLBPoolDeletionStrategy("Retain")
+)
+
+// EdgeLBPoolDeletionStrategy represents a strategy used to delete EdgeLB pools.
+type EdgeLBPoolDeletionStrategy string
+
+// UnmarshalYAML unmarshals the underlying value as an "EdgeLBPoolDeletionStrategy" object.
+func (s *EdgeLBPoolDeletionStrategy) UnmarshalYAML(fn func(interface{}) error) error {
+	var buf string
+	if err := fn(&buf); err != nil {
+		return err
+	}
+	v := EdgeLBPoolDeletionStrategy(buf)
+	switch v {
+	case EdgeLBPoolDeletionStrategyDeleteWhenEmpty, EdgeLBPoolDeletionStrategyOrphan, EdgeLBPoolDeletionStrategyRetain:
+		*s = v
+	default:
+		return fmt.Errorf("failed to parse %q as an edgelb pool deletion strategy", buf)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"fmt"
+)
+
+// EdgeLBTimeoutsSpec contains the specification of the timeouts applied by EdgeLB to client and server connections.
+// Every value is a Go duration (e.g. "30s").
+type EdgeLBTimeoutsSpec struct {
+	// Client is the maximum inactivity time on the client side.
+	Client *string `yaml:"client"`
+	// Connect is the maximum time to wait for a connection attempt to a server to succeed.
+	Connect *string `yaml:"connect"`
+	// HTTPRequest is the maximum time to wait for a complete HTTP request.
+	HTTPRequest *string `yaml:"httpRequest"`
+	// Server is the maximum inactivity time on the server side.
+	Server *string `yaml:"server"`
+	// Tunnel is the maximum inactivity time on the client and server sides for tunnels (e.g. WebSocket connections).
+	Tunnel *string `yaml:"tunnel"`
+}
+
+// MergedWith returns the result of overriding the current object's values with the ones specified in "override".
+// It returns nil in case neither object has been specified.
+func (o *EdgeLBTimeoutsSpec) MergedWith(override *EdgeLBTimeoutsSpec) *EdgeLBTimeoutsSpec {
+	if o == nil && override == nil {
+		return nil
+	}
+	res := &EdgeLBTimeoutsSpec{}
+	for _, spec := range []*EdgeLBTimeoutsSpec{o, override} {
+		if spec == nil {
+			continue
+		}
+		if spec.Client != nil {
+			res.Client = spec.Client
+		}
+		if spec.Connect != nil {
+			res.Connect = spec.Connect
+		}
+		if spec.HTTPRequest != nil {
+			res.HTTPRequest = spec.HTTPRequest
+		}
+		if spec.Server != nil {
+			res.Server = spec.Server
+		}
+		if spec.Tunnel != nil {
+			res.Tunnel = spec.Tunnel
+		}
+	}
+	return res
+}
+
+// validate checks whether the current object is valid.
+func (o *EdgeLBTimeoutsSpec) validate() error {
+	for _, timeout := range []struct {
+		field string
+		value *string
+	}{
+		{field: "client", value: o.Client},
+		{field: "connect", value: o.Connect},
+		{field: "httpRequest", value: o.HTTPRequest},
+		{field: "server", value: o.Server},
+		{field: "tunnel", value: o.Tunnel},
+	} {
+		if timeout.value == nil {
+			continue
+		}
+		if err := isValidPositiveDuration(*timeout.value); err != nil {
+			return fmt.Errorf(".%s %v", timeout.field, err)
+		}
+	}
+	return nil
+}
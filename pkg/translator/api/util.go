@@ -3,12 +3,13 @@ package api
 import (
 	"context"
 	"fmt"
+	"net"
 	pkgstrings "strings"
 	"time"
 
 	"gopkg.in/yaml.v2"
 	corev1 "k8s.io/api/core/v1"
-	extsv1beta1 "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 
 	"github.com/mesosphere/dklb/pkg/cluster"
 	"github.com/mesosphere/dklb/pkg/constants"
@@ -79,13 +80,13 @@ func newRandomEdgeLBPoolName(prefix string) string {
 // GetIngressEdgeLBPoolSpec attempts to parse the contents of the "kubernetes.dcos.io/dklb-config" annotation of the specified Ingress resource as the specification of the target EdgeLB pool.
 // Parsing is strict in the sense that any unrecognized fields will originate a parsing error.
 // If no value has been provided for the "kubernetes.dcos.io/dklb-config" annotation, a default EdgeLB pool specification object is returned.
-func GetIngressEdgeLBPoolSpec(ingress *extsv1beta1.Ingress) (*IngressEdgeLBPoolSpec, error) {
+func GetIngressEdgeLBPoolSpec(ingress *networkingv1.Ingress) (*IngressEdgeLBPoolSpec, error) {
 	v, exists := ingress.Annotations[constants.DklbConfigAnnotationKey]
-	if !exists || v == "" {
-		return NewDefaultIngressEdgeLBPoolSpecForIngress(ingress), nil
-	}
 	r := &IngressEdgeLBPoolSpec{}
-	if err := yaml.UnmarshalStrict([]byte(v), r); err != nil {
+	if !exists || v == "" {
+		// Validate the default configuration object as well, as the Ingress resource itself may still be invalid.
+		r = NewDefaultIngressEdgeLBPoolSpecForIngress(ingress)
+	} else if err := yaml.UnmarshalStrict([]byte(v), r); err != nil {
 		return nil, fmt.Errorf("failed to parse the value of %q as a configuration object: %v", constants.DklbConfigAnnotationKey, err)
 	}
 	if err := r.Validate(ingress); err != nil {
@@ -99,11 +100,11 @@ func GetIngressEdgeLBPoolSpec(ingress *extsv1beta1.Ingress) (*IngressEdgeLBPoolS
 // If no value has been provided for the "kubernetes.dcos.io/dklb-config" annotation, a default EdgeLB pool specification object is returned.
 func GetServiceEdgeLBPoolSpec(service *corev1.Service) (*ServiceEdgeLBPoolSpec, error) {
 	v, exists := service.Annotations[constants.DklbConfigAnnotationKey]
-	if !exists || v == "" {
-		return NewDefaultServiceEdgeLBPoolSpecForService(service), nil
-	}
 	r := &ServiceEdgeLBPoolSpec{}
-	if err := yaml.UnmarshalStrict([]byte(v), r); err != nil {
+	if !exists || v == "" {
+		// Validate the default configuration object as well, as the Service resource itself may still be invalid.
+		r = NewDefaultServiceEdgeLBPoolSpecForService(service)
+	} else if err := yaml.UnmarshalStrict([]byte(v), r); err != nil {
 		return nil, fmt.Errorf("failed to parse the value of %q as a configuration object: %v", constants.DklbConfigAnnotationKey, err)
 	}
 	if err := r.Validate(service); err != nil {
@@ -113,7 +114,7 @@ func GetServiceEdgeLBPoolSpec(service *corev1.Service) (*ServiceEdgeLBPoolSpec,
 }
 
 // SetIngressEdgeLBPoolSpec updates the provided Ingress resource with the provided EdgeLB pool specification.
-func SetIngressEdgeLBPoolSpec(ingress *extsv1beta1.Ingress, obj *IngressEdgeLBPoolSpec) error {
+func SetIngressEdgeLBPoolSpec(ingress *networkingv1.Ingress, obj *IngressEdgeLBPoolSpec) error {
 	b, err := yaml.Marshal(obj)
 	if err != nil {
 		return fmt.Errorf("failed to marshal configuration object: %v", err)
@@ -139,6 +140,16 @@ func SetServiceEdgeLBPoolSpec(service *corev1.Service, obj *ServiceEdgeLBPoolSpe
 }
 
 // IsIngressTLSEnabled checks if ingress has TLS spec defined.
-func IsIngressTLSEnabled(ingress *extsv1beta1.Ingress) bool {
+func IsIngressTLSEnabled(ingress *networkingv1.Ingress) bool {
 	return len(ingress.Spec.TLS) > 0
 }
+
+// validateSourceRanges returns an error if any of the specified source ranges is not a valid CIDR.
+func validateSourceRanges(sourceRanges []string) error {
+	for _, sourceRange := range sourceRanges {
+		if _, _, err := net.ParseCIDR(pkgstrings.TrimSpace(sourceRange)); err != nil {
+			return fmt.Errorf("%q is not a valid CIDR", sourceRange)
+		}
+	}
+	return nil
+}
@@ -93,8 +93,11 @@ frontends:
 					Memory:                     pointers.NewInt32(128),
 					Network:                    pointers.NewString(""),
 					Role:                       pointers.NewString("slave_public"),
+					RoutingMode:                pointers.NewString(EdgeLBPoolRoutingModeNodePort),
 					Strategies: &EdgeLBPoolManagementStrategies{
-						Creation: &EdgeLBPoolCreationStrategyIfNotPresent,
+						Creation:    &EdgeLBPoolCreationStrategyIfNotPresent,
+						Deletion:    &EdgeLBPoolDeletionStrategyDeleteWhenEmpty,
+						Replacement: &EdgeLBPoolReplacementStrategyNever,
 					},
 				},
 				Frontends: []ServiceEdgeLBPoolFrontendSpec{
@@ -0,0 +1,180 @@
+package translator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mesosphere/dcos-edge-lb/pkg/apis/models"
+	corev1 "k8s.io/api/core/v1"
+
+	translatorapi "github.com/mesosphere/dklb/pkg/translator/api"
+	"github.com/mesosphere/dklb/pkg/util/pointers"
+)
+
+const (
+	// edgeLBBackendHTTPCheckFormatString is the format string used to compute the HTTP request performed when health-checking the servers of an EdgeLB backend.
+	edgeLBBackendHTTPCheckFormatString = "GET %s"
+	// edgeLBBackendCheckTimeoutFormatString is the format string used to compute the HAProxy directive that sets the health-check timeout of an EdgeLB backend.
+	edgeLBBackendCheckTimeoutFormatString = "timeout check %s"
+	// edgeLBHealthCheckNodePortCheck is the HTTP request performed against the health-check node port of a Service resource in order to find out whether a given node hosts ready endpoints for said Service resource.
+	edgeLBHealthCheckNodePortCheck = "GET /healthz"
+	// edgeLBServerCheckPortFormatString is the format string used to compute the HAProxy server option that sets the port against which health-checks are performed.
+	edgeLBServerCheckPortFormatString = "port %d"
+	// edgeLBSendProxyV1 is the HAProxy server option that causes version 1 of the PROXY protocol to be used when connecting to a server.
+	edgeLBSendProxyV1 = "send-proxy"
+	// edgeLBSendProxyV2 is the HAProxy server option that causes version 2 of the PROXY protocol to be used when connecting to a server.
+	edgeLBSendProxyV2 = "send-proxy-v2"
+	// edgeLBTimeoutFormatString is the format string used to compute the HAProxy directive that sets a given timeout (e.g. "server") of an EdgeLB frontend or backend.
+	edgeLBTimeoutFormatString = "timeout %s %s"
+)
+
+// podEndpoint represents the IP and port of a (ready) pod backing a given service port.
+type podEndpoint struct {
+	// IP is the IP of the pod.
+	IP string
+	// Port is the port targeted by the service port on the pod.
+	Port int32
+}
+
+// computePodEndpointsForServicePort computes the (sorted) set of endpoints of the ready pods backing the specified service port.
+// A nil Endpoints resource results in an empty set of endpoints.
+func computePodEndpointsForServicePort(endpoints *corev1.Endpoints, servicePort corev1.ServicePort) []podEndpoint {
+	res := make([]podEndpoint, 0)
+	if endpoints == nil {
+		return res
+	}
+	for _, subset := range endpoints.Subsets {
+		for _, port := range subset.Ports {
+			// Endpoint ports are named after the service port they correspond to.
+			if port.Name != servicePort.Name {
+				continue
+			}
+			// Only ready addresses are considered, as pods that are not ready must not receive traffic.
+			for _, address := range subset.Addresses {
+				res = append(res, podEndpoint{IP: address.IP, Port: port.Port})
+			}
+		}
+	}
+	// Sort the set of endpoints so that the resulting EdgeLB backend can be compared with the one reported by the EdgeLB API server.
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].IP != res[j].IP {
+			return res[i].IP < res[j].IP
+		}
+		return res[i].Port < res[j].Port
+	})
+	return res
+}
+
+// computeEdgeLBServiceForPodEndpoint computes the EdgeLB service that targets the specified pod endpoint directly (i.e. without going through a node port).
+func computeEdgeLBServiceForPodEndpoint(endpoint podEndpoint, miscStr string) *models.V2Service {
+	return &models.V2Service{
+		Endpoint: &models.V2Endpoint{
+			Address: endpoint.IP,
+			Check: &models.V2EndpointCheck{
+				Enabled: pointers.NewBool(true),
+			},
+			MiscStr: miscStr,
+			Port:    endpoint.Port,
+			Type:    models.V2EndpointTypeADDRESS,
+		},
+		Marathon: &models.V2ServiceMarathon{
+			// We don't want to use any Marathon service as the backend.
+		},
+		Mesos: &models.V2ServiceMesos{
+			// We don't want to use any Mesos task as the backend.
+		},
+	}
+}
+
+// applyBaseEdgeLBPoolBackendSpec applies the load-balancing, PROXY protocol and health-check configuration contained in the specified spec to the specified EdgeLB backend.
+// A nil spec causes the default configuration to be applied.
+func applyBaseEdgeLBPoolBackendSpec(backend *models.V2Backend, spec *translatorapi.BaseEdgeLBPoolBackendSpec) {
+	backend.Balance = spec.BalanceOrDefault()
+	// Pass client connection information to each server of the EdgeLB backend using the PROXY protocol, if requested.
+	if spec != nil && spec.SendProxy != nil {
+		sendProxy := edgeLBSendProxyV1
+		if *spec.SendProxy == translatorapi.ProxyProtocolV2 {
+			sendProxy = edgeLBSendProxyV2
+		}
+		for _, service := range backend.Services {
+			service.Endpoint.MiscStr = strings.TrimSpace(service.Endpoint.MiscStr + " " + sendProxy)
+		}
+	}
+	if spec == nil || spec.HealthCheck == nil {
+		return
+	}
+	hc := spec.HealthCheck
+	// Perform HTTP health-checks instead of TCP ones in case a path has been specified.
+	if hc.Path != nil {
+		backend.CustomCheck = &models.V2BackendCustomCheck{
+			Httpchk:        true,
+			HttpchkMiscStr: fmt.Sprintf(edgeLBBackendHTTPCheckFormatString, *hc.Path),
+		}
+	}
+	// Compute the health-check parameters to set on each server of the EdgeLB backend.
+	params := make([]string, 0, 3)
+	if hc.Interval != nil {
+		params = append(params, "inter", toHAProxyDuration(*hc.Interval))
+	}
+	if hc.Rise != nil {
+		params = append(params, "rise", fmt.Sprintf("%d", *hc.Rise))
+	}
+	if hc.Fall != nil {
+		params = append(params, "fall", fmt.Sprintf("%d", *hc.Fall))
+	}
+	for _, service := range backend.Services {
+		service.Endpoint.Check.CustomStr = strings.Join(params, " ")
+	}
+	if hc.Timeout != nil {
+		backend.MiscStrs = append(backend.MiscStrs, fmt.Sprintf(edgeLBBackendCheckTimeoutFormatString, toHAProxyDuration(*hc.Timeout)))
+	}
+}
+
+// applyEdgeLBBackendHealthCheckNodePorts configures the specified EdgeLB backend to health-check each of its servers using the corresponding health-check node port (i.e. the one at the same index).
+// The EdgeLB backend is left untouched unless every server has a health-check node port, as HTTP health-checks would otherwise be performed against the node ports of the remaining servers.
+// The health-check interval, rise, fall and timeout configured for the EdgeLB backend (if any) are preserved.
+func applyEdgeLBBackendHealthCheckNodePorts(backend *models.V2Backend, healthCheckNodePorts []int32) {
+	if len(backend.Services) == 0 || len(healthCheckNodePorts) != len(backend.Services) {
+		return
+	}
+	for _, healthCheckNodePort := range healthCheckNodePorts {
+		if healthCheckNodePort == 0 {
+			return
+		}
+	}
+	backend.CustomCheck = &models.V2BackendCustomCheck{
+		Httpchk:        true,
+		HttpchkMiscStr: edgeLBHealthCheckNodePortCheck,
+	}
+	for idx, s := range backend.Services {
+		s.Endpoint.Check.CustomStr = strings.TrimSpace(s.Endpoint.Check.CustomStr + " " + fmt.Sprintf(edgeLBServerCheckPortFormatString, healthCheckNodePorts[idx]))
+	}
+}
+
+// computeEdgeLBBackendTimeoutMiscStrs computes the HAProxy directives that set the server-side timeouts contained in the specified spec on an EdgeLB backend.
+// Client-side timeouts are ignored, as they must be set on EdgeLB frontends instead.
+// It returns nil in case no server-side timeouts have been specified.
+func computeEdgeLBBackendTimeoutMiscStrs(spec *translatorapi.EdgeLBTimeoutsSpec) []string {
+	if spec == nil {
+		return nil
+	}
+	var res []string
+	if spec.Connect != nil {
+		res = append(res, fmt.Sprintf(edgeLBTimeoutFormatString, "connect", toHAProxyDuration(*spec.Connect)))
+	}
+	if spec.Server != nil {
+		res = append(res, fmt.Sprintf(edgeLBTimeoutFormatString, "server", toHAProxyDuration(*spec.Server)))
+	}
+	if spec.Tunnel != nil {
+		res = append(res, fmt.Sprintf(edgeLBTimeoutFormatString, "tunnel", toHAProxyDuration(*spec.Tunnel)))
+	}
+	return res
+}
+
+// toHAProxyDuration converts the specified (previously validated) Go duration into a duration expressed in milliseconds, as understood by HAProxy.
+func toHAProxyDuration(value string) string {
+	d, _ := time.ParseDuration(value)
+	return fmt.Sprintf("%dms", d/time.Millisecond)
+}
@@ -0,0 +1,194 @@
+package translator
+
+import (
+	"testing"
+
+	"github.com/mesosphere/dcos-edge-lb/pkg/apis/models"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	translatorapi "github.com/mesosphere/dklb/pkg/translator/api"
+	"github.com/mesosphere/dklb/pkg/util/pointers"
+)
+
+func TestApplyBaseEdgeLBPoolBackendSpec(t *testing.T) {
+	newBackend := func() *models.V2Backend {
+		return &models.V2Backend{
+			Services: []*models.V2Service{
+				{
+					Endpoint: &models.V2Endpoint{
+						Check: &models.V2EndpointCheck{
+							Enabled: pointers.NewBool(true),
+						},
+					},
+				},
+			},
+		}
+	}
+	tests := []struct {
+		description string
+		spec        *translatorapi.BaseEdgeLBPoolBackendSpec
+		expected    func(backend *models.V2Backend)
+	}{
+		{
+			description: "should apply the default configuration",
+			spec:        nil,
+			expected: func(backend *models.V2Backend) {
+				backend.Balance = "leastconn"
+			},
+		},
+		{
+			description: "should apply a custom balance algorithm",
+			spec: &translatorapi.BaseEdgeLBPoolBackendSpec{
+				Balance: pointers.NewString("roundrobin"),
+			},
+			expected: func(backend *models.V2Backend) {
+				backend.Balance = "roundrobin"
+			},
+		},
+		{
+			description: "should apply a custom health-check",
+			spec: &translatorapi.BaseEdgeLBPoolBackendSpec{
+				HealthCheck: &translatorapi.EdgeLBBackendHealthCheckSpec{
+					Path:     pointers.NewString("/healthz"),
+					Interval: pointers.NewString("5s"),
+					Rise:     pointers.NewInt32(2),
+					Fall:     pointers.NewInt32(3),
+					Timeout:  pointers.NewString("1.5s"),
+				},
+			},
+			expected: func(backend *models.V2Backend) {
+				backend.Balance = "leastconn"
+				backend.CustomCheck = &models.V2BackendCustomCheck{
+					Httpchk:        true,
+					HttpchkMiscStr: "GET /healthz",
+				}
+				backend.Services[0].Endpoint.Check.CustomStr = "inter 5000ms rise 2 fall 3"
+				backend.MiscStrs = []string{"timeout check 1500ms"}
+			},
+		},
+		{
+			description: "should pass client connection information using v1 of the proxy protocol",
+			spec: &translatorapi.BaseEdgeLBPoolBackendSpec{
+				SendProxy: pointers.NewString("v1"),
+			},
+			expected: func(backend *models.V2Backend) {
+				backend.Balance = "leastconn"
+				backend.Services[0].Endpoint.MiscStr = "send-proxy"
+			},
+		},
+		{
+			description: "should pass client connection information using v2 of the proxy protocol",
+			spec: &translatorapi.BaseEdgeLBPoolBackendSpec{
+				SendProxy: pointers.NewString("v2"),
+			},
+			expected: func(backend *models.V2Backend) {
+				backend.Balance = "leastconn"
+				backend.Services[0].Endpoint.MiscStr = "send-proxy-v2"
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+		actual, expected := newBackend(), newBackend()
+		test.expected(expected)
+		applyBaseEdgeLBPoolBackendSpec(actual, test.spec)
+		assert.Equal(t, expected, actual)
+	}
+}
+
+func TestComputeEdgeLBBackendTimeoutMiscStrs(t *testing.T) {
+	tests := []struct {
+		description string
+		spec        *translatorapi.EdgeLBTimeoutsSpec
+		expected    []string
+	}{
+		{
+			description: "should not set any timeout when none is specified",
+			spec:        nil,
+			expected:    nil,
+		},
+		{
+			description: "should only set server-side timeouts",
+			spec: &translatorapi.EdgeLBTimeoutsSpec{
+				Client:  pointers.NewString("1m"),
+				Connect: pointers.NewString("2.5s"),
+				Server:  pointers.NewString("1m"),
+				Tunnel:  pointers.NewString("1h"),
+			},
+			expected: []string{
+				"timeout connect 2500ms",
+				"timeout server 60000ms",
+				"timeout tunnel 3600000ms",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+		assert.Equal(t, test.expected, computeEdgeLBBackendTimeoutMiscStrs(test.spec))
+	}
+}
+
+func TestComputePodEndpointsForServicePort(t *testing.T) {
+	endpoints := &corev1.Endpoints{
+		Subsets: []corev1.EndpointSubset{
+			{
+				Addresses: []corev1.EndpointAddress{
+					{IP: "9.0.0.2"},
+					{IP: "9.0.0.1"},
+				},
+				NotReadyAddresses: []corev1.EndpointAddress{
+					{IP: "9.0.0.3"},
+				},
+				Ports: []corev1.EndpointPort{
+					{Name: "http", Port: 8080},
+					{Name: "metrics", Port: 9090},
+				},
+			},
+			{
+				Addresses: []corev1.EndpointAddress{
+					{IP: "9.0.0.0"},
+				},
+				Ports: []corev1.EndpointPort{
+					{Name: "http", Port: 8081},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		description string
+		endpoints   *corev1.Endpoints
+		servicePort corev1.ServicePort
+		expected    []podEndpoint
+	}{
+		{
+			description: "should return an empty set of endpoints when there is no endpoints resource",
+			endpoints:   nil,
+			servicePort: corev1.ServicePort{Name: "http", Port: 80},
+			expected:    []podEndpoint{},
+		},
+		{
+			description: "should return the sorted set of ready endpoints for the service port",
+			endpoints:   endpoints,
+			servicePort: corev1.ServicePort{Name: "http", Port: 80},
+			expected: []podEndpoint{
+				{IP: "9.0.0.0", Port: 8081},
+				{IP: "9.0.0.1", Port: 8080},
+				{IP: "9.0.0.2", Port: 8080},
+			},
+		},
+		{
+			description: "should return an empty set of endpoints for an unknown service port",
+			endpoints:   endpoints,
+			servicePort: corev1.ServicePort{Name: "grpc", Port: 81},
+			expected:    []podEndpoint{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+		assert.Equal(t, test.expected, computePodEndpointsForServicePort(test.endpoints, test.servicePort))
+	}
+}
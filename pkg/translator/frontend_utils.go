@@ -0,0 +1,109 @@
+package translator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mesosphere/dcos-edge-lb/pkg/apis/models"
+
+	translatorapi "github.com/mesosphere/dklb/pkg/translator/api"
+)
+
+const (
+	// edgeLBFrontendAllowedSourcesACLFormatString is the format string used to compute the HAProxy directive that declares the ACL matching the source ranges allowed to access an EdgeLB frontend.
+	edgeLBFrontendAllowedSourcesACLFormatString = "acl %s src %s"
+	// edgeLBFrontendRejectSourcesFormatString is the format string used to compute the HAProxy directive that rejects connections not matching a given ACL.
+	edgeLBFrontendRejectSourcesFormatString = "tcp-request connection reject if !%s"
+	// edgeLBBackendDenySourcesFormatString is the format string used to compute the HAProxy directive that denies HTTP requests not matching a given ACL.
+	edgeLBBackendDenySourcesFormatString = "http-request deny if !%s"
+	// edgeLBFrontendTimeoutClientPrefix is the prefix of the HAProxy directive that sets the client-side inactivity timeout of an EdgeLB frontend.
+	edgeLBFrontendTimeoutClientPrefix = "timeout client "
+	// edgeLBFrontendTimeoutHTTPRequestPrefix is the prefix of the HAProxy directive that sets the maximum time to wait for a complete HTTP request on an EdgeLB frontend.
+	edgeLBFrontendTimeoutHTTPRequestPrefix = "timeout http-request "
+)
+
+// computeEdgeLBFrontendSourceRangesMiscStrs computes the HAProxy directives that restrict access to an EdgeLB frontend to clients connecting from the specified source ranges.
+// The specified ACL name must be unique within the EdgeLB frontend.
+// It returns nil in case no source ranges are specified, in which case clients are allowed to connect from any address.
+func computeEdgeLBFrontendSourceRangesMiscStrs(aclName string, sourceRanges []string) []string {
+	if len(sourceRanges) == 0 {
+		return nil
+	}
+	return []string{
+		computeEdgeLBSourceRangesACL(aclName, sourceRanges),
+		fmt.Sprintf(edgeLBFrontendRejectSourcesFormatString, aclName),
+	}
+}
+
+// computeEdgeLBBackendSourceRangesMiscStrs computes the HAProxy directives that restrict access to an EdgeLB backend to clients connecting from the specified source ranges.
+// As opposed to the directives computed by computeEdgeLBFrontendSourceRangesMiscStrs, these only apply to requests routed to the EdgeLB backend, and hence can be used when the EdgeLB frontend is shared.
+// It returns nil in case no source ranges are specified, in which case clients are allowed to connect from any address.
+func computeEdgeLBBackendSourceRangesMiscStrs(aclName string, sourceRanges []string) []string {
+	if len(sourceRanges) == 0 {
+		return nil
+	}
+	return []string{
+		computeEdgeLBSourceRangesACL(aclName, sourceRanges),
+		fmt.Sprintf(edgeLBBackendDenySourcesFormatString, aclName),
+	}
+}
+
+// computeEdgeLBSourceRangesACL computes the HAProxy directive that declares an ACL with the specified name matching the specified source ranges.
+func computeEdgeLBSourceRangesACL(aclName string, sourceRanges []string) string {
+	return fmt.Sprintf(edgeLBFrontendAllowedSourcesACLFormatString, aclName, computeEdgeLBSourceRanges(sourceRanges))
+}
+
+// computeEdgeLBSourceRanges computes the space-separated list of CIDRs used in HAProxy directives and conditions matching the specified source ranges.
+func computeEdgeLBSourceRanges(sourceRanges []string) string {
+	cidrs := make([]string, 0, len(sourceRanges))
+	for _, sourceRange := range sourceRanges {
+		cidrs = append(cidrs, strings.TrimSpace(sourceRange))
+	}
+	return strings.Join(cidrs, " ")
+}
+
+// computeEdgeLBFrontendTimeoutMiscStrs computes the HAProxy directives that set the client-side timeouts contained in the specified spec on an EdgeLB frontend.
+// Server-side timeouts are ignored, as they must be set on EdgeLB backends instead.
+// It returns nil in case no client-side timeouts have been specified.
+func computeEdgeLBFrontendTimeoutMiscStrs(spec *translatorapi.EdgeLBTimeoutsSpec) []string {
+	if spec == nil {
+		return nil
+	}
+	var res []string
+	if spec.Client != nil {
+		res = append(res, edgeLBFrontendTimeoutClientPrefix+toHAProxyDuration(*spec.Client))
+	}
+	if spec.HTTPRequest != nil {
+		res = append(res, edgeLBFrontendTimeoutHTTPRequestPrefix+toHAProxyDuration(*spec.HTTPRequest))
+	}
+	return res
+}
+
+// isEdgeLBFrontendTimeoutMiscStr indicates whether the specified value is an HAProxy directive that sets a client-side timeout of an EdgeLB frontend.
+func isEdgeLBFrontendTimeoutMiscStr(miscStr string) bool {
+	return strings.HasPrefix(miscStr, edgeLBFrontendTimeoutClientPrefix) || strings.HasPrefix(miscStr, edgeLBFrontendTimeoutHTTPRequestPrefix)
+}
+
+// replaceEdgeLBFrontendMiscStrs replaces the directives of the specified EdgeLB frontend that are matched by the specified function with the specified ones.
+// Replacement directives are inserted at the position of the first replaced directive (or appended in case there is none), so that directives managed by different owners keep their relative order across updates.
+func replaceEdgeLBFrontendMiscStrs(frontend *models.V2Frontend, isReplaced func(string) bool, miscStrs []string) {
+	res := make([]string, 0, len(frontend.MiscStrs)+len(miscStrs))
+	idx := -1
+	for _, m := range frontend.MiscStrs {
+		if isReplaced(m) {
+			if idx < 0 {
+				idx = len(res)
+			}
+			continue
+		}
+		res = append(res, m)
+	}
+	if idx < 0 {
+		idx = len(res)
+	}
+	res = append(res[:idx], append(append([]string{}, miscStrs...), res[idx:]...)...)
+	if len(res) == 0 {
+		res = nil
+	}
+	frontend.MiscStrs = res
+}
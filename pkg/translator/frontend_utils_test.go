@@ -0,0 +1,138 @@
+package translator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mesosphere/dcos-edge-lb/pkg/apis/models"
+	"github.com/stretchr/testify/assert"
+
+	translatorapi "github.com/mesosphere/dklb/pkg/translator/api"
+	"github.com/mesosphere/dklb/pkg/util/pointers"
+)
+
+func TestComputeEdgeLBFrontendSourceRangesMiscStrs(t *testing.T) {
+	tests := []struct {
+		description  string
+		sourceRanges []string
+		expected     []string
+	}{
+		{
+			description:  "should not restrict access when no source ranges are specified",
+			sourceRanges: nil,
+			expected:     nil,
+		},
+		{
+			description:  "should reject connections from outside the specified source ranges",
+			sourceRanges: []string{"10.0.0.0/8", " 192.168.1.0/24 "},
+			expected: []string{
+				"acl test_acl src 10.0.0.0/8 192.168.1.0/24",
+				"tcp-request connection reject if !test_acl",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+		assert.Equal(t, test.expected, computeEdgeLBFrontendSourceRangesMiscStrs("test_acl", test.sourceRanges))
+	}
+}
+
+func TestComputeEdgeLBBackendSourceRangesMiscStrs(t *testing.T) {
+	tests := []struct {
+		description  string
+		sourceRanges []string
+		expected     []string
+	}{
+		{
+			description:  "should not restrict access when no source ranges are specified",
+			sourceRanges: nil,
+			expected:     nil,
+		},
+		{
+			description:  "should deny requests from outside the specified source ranges",
+			sourceRanges: []string{"10.0.0.0/8", " 192.168.1.0/24 "},
+			expected: []string{
+				"acl test_acl src 10.0.0.0/8 192.168.1.0/24",
+				"http-request deny if !test_acl",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+		assert.Equal(t, test.expected, computeEdgeLBBackendSourceRangesMiscStrs("test_acl", test.sourceRanges))
+	}
+}
+
+func TestComputeEdgeLBFrontendTimeoutMiscStrs(t *testing.T) {
+	tests := []struct {
+		description string
+		spec        *translatorapi.EdgeLBTimeoutsSpec
+		expected    []string
+	}{
+		{
+			description: "should not set any timeout when none is specified",
+			spec:        nil,
+			expected:    nil,
+		},
+		{
+			description: "should only set client-side timeouts",
+			spec: &translatorapi.EdgeLBTimeoutsSpec{
+				Client:      pointers.NewString("1m"),
+				HTTPRequest: pointers.NewString("10s"),
+				Server:      pointers.NewString("1m"),
+			},
+			expected: []string{
+				"timeout client 60000ms",
+				"timeout http-request 10000ms",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+		actual := computeEdgeLBFrontendTimeoutMiscStrs(test.spec)
+		assert.Equal(t, test.expected, actual)
+		for _, miscStr := range actual {
+			assert.True(t, isEdgeLBFrontendTimeoutMiscStr(miscStr))
+		}
+	}
+}
+
+func TestReplaceEdgeLBFrontendMiscStrs(t *testing.T) {
+	tests := []struct {
+		description string
+		miscStrs    []string
+		replacement []string
+		expected    []string
+	}{
+		{
+			description: "should append the replacement directives when no directive is replaced",
+			miscStrs:    []string{"other 1"},
+			replacement: []string{"owned 3"},
+			expected:    []string{"other 1", "owned 3"},
+		},
+		{
+			description: "should insert the replacement directives at the position of the first replaced directive",
+			miscStrs:    []string{"other 1", "owned 1", "other 2", "owned 2"},
+			replacement: []string{"owned 3", "owned 4"},
+			expected:    []string{"other 1", "owned 3", "owned 4", "other 2"},
+		},
+		{
+			description: "should remove the replaced directives when no replacement is specified",
+			miscStrs:    []string{"owned 1"},
+			replacement: nil,
+			expected:    nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+		frontend := &models.V2Frontend{MiscStrs: test.miscStrs}
+		replaceEdgeLBFrontendMiscStrs(frontend, func(m string) bool {
+			return strings.HasPrefix(m, "owned ")
+		}, test.replacement)
+		assert.Equal(t, test.expected, frontend.MiscStrs)
+	}
+}
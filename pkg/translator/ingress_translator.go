@@ -11,8 +11,9 @@ import (
 	"github.com/mesosphere/dcos-edge-lb/pkg/apis/models"
 	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
-	extsv1beta1 "k8s.io/api/extensions/v1beta1"
-	"k8s.io/apimachinery/pkg/util/intstr"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 
 	dklbcache "github.com/mesosphere/dklb/pkg/cache"
@@ -37,15 +38,15 @@ var (
 	// defaultBackendServiceName is the value used internally as ".serviceName" to signal the fact that dklb should be used as the default backend.
 	// It will also end up being used as part of the name of an EdgeLB backend whenever an Ingress resource doesn't define a default backend or a referenced Service resource is missing or otherwise invalid.
 	defaultBackendServiceName = "default-backend"
-	// defaultBackendServicePort is the value used internally as ".servicePort" to signal the fact that dklb should be used as the default backend.
+	// defaultBackendServicePort is the value used internally as ".port" to signal the fact that dklb should be used as the default backend.
 	// It will also end up being used as part of the name of an EdgeLB backend whenever an Ingress resource doesn't define a default backend or a referenced Service resource is missing or otherwise invalid.
-	defaultBackendServicePort = intstr.FromInt(0)
+	defaultBackendServicePort = networkingv1.ServiceBackendPort{Number: 0}
 )
 
 // IngressTranslator is the base implementation of IngressTranslator.
 type IngressTranslator struct {
 	// ingress is the Ingress resource to be translated.
-	ingress *extsv1beta1.Ingress
+	ingress *networkingv1.Ingress
 	// spec is the EdgeLB pool configuration object to use when performing translation.
 	spec *translatorapi.IngressEdgeLBPoolSpec
 	// kubeCache is the instance of the Kubernetes resource cache to use.
@@ -58,10 +59,21 @@ type IngressTranslator struct {
 	recorder record.EventRecorder
 	// poolGroup is the DC/OS service group in which to create EdgeLB pools.
 	poolGroup string
+	// podEndpointsMap is the mapping between Ingress backends and the endpoints of the pods backing them.
+	// It is only populated when EdgeLB routes traffic directly to pod IPs.
+	podEndpointsMap IngressBackendPodEndpointsMap
+	// healthCheckNodePortMap is the mapping between Ingress backends and the health-check node port of the Service resources they target.
+	// It is only populated when EdgeLB routes traffic to node ports, and only for Service resources whose external traffic policy is "Local".
+	healthCheckNodePortMap IngressBackendHealthCheckNodePortMap
+	// redeployedSettings holds the names of the settings of the target EdgeLB pool whose change causes EdgeLB to redeploy its load balancer instances.
+	// It is populated when updating the target EdgeLB pool object.
+	redeployedSettings []string
+	// replacedEdgeLBPool holds whether the Ingress resource has been removed from the EdgeLB pool replaced by the target EdgeLB pool (if any).
+	replacedEdgeLBPool bool
 }
 
 // NewIngressTranslator returns an ingress translator that can be used to translate the specified Ingress resource into an EdgeLB pool.
-func NewIngressTranslator(ingress *extsv1beta1.Ingress, kubeCache dklbcache.KubernetesResourceCache, manager manager.EdgeLBManager, recorder record.EventRecorder) *IngressTranslator {
+func NewIngressTranslator(ingress *networkingv1.Ingress, kubeCache dklbcache.KubernetesResourceCache, manager manager.EdgeLBManager, recorder record.EventRecorder) *IngressTranslator {
 	return &IngressTranslator{
 		// Use a clone of the Ingress resource as we may need to modify it in order to inject the default backend.
 		ingress:   ingress.DeepCopy(),
@@ -102,12 +114,87 @@ func (it *IngressTranslator) Translate() (*corev1.LoadBalancerStatus, error) {
 			return nil, fmt.Errorf("failed to check for the existence of the %q edgelb pool: %v", *it.spec.Name, err)
 		}
 	}
-	// If the target EdgeLB pool does not exist, we must try to create it,
+	var status *corev1.LoadBalancerStatus
 	if pool == nil {
-		return it.createEdgeLBPool(backendMap)
+		// If the target EdgeLB pool does not exist, we must try to create it,
+		status, err = it.createEdgeLBPool(backendMap)
+	} else {
+		// If the target EdgeLB pool already exists, we must check whether it needs to be updated/deleted.
+		status, err = it.updateOrDeleteEdgeLBPool(pool, backendMap)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Proceed with the replacement of the EdgeLB pool previously targeted by the Ingress resource (if any).
+	return it.replaceEdgeLBPool(status, backendMap)
+}
+
+// ReplacedEdgeLBPool returns a value indicating whether the Ingress resource has been removed from the EdgeLB pool being replaced by the target EdgeLB pool.
+// In case it returns true, the "kubernetes.dcos.io/dklb-replaced-pool" annotation can be removed from the Ingress resource.
+func (it *IngressTranslator) ReplacedEdgeLBPool() bool {
+	return it.replacedEdgeLBPool
+}
+
+// ingressDeleted returns a value indicating whether the Ingress resource has been deleted or is not meant to be provisioned by EdgeLB anymore.
+func (it *IngressTranslator) ingressDeleted() bool {
+	if it.ingress.DeletionTimestamp != nil {
+		return true
 	}
-	// If the target EdgeLB pool already exists, we must check whether it needs to be updated/deleted.
-	return it.updateOrDeleteEdgeLBPool(pool, backendMap)
+	ingressClasses, err := it.kubeCache.GetIngressClasses()
+	if err != nil {
+		// Err on the side of caution and keep the Ingress resource in the target EdgeLB pool, as we cannot tell whether it is still meant to be provisioned by EdgeLB.
+		it.logger.Warnf("failed to list ingress classes: %v", err)
+		return false
+	}
+	return !kubernetesutil.IsEdgeLBIngress(it.ingress, ingressClasses)
+}
+
+// replacedEdgeLBPoolName returns the name of the EdgeLB pool being replaced by the target EdgeLB pool, or an empty string if no EdgeLB pool is being replaced.
+func (it *IngressTranslator) replacedEdgeLBPoolName() string {
+	if name := it.ingress.Annotations[constants.DklbReplacedPoolAnnotationKey]; name != *it.spec.Name {
+		return name
+	}
+	return ""
+}
+
+// replaceEdgeLBPool removes the Ingress resource from the EdgeLB pool being replaced by the target EdgeLB pool (if any) as soon as the target EdgeLB pool has become ready.
+// The specified status is the status of the target EdgeLB pool, and is returned unchanged unless the target EdgeLB pool is not ready yet.
+// In that case, the status of the EdgeLB pool being replaced is returned instead, so that clients keep connecting to it.
+func (it *IngressTranslator) replaceEdgeLBPool(status *corev1.LoadBalancerStatus, backendMap IngressBackendNodePortMap) (*corev1.LoadBalancerStatus, error) {
+	replacedPoolName := it.replacedEdgeLBPoolName()
+	if replacedPoolName == "" {
+		return status, nil
+	}
+	// Wait for the target EdgeLB pool to report the addresses at which the Ingress resource can be reached before removing the Ingress resource from the EdgeLB pool being replaced.
+	// There is no point in waiting in case the Ingress resource has been deleted.
+	if !it.ingressDeleted() && (status == nil || len(status.Ingress) == 0) {
+		it.logger.Infof("waiting for edgelb pool %q to become ready before removing %q from edgelb pool %q", *it.spec.Name, kubernetesutil.Key(it.ingress), replacedPoolName)
+		return computeLoadBalancerStatus(it.manager, replacedPoolName, it.ingress, nil), nil
+	}
+	ctx, fn := context.WithTimeout(context.Background(), defaultEdgeLBManagerTimeout)
+	defer fn()
+	pool, err := it.manager.GetPool(ctx, replacedPoolName)
+	if err != nil && !dklberrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to check for the existence of the %q edgelb pool: %v", replacedPoolName, err)
+	}
+	// Remove the Ingress resource from the EdgeLB pool being replaced (if it still exists) by pretending it has been deleted.
+	if pool != nil {
+		deletionTimestamp := metav1.Now()
+		replaced := *it
+		replaced.ingress = it.ingress.DeepCopy()
+		replaced.ingress.DeletionTimestamp = &deletionTimestamp
+		replacedSpec := *it.spec
+		replacedSpec.Name = &replacedPoolName
+		replacedSpec.Strategies = replacedEdgeLBPoolStrategies(*it.spec.Strategies)
+		replaced.spec = &replacedSpec
+		replaced.redeployedSettings = nil
+		if _, err := replaced.updateOrDeleteEdgeLBPool(pool, backendMap); err != nil {
+			return nil, fmt.Errorf("failed to remove %q from edgelb pool %q: %v", kubernetesutil.Key(it.ingress), replacedPoolName, err)
+		}
+	}
+	it.logger.Infof("removed %q from edgelb pool %q", kubernetesutil.Key(it.ingress), replacedPoolName)
+	it.replacedEdgeLBPool = true
+	return status, nil
 }
 
 // determineDefaultBackendNodePort attempts to determine the node port at which the default backend is exposed.
@@ -128,82 +215,159 @@ func (it *IngressTranslator) determineDefaultBackendNodePort() (int32, error) {
 }
 
 // computeIngressBackendNodePortMap computes the mapping between (unique) Ingress backends defined on the current Ingress resource and their target node ports.
-// It starts by compiling a set of all (possibly duplicate) Ingress backends defined on the Ingress resource.
+// It starts by compiling a set of all (possibly duplicate) Ingress backends defined on the Ingress resource, as well as of the canaries they reference.
 // In case a default backend hasn't been specified, dklb's default backend is injected as the default one.
 // Then, it iterates over said set and checks whether the referenced service port exists, adding them to the map or using the default backend's node port instead.
+// In case EdgeLB routes traffic directly to pod IPs, the endpoints of the pods backing each (valid) Ingress backend are additionally stored in the translator's pod endpoints map.
+// Otherwise, the health-check node port of each (valid) Ingress backend targeting a Service resource whose external traffic policy is "Local" is stored in the translator's health-check node port map.
 // As the returned object is in fact a map, duplicate Ingress backends are automatically removed.
 func (it *IngressTranslator) computeIngressBackendNodePortMap(defaultBackendNodePort int32) IngressBackendNodePortMap {
 	// Inject dklb as the default backend in case none is specified.
-	if it.ingress.Spec.Backend == nil {
-		it.ingress.Spec.Backend = &extsv1beta1.IngressBackend{
-			ServiceName: defaultBackendServiceName,
-			ServicePort: defaultBackendServicePort,
+	if it.ingress.Spec.DefaultBackend == nil {
+		it.ingress.Spec.DefaultBackend = &networkingv1.IngressBackend{
+			Service: &networkingv1.IngressServiceBackend{
+				Name: defaultBackendServiceName,
+				Port: defaultBackendServicePort,
+			},
 		}
 		it.recorder.Eventf(it.ingress, corev1.EventTypeWarning, constants.ReasonNoDefaultBackendSpecified, "%s will be used as the default backend since none was specified", constants.ComponentName)
 	}
 	// backends is the slice containing all Ingress backends present in the current Ingress resource.
-	backends := make([]extsv1beta1.IngressBackend, 0)
+	backends := make([]networkingv1.IngressServiceBackend, 0)
 	// Iterate over all Ingress backends, adding them to the slice of results.
-	kubernetesutil.ForEachIngresBackend(it.ingress, func(_, _ *string, backend extsv1beta1.IngressBackend) {
+	kubernetesutil.ForEachIngresBackend(it.ingress, func(_ *string, _ *networkingv1.HTTPIngressPath, backend networkingv1.IngressServiceBackend) {
 		backends = append(backends, backend)
 	})
+	// Add the canaries referenced by said Ingress backends, as their node ports are required in order to route traffic to them.
+	for canary := range computeIngressCanaryBackends(it.ingress, *it.spec) {
+		backends = append(backends, canary)
+	}
 	// Create the map that we will be populating and returning.
 	res := make(IngressBackendNodePortMap, len(backends))
+	// Create the map of pod endpoints in case EdgeLB routes traffic directly to pod IPs.
+	if it.spec.RoutesToPodIPs() {
+		it.podEndpointsMap = make(IngressBackendPodEndpointsMap, len(backends))
+	} else {
+		it.healthCheckNodePortMap = make(IngressBackendHealthCheckNodePortMap)
+	}
 	// Iterate over the set of Ingress backends, computing the target node port.
 	for _, backend := range backends {
 		// If the target service's name corresponds to "defaultBackendServiceName", we use the default backend's node port.
-		if backend.ServiceName == defaultBackendServiceName && backend.ServicePort == defaultBackendServicePort {
+		if backend.Name == defaultBackendServiceName && backend.Port == defaultBackendServicePort {
 			res[backend] = defaultBackendNodePort
 			continue
 		}
-		if nodePort, err := it.computeNodePortForIngressBackend(backend); err == nil {
-			res[backend] = nodePort
+		servicePort, err := it.computeServicePortForIngressBackend(backend)
+		var podEndpoints []podEndpoint
+		if err == nil && it.spec.RoutesToPodIPs() {
+			podEndpoints, err = it.computePodEndpointsForIngressBackend(backend, *servicePort)
+		}
+		if err == nil {
+			res[backend] = servicePort.NodePort
+			if it.spec.RoutesToPodIPs() {
+				it.podEndpointsMap[backend] = podEndpoints
+			} else if healthCheckNodePort := it.computeHealthCheckNodePortForIngressBackend(backend); healthCheckNodePort != 0 {
+				it.healthCheckNodePortMap[backend] = healthCheckNodePort
+			}
 		} else {
-			// We've failed to compute the target node port for the current backend.
+			// We've failed to compute the target node port (or pod endpoints) for the current backend.
 			// This may be caused by the specified Service resource being absent or not being of NodePort/LoadBalancer type.
 			// Hence, we use the default backend's node port and report the error as an event, but do not fail.
-			msg := fmt.Sprintf("using the default backend in place of \"%s:%s\": %v", backend.ServiceName, backend.ServicePort.String(), err)
+			msg := fmt.Sprintf("using the default backend in place of \"%s:%s\": %v", backend.Name, kubernetesutil.IngressServiceBackendPort(backend), err)
 			it.recorder.Eventf(it.ingress, corev1.EventTypeWarning, constants.ReasonInvalidBackendService, msg)
 			it.logger.Warn(msg)
 			res[backend] = defaultBackendNodePort
 		}
 	}
+	// Warn about health-check paths that are overridden because the target Service resources have an external traffic policy of "Local".
+	it.warnOverriddenHealthCheckPaths(res)
 	// Return the populated map.
 	return res
 }
 
-// computeNodePortForIngressBackend computes the node port targeted by the specified Ingress backend.
-func (it *IngressTranslator) computeNodePortForIngressBackend(backend extsv1beta1.IngressBackend) (int32, error) {
+// computeHealthCheckNodePortForIngressBackend computes the health-check node port of the Service resource referenced by the specified Ingress backend.
+// It returns zero in case the external traffic policy of the Service resource is not "Local" (or in case the Service resource cannot be read).
+func (it *IngressTranslator) computeHealthCheckNodePortForIngressBackend(backend networkingv1.IngressServiceBackend) int32 {
+	s, err := it.kubeCache.GetService(it.ingress.Namespace, backend.Name)
+	if err != nil {
+		return 0
+	}
+	return computeHealthCheckNodePortForService(s)
+}
+
+// warnOverriddenHealthCheckPaths emits a warning event for every Ingress backend in the specified map whose health-check path is overridden by the health-check node port of the target Service resources.
+func (it *IngressTranslator) warnOverriddenHealthCheckPaths(backendMap IngressBackendNodePortMap) {
+	for backend := range backendMap {
+		backendSpec := it.spec.BackendSpecFor(backend)
+		if backendSpec.HealthCheck == nil || backendSpec.HealthCheck.Path == nil {
+			continue
+		}
+		if computeHealthCheckNodePortsForIngressBackend(*it.spec, backend, it.healthCheckNodePortMap) == nil {
+			continue
+		}
+		msg := fmt.Sprintf("the health-check path for \"%s:%s\" is ignored, as the target services have an external traffic policy of %q", backend.Name, kubernetesutil.IngressServiceBackendPort(backend), corev1.ServiceExternalTrafficPolicyTypeLocal)
+		it.recorder.Eventf(it.ingress, corev1.EventTypeWarning, constants.ReasonHealthCheckPathIgnored, msg)
+		it.logger.Warn(msg)
+	}
+}
+
+// computeServicePortForIngressBackend computes the service port targeted by the specified Ingress backend.
+// Unless EdgeLB routes traffic directly to pod IPs, the referenced Service resource must be of type "NodePort" or "LoadBalancer" so that the service port has a node port.
+func (it *IngressTranslator) computeServicePortForIngressBackend(backend networkingv1.IngressServiceBackend) (*corev1.ServicePort, error) {
 	// Check whether the referenced Service resource exists.
-	s, err := it.kubeCache.GetService(it.ingress.Namespace, backend.ServiceName)
+	s, err := it.kubeCache.GetService(it.ingress.Namespace, backend.Name)
 	if err != nil {
-		return 0, fmt.Errorf("failed to read service %q referenced by ingress %q: %v", backend.ServiceName, kubernetesutil.Key(it.ingress), err)
+		return nil, fmt.Errorf("failed to read service %q referenced by ingress %q: %v", backend.Name, kubernetesutil.Key(it.ingress), err)
 	}
 	// Check whether the referenced Service resource is of type "NodePort" or "LoadBalancer".
-	if s.Spec.Type != corev1.ServiceTypeNodePort && s.Spec.Type != corev1.ServiceTypeLoadBalancer {
-		return 0, fmt.Errorf("service %q referenced by ingress %q is of unexpected type %q", backend.ServiceName, kubernetesutil.Key(it.ingress), s.Spec.Type)
+	if !it.spec.RoutesToPodIPs() && s.Spec.Type != corev1.ServiceTypeNodePort && s.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		return nil, fmt.Errorf("service %q referenced by ingress %q is of unexpected type %q", backend.Name, kubernetesutil.Key(it.ingress), s.Spec.Type)
 	}
-	// Lookup the referenced service port.
-	var servicePort *corev1.ServicePort
-	log.Printf("searching for backend.servicePort={%+v}", backend.ServicePort)
+	// Lookup the referenced service port, ignoring service ports that cannot be exposed by EdgeLB (i.e. non-TCP ones).
+	var servicePort, unsupportedServicePort *corev1.ServicePort
+	log.Printf("searching for backend.port={%+v}", backend.Port)
 	for _, port := range s.Spec.Ports {
 		// Pin "port" so we can take its address.
 		port := port
-		if port.Port == backend.ServicePort.IntVal || port.Name == backend.ServicePort.StrVal {
+		if port.Port == backend.Port.Number || (backend.Port.Name != "" && port.Name == backend.Port.Name) {
+			if !kubernetesutil.IsSupportedServicePort(port) {
+				unsupportedServicePort = &port
+				continue
+			}
 			servicePort = &port
 		}
 	}
 	// Check whether the referenced service port has been found.
+	if servicePort == nil && unsupportedServicePort != nil {
+		return nil, fmt.Errorf("port %q of service %q referenced by ingress %q uses the %s protocol, which is not supported by edgelb", kubernetesutil.IngressServiceBackendPort(backend), backend.Name, kubernetesutil.Key(it.ingress), unsupportedServicePort.Protocol)
+	}
 	if servicePort == nil {
-		return 0, fmt.Errorf("port %q of service %q referenced by ingress %q not found", backend.ServicePort.String(), backend.ServiceName, kubernetesutil.Key(it.ingress))
+		return nil, fmt.Errorf("port %q of service %q referenced by ingress %q not found", kubernetesutil.IngressServiceBackendPort(backend), backend.Name, kubernetesutil.Key(it.ingress))
+	}
+	return servicePort, nil
+}
+
+// computePodEndpointsForIngressBackend computes the endpoints of the (ready) pods backing the specified service port of the Service resource referenced by the specified Ingress backend.
+// A missing Endpoints resource (e.g. because the Service resource has just been created) is interpreted as the absence of ready pods.
+func (it *IngressTranslator) computePodEndpointsForIngressBackend(backend networkingv1.IngressServiceBackend, servicePort corev1.ServicePort) ([]podEndpoint, error) {
+	endpoints, err := it.kubeCache.GetEndpoints(it.ingress.Namespace, backend.Name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to read the endpoints for service %q referenced by ingress %q: %v", backend.Name, kubernetesutil.Key(it.ingress), err)
 	}
-	return servicePort.NodePort, nil
+	return computePodEndpointsForServicePort(endpoints, servicePort), nil
 }
 
 // createEdgeLBPool makes a decision on whether an EdgeLB pool should be created for the associated Ingress resource.
 // This decision is based on the EdgeLB pool creation strategy specified for the Ingress resource.
 // In case it should be created, it proceeds to actually creating it.
 func (it *IngressTranslator) createEdgeLBPool(backendMap IngressBackendNodePortMap) (*corev1.LoadBalancerStatus, error) {
+	// If the Ingress resource has been deleted (or is not meant to be provisioned by EdgeLB anymore), there is nothing to clean up.
+	// Hence, and as the target EdgeLB pool must not be re-created, we should just exit.
+	if it.ingressDeleted() {
+		it.logger.Debugf("edgelb pool %q does not exist, so there is nothing to clean up", *it.spec.Name)
+		return &corev1.LoadBalancerStatus{}, nil
+	}
+
 	// If the pool creation strategy is "Never", the target EdgeLB pool must be provisioned manually.
 	// Hence, we should just exit.
 	if *it.spec.Strategies.Creation == translatorapi.EdgeLBPoolCreationStrategyNever {
@@ -212,7 +376,8 @@ func (it *IngressTranslator) createEdgeLBPool(backendMap IngressBackendNodePortM
 
 	// If the Ingress resource's ".status" field contains at least one IP/host, that means an EdgeLB pool has once existed, but has been deleted manually.
 	// Hence, and if the EdgeLB pool creation strategy is "Once", we should also just exit.
-	if len(it.ingress.Status.LoadBalancer.Ingress) > 0 && *it.spec.Strategies.Creation == translatorapi.EdgeLBPoolCreationStrategyOnce {
+	// This doesn't apply while the EdgeLB pool targeted by the Ingress resource is being replaced, as the Ingress resource's ".status" field then refers to the EdgeLB pool being replaced.
+	if len(it.ingress.Status.LoadBalancer.Ingress) > 0 && *it.spec.Strategies.Creation == translatorapi.EdgeLBPoolCreationStrategyOnce && it.replacedEdgeLBPoolName() == "" {
 		return nil, fmt.Errorf("edgelb pool %q targeted by ingress %q has probably been manually deleted, and the pool creation strategy is %q", *it.spec.Name, kubernetesutil.Key(it.ingress), *it.spec.Strategies.Creation)
 	}
 
@@ -231,11 +396,29 @@ func (it *IngressTranslator) createEdgeLBPool(backendMap IngressBackendNodePortM
 
 // updateOrDeleteEdgeLBPool makes a decision on whether the specified EdgeLB pool should be updated/deleted based on the current status of the associated Ingress resource.
 // In case it should be updated/deleted, it proceeds to actually updating/deleting it.
-// TODO (@bcustodio) Decide whether we should also update the EdgeLB pool's role and its CPU/memory/size requests.
 func (it *IngressTranslator) updateOrDeleteEdgeLBPool(pool *models.V2Pool, backendMap IngressBackendNodePortMap) (*corev1.LoadBalancerStatus, error) {
+	// If the Ingress resource has been deleted and the EdgeLB pool deletion strategy is "Orphan", we leave its EdgeLB backends and EdgeLB frontends untouched.
+	ingressDeleted := it.ingressDeleted()
+	if ingressDeleted && *it.spec.Strategies.Deletion == translatorapi.EdgeLBPoolDeletionStrategyOrphan {
+		it.logger.Debugf("leaving the edgelb backends and edgelb frontends of %q in edgelb pool %q as the pool deletion strategy is %q", kubernetesutil.Key(it.ingress), pool.Name, *it.spec.Strategies.Deletion)
+		return &corev1.LoadBalancerStatus{}, nil
+	}
+
 	// Check whether the EdgeLB pool object must be updated.
 	opResult, desiredFrontends := it.updateEdgeLBPoolObject(pool, backendMap)
 
+	// Refuse to serve the Ingress resource through an EdgeLB frontend that cannot enforce its client certificate configuration, and let the user know about other frontend-level settings that cannot be applied as the EdgeLB frontend is shared with (and owned by) another Ingress resource.
+	if !ingressDeleted {
+		for _, frontend := range desiredFrontends {
+			if err := checkEdgeLBFrontendClientAuthForIngress(it.ingress, *it.spec, frontend); err != nil {
+				return nil, err
+			}
+			if settings := computeIgnoredEdgeLBFrontendSettingsForIngress(it.ingress, *it.spec, frontend); len(settings) > 0 {
+				it.recorder.Eventf(it.ingress, corev1.EventTypeWarning, constants.ReasonFrontendSettingsIgnored, "%s ignored as edgelb frontend %q is owned by another ingress", strings.Join(settings, ", "), frontend.Name)
+			}
+		}
+	}
+
 	b, _ := json.Marshal(pool)
 	log.WithField("pool", string(b)).Infof("computed updated edgelb pool")
 
@@ -249,8 +432,14 @@ func (it *IngressTranslator) updateOrDeleteEdgeLBPool(pool *models.V2Pool, backe
 	ctx, fn := context.WithTimeout(context.Background(), defaultEdgeLBManagerTimeout)
 	defer fn()
 
-	// If the EdgeLB pool is empty (i.e. it has no EdgeLB frontends or EdgeLB backends) we proceed to deleting it and reporting an empty status.
+	// If the EdgeLB pool is empty (i.e. it has no EdgeLB frontends or EdgeLB backends) we proceed to deleting (or retaining) it and reporting an empty status.
 	if len(pool.Haproxy.Frontends) == 0 && len(pool.Haproxy.Backends) == 0 {
+		// If the EdgeLB pool deletion strategy is "Retain", the EdgeLB pool must be kept.
+		// As EdgeLB pools are not meant to be left without any EdgeLB frontends or EdgeLB backends, we keep the EdgeLB pool alive by leaving it untouched (i.e. with the EdgeLB frontends and EdgeLB backends of the Ingress resource).
+		if *it.spec.Strategies.Deletion == translatorapi.EdgeLBPoolDeletionStrategyRetain {
+			it.logger.Infof("leaving edgelb pool %q untouched as it would become empty and the pool deletion strategy is %q", pool.Name, *it.spec.Strategies.Deletion)
+			return &corev1.LoadBalancerStatus{}, nil
+		}
 		// The EdgeLB pool is empty, so we delete it.
 		it.logger.Debugf("edgelb pool %q is empty and must be deleted", pool.Name)
 		if err := it.manager.DeletePool(ctx, pool.Name); err != nil {
@@ -264,23 +453,24 @@ func (it *IngressTranslator) updateOrDeleteEdgeLBPool(pool *models.V2Pool, backe
 	if _, err := it.manager.UpdatePool(ctx, pool); err != nil {
 		return nil, err
 	}
+	// Let the user know in case the update causes EdgeLB to redeploy the pool's load balancer instances, as this may cause a short disruption.
+	if len(it.redeployedSettings) > 0 {
+		it.recorder.Eventf(it.ingress, corev1.EventTypeNormal, constants.ReasonEdgeLBPoolRedeploying, "edgelb pool %q will be redeployed as its %s changed", pool.Name, strings.Join(it.redeployedSettings, ", "))
+	}
 	return computeLoadBalancerStatus(it.manager, pool.Name, it.ingress, desiredFrontends), nil
 }
 
 // createEdgeLBPoolObject creates an EdgeLB pool object that satisfies the current Ingress resource.
 func (it *IngressTranslator) createEdgeLBPoolObject(backendMap IngressBackendNodePortMap) *models.V2Pool {
 	// Iterate over Ingress backends and their target node ports, and create the corresponding EdgeLB backend objects.
-	backends := make([]*models.V2Backend, 0, len(backendMap))
-	for backend, nodePort := range backendMap {
-		backends = append(backends, computeEdgeLBBackendForIngressBackend(it.ingress, backend, nodePort))
-	}
+	backends := computeEdgeLBBackendForIngress(it.ingress, *it.spec, backendMap, it.podEndpointsMap, it.healthCheckNodePortMap)
 	// Sort backends alphabetically in order to get a predictable output, as ranging over a map can produce different results every time.
 	sort.SliceStable(backends, func(i, j int) bool {
 		return backends[i].Name < backends[j].Name
 	})
 	// Create the EdgeLB frontend object.
 	frontends := computeEdgeLBFrontendForIngress(it.ingress, *it.spec, nil)
-	secrets := computeEdgeLBSecretsForIngress(it.ingress)
+	secrets := computeEdgeLBSecretsForIngress(it.ingress, *it.spec)
 	// Create the base EdgeLB pool object.
 	p := &models.V2Pool{
 		Name:      *it.spec.Name,
@@ -334,16 +524,28 @@ func checkIfReferencesBackend(backends []*models.V2Backend, name string) bool {
 	return false
 }
 
-func computeEdgeLBBackendForIngress(ingress *extsv1beta1.Ingress, backendMap IngressBackendNodePortMap) []*models.V2Backend {
+// computeEdgeLBBackendForIngress computes the EdgeLB backends that correspond to the Ingress backends in the specified map.
+// Canaries only get an EdgeLB backend of their own in case requests can be forced to them, or in case they are also referenced directly by the Ingress resource.
+func computeEdgeLBBackendForIngress(ingress *networkingv1.Ingress, spec translatorapi.IngressEdgeLBPoolSpec, backendMap IngressBackendNodePortMap, podEndpointsMap IngressBackendPodEndpointsMap, healthCheckNodePortMap IngressBackendHealthCheckNodePortMap) []*models.V2Backend {
+	referencedBackends := make(map[networkingv1.IngressServiceBackend]bool, len(backendMap))
+	kubernetesutil.ForEachIngresBackend(ingress, func(_ *string, _ *networkingv1.HTTPIngressPath, backend networkingv1.IngressServiceBackend) {
+		referencedBackends[backend] = true
+	})
+	canaryBackends := computeIngressCanaryBackends(ingress, spec)
 	desiredBackends := make([]*models.V2Backend, 0)
-	for ingressBackend, nodePort := range backendMap {
-		desiredBackend := computeEdgeLBBackendForIngressBackend(ingress, ingressBackend, nodePort)
+	for ingressBackend := range backendMap {
+		if forceable, isCanary := canaryBackends[ingressBackend]; isCanary && !forceable && !referencedBackends[ingressBackend] {
+			continue
+		}
+		desiredBackend := computeEdgeLBBackendForIngressBackend(ingress, spec, ingressBackend, backendMap, podEndpointsMap, healthCheckNodePortMap)
 		desiredBackends = append(desiredBackends, desiredBackend)
 	}
+	// Apply the rate limiting configuration for the Ingress resource, which spans all of its EdgeLB backends.
+	applyEdgeLBRateLimit(desiredBackends, spec.RateLimit)
 	return desiredBackends
 }
 
-func computeUnmanagedBackendsForIngress(ingress *extsv1beta1.Ingress, pool *models.V2Pool) []*models.V2Backend {
+func computeUnmanagedBackendsForIngress(ingress *networkingv1.Ingress, pool *models.V2Pool) []*models.V2Backend {
 	unmanagedBackends := make([]*models.V2Backend, 0)
 	for _, backend := range pool.Haproxy.Backends {
 		backendMetadata := computeIngressOwnedEdgeLBObjectMetadata(backend.Name)
@@ -354,7 +556,7 @@ func computeUnmanagedBackendsForIngress(ingress *extsv1beta1.Ingress, pool *mode
 	return unmanagedBackends
 }
 
-func computeUnmanagedFrontendsForIngress(ingress *extsv1beta1.Ingress, pool *models.V2Pool, desiredFrontends []*models.V2Frontend) []*models.V2Frontend {
+func computeUnmanagedFrontendsForIngress(ingress *networkingv1.Ingress, pool *models.V2Pool, desiredFrontends []*models.V2Frontend) []*models.V2Frontend {
 	unmanagedFrontends := make([]*models.V2Frontend, 0)
 	for _, frontend := range pool.Haproxy.Frontends {
 		desiredFrontend := checkIfDesired(desiredFrontends, frontend)
@@ -367,7 +569,7 @@ func computeUnmanagedFrontendsForIngress(ingress *extsv1beta1.Ingress, pool *mod
 	return unmanagedFrontends
 }
 
-func computeUnmanagedSecrets(ingress *extsv1beta1.Ingress, pool *models.V2Pool) []*models.V2PoolSecretsItems0 {
+func computeUnmanagedSecrets(ingress *networkingv1.Ingress, pool *models.V2Pool) []*models.V2PoolSecretsItems0 {
 	unmanagedSecrets := make([]*models.V2PoolSecretsItems0, 0)
 
 	// Iterate of the pool's secrets and check whether each one is owned by the
@@ -391,6 +593,12 @@ func filterUnreferencedBackends(backends []*models.V2Backend, frontends []*model
 		for _, b := range f.LinkBackend.Map {
 			references[b.Backend] = ""
 		}
+		// Backends may also be referenced by "use_backend" directives (e.g. in order to force requests to a canary).
+		for _, m := range f.MiscStrs {
+			if target := computeEdgeLBUseBackendMiscStrTarget(m); target != "" {
+				references[target] = ""
+			}
+		}
 	}
 
 	for _, b := range backends {
@@ -423,6 +631,11 @@ func filterReferencedBackends(backends []*models.V2Backend, frontends []*models.
 				linkBackendMap = append(linkBackendMap, b)
 			}
 		}
+		// Remove any "use_backend" directives referencing the removed backends, as HAProxy refuses to start otherwise.
+		replaceEdgeLBFrontendMiscStrs(f, func(m string) bool {
+			_, ok := references[computeEdgeLBUseBackendMiscStrTarget(m)]
+			return ok
+		}, nil)
 		if len(linkBackendMap) == 0 && f.LinkBackend.DefaultBackend == "" {
 			log.Debugf("frontend %s is empty, removing", f.Name)
 		} else {
@@ -442,14 +655,14 @@ func filterReferencedBackends(backends []*models.V2Backend, frontends []*models.
 // * If the object is owned by the current Ingress resource and the corresponding Ingress backend still exists (or the object is an EdgeLB frontend), it is checked for correctness and updated if necessary.
 // Furthermore, Ingress backends are iterated over in order to understand which EdgeLB backends must be added to the EdgeLB pool.
 func (it *IngressTranslator) updateEdgeLBPoolObject(pool *models.V2Pool, backendMap IngressBackendNodePortMap) (operationResult OperationResult, desiredFrontends []*models.V2Frontend) {
-	// ingressDeleted holds whether the Ingress resource has been deleted or its "kubernetes.io/ingress.class" has changed.
-	ingressDeleted := it.ingress.DeletionTimestamp != nil || it.ingress.Annotations[constants.EdgeLBIngressClassAnnotationKey] != constants.EdgeLBIngressClassAnnotationValue
+	// ingressDeleted holds whether the Ingress resource has been deleted or its ingress class has changed.
+	ingressDeleted := it.ingressDeleted()
 	log.Debugf("ingress is being deleted? %v", ingressDeleted)
 	operationResult = OperationResultNone
 
-	desiredBackends := computeEdgeLBBackendForIngress(it.ingress, backendMap)
+	desiredBackends := computeEdgeLBBackendForIngress(it.ingress, *it.spec, backendMap, it.podEndpointsMap, it.healthCheckNodePortMap)
 	desiredFrontends = computeEdgeLBFrontendForIngress(it.ingress, *it.spec, pool)
-	desiredSecrets := computeEdgeLBSecretsForIngress(it.ingress)
+	desiredSecrets := computeEdgeLBSecretsForIngress(it.ingress, *it.spec)
 
 	backends := computeUnmanagedBackendsForIngress(it.ingress, pool)
 	frontends := computeUnmanagedFrontendsForIngress(it.ingress, pool, desiredFrontends)
@@ -506,26 +719,15 @@ func (it *IngressTranslator) updateEdgeLBPoolObject(pool *models.V2Pool, backend
 		return OperationResultDeleted, nil
 	}
 
-	// Update the CPU request as necessary.
-	desiredCpus := *it.spec.CPUs
-	if pool.Cpus != desiredCpus {
-		pool.Cpus = desiredCpus
-		operationResult = OperationResultUpdated
-		log.Debugf("must update the cpu request")
-	}
-	// Update the memory request as necessary.
-	desiredMem := *it.spec.Memory
-	if pool.Mem != desiredMem {
-		pool.Mem = desiredMem
-		operationResult = OperationResultUpdated
-		log.Debugf("must update the memory request")
-	}
-	// Update the size request as necessary.
-	desiredSize := *it.spec.Size
-	if *pool.Count != desiredSize {
-		pool.Count = &desiredSize
-		operationResult = OperationResultUpdated
-		log.Debugf("must update the size request")
+	// Update the CPU, memory and size requests, the placement constraints and the role as necessary.
+	// These are left untouched in case the ingress has been deleted, as the pool may be shared with other ingresses.
+	if !ingressDeleted {
+		changes := reconcileEdgeLBPoolSettings(pool, it.spec.BaseEdgeLBPoolSpec)
+		for _, change := range changes {
+			operationResult = OperationResultUpdated
+			log.Debugf("must update the %s", change.Setting)
+		}
+		it.redeployedSettings = redeployedEdgeLBPoolSettings(changes)
 	}
 
 	// Return a value indicating whether the pool was changed and the desired frontend configuration
@@ -10,10 +10,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	corev1 "k8s.io/api/core/v1"
-	extsv1beta1 "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/tools/record"
 
 	edgelbmodels "github.com/mesosphere/dcos-edge-lb/pkg/apis/models"
@@ -64,7 +63,7 @@ func TestTranslate(t *testing.T) {
 		eventRecorder    record.EventRecorder
 		expectedError    error
 		expectedLBStatus *corev1.LoadBalancerStatus
-		ingress          *extsv1beta1.Ingress
+		ingress          *networkingv1.Ingress
 		kubeCache        dklbcache.KubernetesResourceCache
 	}{
 		{
@@ -84,13 +83,44 @@ func TestTranslate(t *testing.T) {
 			eventRecorder:    record.NewFakeRecorder(10),
 			expectedError:    nil,
 			expectedLBStatus: &corev1.LoadBalancerStatus{},
-			ingress: &extsv1beta1.Ingress{
+			ingress: &networkingv1.Ingress{
 				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						constants.EdgeLBIngressClassAnnotationKey: constants.EdgeLBIngressClassAnnotationValue,
+					},
 					Namespace: "test-namespace",
 					Name:      "test-ingress",
 				},
-				Spec: extsv1beta1.IngressSpec{
-					TLS: []extsv1beta1.IngressTLS{
+				Spec: networkingv1.IngressSpec{
+					TLS: []networkingv1.IngressTLS{
+						{SecretName: "test-secret"},
+					},
+				},
+			},
+			kubeCache: dklbcache.NewInformerBackedResourceCache(cachetestutil.NewFakeSharedInformerFactory(defaultService)),
+		},
+		{
+			description: "should not re-create the edgelb pool for a deleted ingress",
+			edgelbManager: func() edgelbmanager.EdgeLBManager {
+				edgelbManager := new(mockedgelb.MockEdgeLBManager)
+				edgelbManager.On("PoolGroup").Return("test-pool-group", nil)
+				edgelbManager.On("GetPool", mock.Anything, mock.Anything).Return(nil, nil)
+				return edgelbManager
+			},
+			eventRecorder:    record.NewFakeRecorder(10),
+			expectedError:    nil,
+			expectedLBStatus: &corev1.LoadBalancerStatus{},
+			ingress: &networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						constants.EdgeLBIngressClassAnnotationKey: constants.EdgeLBIngressClassAnnotationValue,
+					},
+					DeletionTimestamp: &metav1.Time{},
+					Namespace:         "test-namespace",
+					Name:              "test-ingress",
+				},
+				Spec: networkingv1.IngressSpec{
+					TLS: []networkingv1.IngressTLS{
 						{SecretName: "test-secret"},
 					},
 				},
@@ -102,7 +132,7 @@ func TestTranslate(t *testing.T) {
 			edgelbManager: func() edgelbmanager.EdgeLBManager {
 				pool := &models.V2Pool{
 					Namespace: pointers.NewString(""),
-					Role:      "slave-public",
+					Role:      constants.EdgeLBRolePublic,
 					Cpus:      0.1,
 					Mem:       int32(128),
 					VirtualNetworks: []*models.V2PoolVirtualNetworksItems0{
@@ -155,7 +185,7 @@ func TestTranslate(t *testing.T) {
 
 					expectedPool := &models.V2Pool{
 						Namespace: pointers.NewString(""),
-						Role:      "slave-public",
+						Role:      constants.EdgeLBRolePublic,
 						Cpus:      0.1,
 						Mem:       int32(128),
 						VirtualNetworks: []*models.V2PoolVirtualNetworksItems0{
@@ -190,7 +220,7 @@ func TestTranslate(t *testing.T) {
 													Enabled: pointers.NewBool(true),
 												},
 												MiscStr: "",
-												Port:    32889,
+												Port:    31889,
 												Type:    models.V2EndpointTypeCONTAINERIP,
 											},
 											Marathon: &models.V2ServiceMarathon{},
@@ -250,7 +280,7 @@ func TestTranslate(t *testing.T) {
 			eventRecorder:    record.NewFakeRecorder(10),
 			expectedError:    nil,
 			expectedLBStatus: &corev1.LoadBalancerStatus{},
-			ingress: &extsv1beta1.Ingress{
+			ingress: &networkingv1.Ingress{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: "test-namespace",
 					Name:      "test-ingress-1",
@@ -264,21 +294,25 @@ frontends:
 `,
 					},
 				},
-				Spec: extsv1beta1.IngressSpec{
-					// Backend: &extsv1beta1.IngressBackend{
-					// 	ServiceName: testService.Name,
-					// 	ServicePort: intstr.FromString("80"),
+				Spec: networkingv1.IngressSpec{
+					// DefaultBackend: &networkingv1.IngressBackend{
+					// 	Service: &networkingv1.IngressServiceBackend{
+					// 		Name: testService.Name,
+					// 		Port: networkingv1.ServiceBackendPort{Number: 80},
+					// 	},
 					// },
-					Rules: []extsv1beta1.IngressRule{
+					Rules: []networkingv1.IngressRule{
 						{
 							Host: "test-host.com",
-							IngressRuleValue: extsv1beta1.IngressRuleValue{
-								HTTP: &extsv1beta1.HTTPIngressRuleValue{
-									Paths: []extsv1beta1.HTTPIngressPath{
+							IngressRuleValue: networkingv1.IngressRuleValue{
+								HTTP: &networkingv1.HTTPIngressRuleValue{
+									Paths: []networkingv1.HTTPIngressPath{
 										{
-											Backend: extsv1beta1.IngressBackend{
-												ServiceName: testService.Name,
-												ServicePort: intstr.FromInt(80),
+											Backend: networkingv1.IngressBackend{
+												Service: &networkingv1.IngressServiceBackend{
+													Name: testService.Name,
+													Port: networkingv1.ServiceBackendPort{Number: 80},
+												},
 											},
 										},
 									},
@@ -286,7 +320,7 @@ frontends:
 							},
 						},
 					},
-					TLS: []extsv1beta1.IngressTLS{
+					TLS: []networkingv1.IngressTLS{
 						{SecretName: "test-secret-1"},
 					},
 				},
@@ -343,7 +377,7 @@ func TestTranslate_createEdgeLBPoolObject(t *testing.T) {
 				},
 			},
 			it: &IngressTranslator{
-				ingress: &extsv1beta1.Ingress{
+				ingress: &networkingv1.Ingress{
 					ObjectMeta: metav1.ObjectMeta{
 						Namespace: "test-namespace",
 						Name:      "test-ingress",
@@ -417,7 +451,7 @@ func TestTranslate_createEdgeLBPoolObject(t *testing.T) {
 				},
 			},
 			it: &IngressTranslator{
-				ingress: &extsv1beta1.Ingress{
+				ingress: &networkingv1.Ingress{
 					ObjectMeta: metav1.ObjectMeta{
 						Namespace: "test-namespace",
 						Name:      "test-ingress",
@@ -426,8 +460,8 @@ func TestTranslate_createEdgeLBPoolObject(t *testing.T) {
 						},
 						UID: types.UID("uid"),
 					},
-					Spec: extsv1beta1.IngressSpec{
-						TLS: []extsv1beta1.IngressTLS{
+					Spec: networkingv1.IngressSpec{
+						TLS: []networkingv1.IngressTLS{
 							{SecretName: "test-secret"},
 						},
 					},
@@ -490,7 +524,7 @@ func TestTranslate_createEdgeLBPoolObject(t *testing.T) {
 				},
 			},
 			it: &IngressTranslator{
-				ingress: &extsv1beta1.Ingress{
+				ingress: &networkingv1.Ingress{
 					ObjectMeta: metav1.ObjectMeta{
 						Namespace: "test-namespace",
 						Name:      "test-ingress",
@@ -499,8 +533,8 @@ func TestTranslate_createEdgeLBPoolObject(t *testing.T) {
 						},
 						UID: types.UID("uid"),
 					},
-					Spec: extsv1beta1.IngressSpec{
-						TLS: []extsv1beta1.IngressTLS{
+					Spec: networkingv1.IngressSpec{
+						TLS: []networkingv1.IngressTLS{
 							{SecretName: "test-secret"},
 						},
 					},
@@ -543,7 +577,7 @@ func TestTranslate_updateEdgeLBPoolObject(t *testing.T) {
 	// customize the object
 	newIngressTranslator := func(mutator func(*IngressTranslator)) *IngressTranslator {
 		it := &IngressTranslator{
-			ingress: &extsv1beta1.Ingress{
+			ingress: &networkingv1.Ingress{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: "test-namespace",
 					Name:      "test-ingress",
@@ -552,8 +586,8 @@ func TestTranslate_updateEdgeLBPoolObject(t *testing.T) {
 					},
 					UID: types.UID("uid"),
 				},
-				Spec: extsv1beta1.IngressSpec{
-					TLS: []extsv1beta1.IngressTLS{
+				Spec: networkingv1.IngressSpec{
+					TLS: []networkingv1.IngressTLS{
 						{SecretName: "test-secret"},
 					},
 				},
@@ -575,7 +609,9 @@ func TestTranslate_updateEdgeLBPoolObject(t *testing.T) {
 						Port: pointers.NewInt32(443),
 					},
 				},
+				PathType: pointers.NewString(translatorapi.IngressPathTypeImplementationSpecific),
 			},
+			kubeCache: dklbcache.NewInformerBackedResourceCache(cachetestutil.NewFakeSharedInformerFactory()),
 		}
 		mutator(it)
 		return it
@@ -653,7 +689,7 @@ func TestTranslate_updateEdgeLBPoolObject(t *testing.T) {
 			backendMap:        IngressBackendNodePortMap{},
 			expectedOperation: OperationResultUpdated,
 			it: newIngressTranslator(func(it *IngressTranslator) {
-				it.ingress.Spec.TLS = []extsv1beta1.IngressTLS{
+				it.ingress.Spec.TLS = []networkingv1.IngressTLS{
 					// in this test we update the name of the secret
 					{SecretName: "should-update-test-secret-1"},
 				}
@@ -982,3 +1018,145 @@ func TestTranslate_updateEdgeLBPoolObject(t *testing.T) {
 		assert.Nil(t, err)
 	}
 }
+
+// TestTranslate_deletionStrategies tests that the EdgeLB pool deletion strategies are honored when an Ingress resource is deleted.
+func TestTranslate_deletionStrategies(t *testing.T) {
+	cluster.Name = "test-cluster"
+
+	defaultService := servicetestutil.DummyServiceResource("kube-system", "dklb", func(service *corev1.Service) {
+		service.Spec.Type = corev1.ServiceTypeNodePort
+		service.Spec.Ports = []corev1.ServicePort{
+			{Port: 80, NodePort: 31789},
+		}
+	})
+	testService := servicetestutil.DummyServiceResource("test-namespace", "test-service", func(service *corev1.Service) {
+		service.Spec.Type = corev1.ServiceTypeNodePort
+		service.Spec.Ports = []corev1.ServicePort{
+			{Port: 80, NodePort: 31889},
+		}
+	})
+	// newIngress returns an Ingress resource using the specified EdgeLB pool deletion strategy.
+	newIngress := func(deletion translatorapi.EdgeLBPoolDeletionStrategy) *networkingv1.Ingress {
+		return &networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					constants.EdgeLBIngressClassAnnotationKey: constants.EdgeLBIngressClassAnnotationValue,
+					constants.DklbConfigAnnotationKey: fmt.Sprintf(`
+name: test-pool
+strategies:
+  deletion: %s
+`, deletion),
+				},
+				Namespace: "test-namespace",
+				Name:      "test-ingress",
+				UID:       "uid",
+			},
+			Spec: networkingv1.IngressSpec{
+				DefaultBackend: &networkingv1.IngressBackend{
+					Service: &networkingv1.IngressServiceBackend{
+						Name: testService.Name,
+						Port: networkingv1.ServiceBackendPort{Number: 80},
+					},
+				},
+			},
+		}
+	}
+	// newPool returns the EdgeLB pool created for the specified Ingress resource, optionally shared with an unmanaged EdgeLB frontend and EdgeLB backend.
+	newPool := func(ingress *networkingv1.Ingress, shared bool) *models.V2Pool {
+		var res *models.V2Pool
+		edgelbManager := new(mockedgelb.MockEdgeLBManager)
+		edgelbManager.On("PoolGroup").Return("test-pool-group")
+		edgelbManager.On("GetPool", mock.Anything, mock.Anything).Return(nil, nil)
+		edgelbManager.On("CreatePool", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			res = args.Get(1).(*models.V2Pool)
+		}).Return(&models.V2Pool{}, nil)
+		edgelbManager.On("GetPoolMetadata", mock.Anything, mock.Anything).Return(&models.V2PoolMetadata{}, nil)
+		kubeCache := dklbcache.NewInformerBackedResourceCache(cachetestutil.NewFakeSharedInformerFactory(defaultService, testService))
+		_, err := NewIngressTranslator(ingress, kubeCache, edgelbManager, record.NewFakeRecorder(10)).Translate()
+		assert.NoError(t, err)
+		if shared {
+			res.Haproxy.Backends = append(res.Haproxy.Backends, &models.V2Backend{Name: "unmanaged"})
+			res.Haproxy.Frontends = append(res.Haproxy.Frontends, &models.V2Frontend{
+				BindPort:    pointers.NewInt32(8080),
+				LinkBackend: &models.V2FrontendLinkBackend{DefaultBackend: "unmanaged"},
+				Name:        "unmanaged",
+				Protocol:    models.V2ProtocolHTTP,
+			})
+		}
+		return res
+	}
+
+	tests := []struct {
+		description           string
+		deletion              translatorapi.EdgeLBPoolDeletionStrategy
+		shared                bool
+		expectedDeletePool    bool
+		expectedUpdatedPool   bool
+		expectedPoolFrontends []string
+	}{
+		{
+			description:        "should delete the edgelb pool when it becomes empty",
+			deletion:           translatorapi.EdgeLBPoolDeletionStrategyDeleteWhenEmpty,
+			expectedDeletePool: true,
+		},
+		{
+			description: "should leave the edgelb pool untouched instead of emptying it when the strategy is Retain",
+			deletion:    translatorapi.EdgeLBPoolDeletionStrategyRetain,
+		},
+		{
+			description:           "should remove the ingress from a shared edgelb pool when the strategy is Retain",
+			deletion:              translatorapi.EdgeLBPoolDeletionStrategyRetain,
+			shared:                true,
+			expectedUpdatedPool:   true,
+			expectedPoolFrontends: []string{"unmanaged"},
+		},
+		{
+			description: "should leave the edgelb pool untouched when the strategy is Orphan",
+			deletion:    translatorapi.EdgeLBPoolDeletionStrategyOrphan,
+		},
+		{
+			description: "should leave a shared edgelb pool untouched when the strategy is Orphan",
+			deletion:    translatorapi.EdgeLBPoolDeletionStrategyOrphan,
+			shared:      true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+
+		ingress := newIngress(test.deletion)
+		pool := newPool(ingress, test.shared)
+		ingress.DeletionTimestamp = &metav1.Time{}
+
+		var updatedPool *models.V2Pool
+		edgelbManager := new(mockedgelb.MockEdgeLBManager)
+		edgelbManager.On("PoolGroup").Return("test-pool-group")
+		edgelbManager.On("GetPool", mock.Anything, "test-pool").Return(pool, nil)
+		edgelbManager.On("GetPoolMetadata", mock.Anything, mock.Anything).Return(&models.V2PoolMetadata{}, nil)
+		edgelbManager.On("DeletePool", mock.Anything, "test-pool").Return(nil)
+		edgelbManager.On("UpdatePool", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			updatedPool = args.Get(1).(*models.V2Pool)
+		}).Return(&models.V2Pool{}, nil)
+		kubeCache := dklbcache.NewInformerBackedResourceCache(cachetestutil.NewFakeSharedInformerFactory(defaultService, testService))
+
+		status, err := NewIngressTranslator(ingress, kubeCache, edgelbManager, record.NewFakeRecorder(10)).Translate()
+		assert.NoError(t, err)
+		assert.Equal(t, &corev1.LoadBalancerStatus{}, status)
+		if test.expectedDeletePool {
+			edgelbManager.AssertCalled(t, "DeletePool", mock.Anything, "test-pool")
+		} else {
+			edgelbManager.AssertNotCalled(t, "DeletePool", mock.Anything, mock.Anything)
+		}
+		if test.expectedUpdatedPool {
+			assert.NotNil(t, updatedPool)
+			frontends := make([]string, 0, len(updatedPool.Haproxy.Frontends))
+			for _, frontend := range updatedPool.Haproxy.Frontends {
+				frontends = append(frontends, frontend.Name)
+			}
+			assert.Equal(t, test.expectedPoolFrontends, frontends)
+			assert.Equal(t, []*models.V2Backend{{Name: "unmanaged"}}, updatedPool.Haproxy.Backends)
+		} else {
+			edgelbManager.AssertNotCalled(t, "UpdatePool", mock.Anything, mock.Anything)
+		}
+	}
+}
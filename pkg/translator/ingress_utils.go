@@ -1,19 +1,15 @@
 package translator
 
 import (
-	"crypto/tls"
 	"fmt"
-	"log"
 	"math"
-	"net"
 	"reflect"
+	"regexp"
 	"sort"
 	"strings"
-	"time"
 
 	"github.com/mesosphere/dcos-edge-lb/pkg/apis/models"
-	extsv1beta1 "k8s.io/api/extensions/v1beta1"
-	"k8s.io/apimachinery/pkg/util/intstr"
+	networkingv1 "k8s.io/api/networking/v1"
 
 	"github.com/mesosphere/dklb/pkg/cluster"
 	"github.com/mesosphere/dklb/pkg/constants"
@@ -25,22 +21,105 @@ import (
 )
 
 const (
+	// edgeLBBasicAuthRequireFormatString is the format string used to compute the HAProxy directive that requests authentication unless the provided password matches the expected hash.
+	edgeLBBasicAuthRequireFormatString = "http-request auth realm %s unless { http_auth_pass,sha1,base64,strcmp(txn.dklb_auth_hash) eq 0 }"
+	// edgeLBBasicAuthSetHashFormatString is the format string used to compute the HAProxy directive that looks up the expected password hash of the provided user in the specified pool secret file.
+	edgeLBBasicAuthSetHashFormatString = `http-request set-var(txn.dklb_auth_hash) http_auth_user,map("$SECRETS/%s")`
+	// edgeLBClientAuthBindModifierFormatString is the format string used to compute the options of the "bind" line that verify client certificates against the CA bundle stored in the specified pool secret file using the specified verification mode.
+	edgeLBClientAuthBindModifierFormatString = `ca-file "$SECRETS/%s" verify %s`
+	// edgeLBClientAuthCondition is the HAProxy condition that matches requests sent over a connection on which the client presented a certificate.
+	edgeLBClientAuthCondition = "{ ssl_c_used }"
+	// edgeLBClientAuthDelSubjectHeaderFormatString is the format string used to compute the HAProxy directive that removes the specified header from requests sent without a client certificate, so that it cannot be forged.
+	edgeLBClientAuthDelSubjectHeaderFormatString = "http-request del-header %s unless " + edgeLBClientAuthCondition
+	// edgeLBClientAuthSetSubjectHeaderFormatString is the format string used to compute the HAProxy directive that passes the subject of the client certificate to the backends in the specified header.
+	edgeLBClientAuthSetSubjectHeaderFormatString = "http-request set-header %s %%{+Q}[ssl_c_s_dn] if " + edgeLBClientAuthCondition
+	// edgeLBForcedCanaryCookieConditionFormatString is the format string used to compute the HAProxy condition that matches requests carrying the cookie that forces them to a canary.
+	edgeLBForcedCanaryCookieConditionFormatString = "{ req.cook(%s) -m str %s }"
+	// edgeLBForcedCanaryHeaderConditionFormatString is the format string used to compute the HAProxy condition that matches requests carrying the header that forces them to a canary.
+	edgeLBForcedCanaryHeaderConditionFormatString = "{ req.hdr(%s) -m str %s }"
+	// edgeLBForcedCanaryHostEqConditionFormatString is the format string used to compute the HAProxy condition that matches requests for a given host.
+	edgeLBForcedCanaryHostEqConditionFormatString = "{ req.hdr(host),field(1,:) -m str -i %s }"
+	// edgeLBForcedCanaryHostRegConditionFormatString is the format string used to compute the HAProxy condition that matches requests for hosts matching a given regular expression.
+	edgeLBForcedCanaryHostRegConditionFormatString = "{ req.hdr(host),field(1,:) -m reg -i %s }"
+	// edgeLBForcedCanaryPathRegConditionFormatString is the format string used to compute the HAProxy condition that matches requests for paths matching a given regular expression.
+	edgeLBForcedCanaryPathRegConditionFormatString = "{ path -m reg %s }"
+	// edgeLBHeaderDirectiveFormatString is the format string used to compute the HAProxy directive that performs a given action (e.g. "http-request del-header") on the specified HTTP header.
+	edgeLBHeaderDirectiveFormatString = "%s %s"
+	// edgeLBHeaderValueDirectiveFormatString is the format string used to compute the HAProxy directive that performs a given action (e.g. "http-response set-header") on the specified HTTP header using the specified value.
+	edgeLBHeaderValueDirectiveFormatString = "%s %s \"%s\""
 	// edgeLBHostCatchAllRegex is the regular expression used by EdgeLB to match all hosts.
 	edgeLBHostCatchAllRegex = "^.*$"
+	// edgeLBHostWildcardRegexFormatString is the format string used to compute the regular expression used by EdgeLB to match a given wildcard host.
+	// The leading "*" label is replaced by a pattern that matches exactly one DNS label.
+	edgeLBHostWildcardRegexFormatString = "^[^.]+%s$"
+	// edgeLBHostWildcardPrefix is the prefix that identifies a wildcard host.
+	edgeLBHostWildcardPrefix = "*"
+	// edgeLBHSTSIncludeSubDomains is the directive that extends the HSTS policy to subdomains.
+	edgeLBHSTSIncludeSubDomains = "includeSubDomains"
+	// edgeLBHSTSMaxAgeFormatString is the format string used to compute the directive that sets the lifetime of the HSTS policy.
+	edgeLBHSTSMaxAgeFormatString = "max-age=%d"
+	// edgeLBHSTSPreload is the directive that consents to the inclusion of the host in browsers' HSTS preload lists.
+	edgeLBHSTSPreload = "preload"
+	// edgeLBHSTSPrefix is the prefix of the HAProxy directive that advertises an HSTS policy to clients.
+	edgeLBHSTSPrefix = "http-response set-header Strict-Transport-Security "
 	// edgeLBIngressBackendNameFormatString is the format string used to compute the name for an EdgeLB backend corresponding to a given Ingress backend.
 	// The resulting name is of the form "<cluster-name>:<ingress-namespace>:<ingress-name>:<service-name>:<service-port>".
 	edgeLBIngressBackendNameFormatString = "%s:%s:%s:%s:%s"
 	// edgeLBIngressFrontendNameFormatString is the format string used to compute the name for an EdgeLB frontend corresponding to a given Ingress resource.
 	// The resulting name is of the form "<cluster-name>:<ingress-namespace>:<ingress-name>:<protocol>".
 	edgeLBIngressFrontendNameFormatString = "%s:%s:%s:%s"
+	// edgeLBMaxConnFormatString is the format string used to compute the HAProxy directive (or server option) that limits the number of concurrent connections.
+	edgeLBMaxConnFormatString = edgeLBMaxConnPrefix + "%d"
+	// edgeLBMaxConnPrefix is the prefix of the HAProxy directive (or server option) that limits the number of concurrent connections.
+	edgeLBMaxConnPrefix = "maxconn "
 	// edgeLBPathCatchAllRegex is the regular expression used by EdgeLB to match all paths.
 	edgeLBPathCatchAllRegex = "^.*$"
+	// edgeLBPathPrefixRegexFormatString is the format string used to compute the regular expression used by EdgeLB to match a given path prefix on path-segment boundaries.
+	edgeLBPathPrefixRegexFormatString = "^%s(/.*)?$"
 	// edgeLBPathRegexFormatString is the format string used to compute the regular expression used by EdgeLB to match a given path.
 	edgeLBPathRegexFormatString = "^%s$"
+	// edgeLBPathRootPrefixRegex is the regular expression used by EdgeLB to match all paths under the "/" prefix.
+	edgeLBPathRootPrefixRegex = "^/.*$"
+	// edgeLBRateLimitDenyFormatString is the format string used to compute the HAProxy directive that denies requests from clients exceeding the allowed request rate.
+	edgeLBRateLimitDenyFormatString = "http-request deny deny_status 429 if { sc_http_req_rate(0) gt %d }"
+	// edgeLBRateLimitStickTableFormatString is the format string used to compute the HAProxy directive that declares the table where the request rate of each client is stored.
+	edgeLBRateLimitStickTableFormatString = "stick-table type ipv6 size 100k expire %s store http_req_rate(%s)"
+	// edgeLBRateLimitTrackSourceFormatString is the format string used to compute the HAProxy directive that tracks the request rate of each client by its source IP in the table declared by a given EdgeLB backend.
+	edgeLBRateLimitTrackSourceFormatString = "http-request track-sc0 src table %s"
+	// edgeLBStickyInsertFormatString is the format string used to compute the arguments of the HAProxy "cookie" directive when EdgeLB inserts the session affinity cookie.
+	edgeLBStickyInsertFormatString = "%s insert indirect nocache"
+	// edgeLBStickyMaxLifeFormatString is the format string used to append the maximum lifetime of an inserted session affinity cookie to the arguments of the HAProxy "cookie" directive.
+	edgeLBStickyMaxLifeFormatString = "%s maxlife %s"
+	// edgeLBStickyPrefixFormatString is the format string used to compute the arguments of the HAProxy "cookie" directive when EdgeLB prefixes the session affinity cookie set by the application.
+	edgeLBStickyPrefixFormatString = "%s prefix nocache"
+	// edgeLBUseBackendFormatString is the format string used to compute the HAProxy directive that routes requests matching a given set of conditions to the specified EdgeLB backend.
+	edgeLBUseBackendFormatString = edgeLBUseBackendPrefix + "%s if %s"
+	// edgeLBUseBackendPrefix is the prefix of the HAProxy directive that routes requests to a given EdgeLB backend.
+	edgeLBUseBackendPrefix = "use_backend "
+	// edgeLBWeightFormatString is the format string used to compute the server option that sets the weight of a server.
+	edgeLBWeightFormatString = "weight %d"
+	// ingressAllowedSourcesACLNameFormatString is the format string used to compute the name of the HAProxy ACL matching the source ranges allowed to access a given Ingress resource.
+	// The resulting name is of the form "dklb_<ingress-uid>_allowed_sources".
+	ingressAllowedSourcesACLNameFormatString = "dklb_%s_allowed_sources"
+)
+
+var (
+	// haproxyHeaderValueReplacer escapes characters that have a special meaning inside a double-quoted string in the HAProxy configuration file.
+	haproxyHeaderValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	// haproxyRegexReplacer escapes characters that have a special meaning in the HAProxy configuration file but may legitimately appear in a regular expression.
+	haproxyRegexReplacer = strings.NewReplacer(" ", `\x20`, "\t", `\x09`, "#", `\x23`)
 )
 
 // IngressBackendNodePortMap represents a mapping between Ingress backends and their target node ports.
-type IngressBackendNodePortMap map[extsv1beta1.IngressBackend]int32
+type IngressBackendNodePortMap map[networkingv1.IngressServiceBackend]int32
+
+// IngressBackendPodEndpointsMap represents a mapping between Ingress backends and the endpoints of the (ready) pods backing them.
+// It is only populated when EdgeLB routes traffic directly to pod IPs, in which case it takes precedence over the corresponding IngressBackendNodePortMap.
+type IngressBackendPodEndpointsMap map[networkingv1.IngressServiceBackend][]podEndpoint
+
+// IngressBackendHealthCheckNodePortMap represents a mapping between Ingress backends and the health-check node port of the Service resources they target.
+// It is only populated when EdgeLB routes traffic to node ports, and only for Ingress backends targeting Service resources whose external traffic policy is "Local".
+type IngressBackendHealthCheckNodePortMap map[networkingv1.IngressServiceBackend]int32
 
 // ingressOwnedEdgeLBObjectMetadata groups together information about the Ingress resource that owns a given EdgeLB backend/frontend.
 type ingressOwnedEdgeLBObjectMetadata struct {
@@ -51,19 +130,38 @@ type ingressOwnedEdgeLBObjectMetadata struct {
 	// Namespace is the namespace to which the Ingress resource belongs.
 	Namespace string
 	// IngressBackend is the reconstructed IngressBackend object represented by the current EdgeLB object (in case said object is an EdgeLB backend).
-	IngressBackend *extsv1beta1.IngressBackend
+	IngressBackend *networkingv1.IngressServiceBackend
 	// Protocol is either http or https
 	Protocol string
 }
 
 // prioritizedMatchingRule is a helper struct used to associate a priority with an EdgeLB "V2FrontendLinkBackendMapItems0".
 type prioritizedMatchingRule struct {
-	item     *models.V2FrontendLinkBackendMapItems0
-	priority int
+	// backend is the Ingress backend targeted by the rule.
+	backend networkingv1.IngressServiceBackend
+	item    *models.V2FrontendLinkBackendMapItems0
+	// hostPriority is the priority of the rule according to its ".host".
+	hostPriority int
+	// exact indicates whether the rule matches its ".path" exactly.
+	exact bool
+	// pathPriority is the priority of the rule according to its ".path".
+	pathPriority int
+}
+
+// precedes indicates whether the current rule must be matched by HAProxy before the specified one.
+// Rules are ordered first by their host priority, then by preferring exact path matches, and finally by their path priority.
+func (r prioritizedMatchingRule) precedes(other prioritizedMatchingRule) bool {
+	if r.hostPriority != other.hostPriority {
+		return r.hostPriority > other.hostPriority
+	}
+	if r.exact != other.exact {
+		return r.exact
+	}
+	return r.pathPriority > other.pathPriority
 }
 
 // IsOwnedBy indicates whether the current EdgeLB object is owned by the specified Ingress resource.
-func (m *ingressOwnedEdgeLBObjectMetadata) IsOwnedBy(ingress *extsv1beta1.Ingress) bool {
+func (m *ingressOwnedEdgeLBObjectMetadata) IsOwnedBy(ingress *networkingv1.Ingress) bool {
 	if m == nil {
 		return false
 	}
@@ -71,63 +169,206 @@ func (m *ingressOwnedEdgeLBObjectMetadata) IsOwnedBy(ingress *extsv1beta1.Ingres
 }
 
 // computeEdgeLBBackendForIngressBackend computes the EdgeLB backend that corresponds to the specified Ingress backend.
-func computeEdgeLBBackendForIngressBackend(ingress *extsv1beta1.Ingress, backend extsv1beta1.IngressBackend, nodePort int32) *models.V2Backend {
-
-	return &models.V2Backend{
-		Balance:  constants.EdgeLBBackendBalanceLeastConnections,
+// Ingress backends present in the specified pod endpoints map target the endpoints of their pods directly, while all others target their node port.
+// Ingress backends present in the specified health-check node port map (as well as all of their canaries) are health-checked using said health-check node port.
+func computeEdgeLBBackendForIngressBackend(ingress *networkingv1.Ingress, spec translatorapi.IngressEdgeLBPoolSpec, backend networkingv1.IngressServiceBackend, backendMap IngressBackendNodePortMap, podEndpointsMap IngressBackendPodEndpointsMap, healthCheckNodePortMap IngressBackendHealthCheckNodePortMap) *models.V2Backend {
+	backendSpec := spec.BackendSpecFor(backend)
+	res := &models.V2Backend{
 		Name:     computeEdgeLBBackendNameForIngressBackend(ingress, backend),
 		Protocol: models.V2ProtocolHTTP,
-		// When the target server is TLS-enabled, HAProxy **MUST** be configured with the "ssl verify none" option (so that it actually communicates over TLS **AND** skips certificate verification).
+		// The protocol spoken by the target server is declared in the EdgeLB pool configuration object.
+		// When the target server speaks HTTPS, HAProxy **MUST** be configured to communicate over TLS and to verify the certificate presented by the server against the provided CA bundle.
 		// This will result in an HAProxy config similar to the following one:
 		//
 		// backend ingress-backend
 		//    mode http
-		//    server 1.2.3.4:5678 check check-ssl ssl verify none
-		//    server 4.3.2.1:5678 check
-		Services: []*models.V2Service{
-			{
-				Endpoint: &models.V2Endpoint{
-					Check: &models.V2EndpointCheck{
-						Enabled: pointers.NewBool(true),
-					},
-					MiscStr: computeEdgeLBBackendMiscStr(ingress, backend),
-					Port:    nodePort,
-					Type:    models.V2EndpointTypeCONTAINERIP,
-				},
-				Marathon: &models.V2ServiceMarathon{
-					// We don't want to use any Marathon service as the backend.
-				},
-				Mesos: &models.V2ServiceMesos{
-					FrameworkName:   cluster.Name,
-					TaskNamePattern: constants.KubeNodeTaskPattern,
-				},
+		//    server 1.2.3.4:5678 check check-ssl ssl verify required ca-file "$SECRETS/<uid>__<secret>__ca"
+		Services:    computeEdgeLBServicesForIngressBackend(ingress, backendSpec, backend, backendMap, podEndpointsMap, nil),
+		RewriteHTTP: computeEdgeLBRewriteHTTPForIngressBackend(ingress, spec, backend),
+	}
+	// Split traffic between the target Service resource and its canaries (if any) according to the requested weights.
+	// As every Service resource is exposed on every Kubernetes node, each server's weight is the weight of the corresponding Service resource.
+	// This will result in an HAProxy config similar to the following one:
+	//
+	// backend ingress-backend
+	//    server 1.2.3.4:5678 check weight 90
+	//    server 1.2.3.4:6789 check weight 10
+	if len(backendSpec.Canaries) > 0 {
+		res.Services = computeEdgeLBServicesForIngressBackend(ingress, backendSpec, backend, backendMap, podEndpointsMap, pointers.NewInt32(backendSpec.PrimaryWeight()))
+		for _, canary := range backendSpec.Canaries {
+			res.Services = append(res.Services, computeEdgeLBServicesForIngressBackend(ingress, backendSpec, canary.IngressBackend(), backendMap, podEndpointsMap, canary.Weight)...)
+		}
+	}
+	// Apply the load-balancing and health-check configuration for the Ingress backend.
+	applyBaseEdgeLBPoolBackendSpec(res, &backendSpec.BaseEdgeLBPoolBackendSpec)
+	// Make sure that EdgeLB doesn't route traffic to Kubernetes nodes that don't host ready pods for Service resources whose external traffic policy is "Local".
+	applyEdgeLBBackendHealthCheckNodePorts(res, computeHealthCheckNodePortsForIngressBackend(spec, backend, healthCheckNodePortMap))
+	// Apply the server-side timeouts for the Ingress backend.
+	res.MiscStrs = append(res.MiscStrs, computeEdgeLBBackendTimeoutMiscStrs(spec.TimeoutsSpecFor(backend))...)
+	// Restrict access to the Ingress resource to the source ranges specified for it (if any).
+	// This is done at the EdgeLB backend level so that clients of other Ingress resources sharing the same EdgeLB frontend are not affected.
+	res.MiscStrs = append(res.MiscStrs, computeEdgeLBBackendSourceRangesMiscStrs(fmt.Sprintf(ingressAllowedSourcesACLNameFormatString, ingress.UID), spec.AllowedSourceRanges)...)
+	// Require authentication for the Ingress resource, if requested.
+	res.MiscStrs = append(res.MiscStrs, computeEdgeLBBasicAuthMiscStrs(ingress, spec.BasicAuth)...)
+	// Apply the changes to perform on the HTTP headers of requests and responses going through the Ingress resource.
+	res.MiscStrs = append(res.MiscStrs, computeEdgeLBHeadersMiscStrs(spec.Headers)...)
+	return res
+}
+
+// computeHealthCheckNodePortsForIngressBackend computes the health-check node ports of the Service resources targeted by the specified Ingress backend and its canaries (in this order).
+// It returns nil in case any of said Service resources is not present in the specified health-check node port map, as the EdgeLB backend must then be health-checked as usual.
+func computeHealthCheckNodePortsForIngressBackend(spec translatorapi.IngressEdgeLBPoolSpec, backend networkingv1.IngressServiceBackend, healthCheckNodePortMap IngressBackendHealthCheckNodePortMap) []int32 {
+	backends := []networkingv1.IngressServiceBackend{backend}
+	for _, canary := range spec.BackendSpecFor(backend).Canaries {
+		backends = append(backends, canary.IngressBackend())
+	}
+	res := make([]int32, 0, len(backends))
+	for _, b := range backends {
+		healthCheckNodePort, exists := healthCheckNodePortMap[b]
+		if !exists {
+			return nil
+		}
+		res = append(res, healthCheckNodePort)
+	}
+	return res
+}
+
+// computeEdgeLBServicesForIngressBackend computes the EdgeLB services that target the specified Ingress backend using the specified Ingress backend configuration.
+// In case the Ingress backend is present in the specified pod endpoints map, an EdgeLB service is computed for each of its pod endpoints.
+// Otherwise, a single EdgeLB service targeting the Ingress backend's node port is computed.
+// If a weight is specified, it is set on every server of the EdgeLB services.
+func computeEdgeLBServicesForIngressBackend(ingress *networkingv1.Ingress, backendSpec *translatorapi.IngressEdgeLBPoolBackendSpec, backend networkingv1.IngressServiceBackend, backendMap IngressBackendNodePortMap, podEndpointsMap IngressBackendPodEndpointsMap, weight *int32) []*models.V2Service {
+	podEndpoints, exists := podEndpointsMap[backend]
+	if !exists {
+		return []*models.V2Service{computeEdgeLBServiceForIngressBackend(ingress, backendSpec, backendMap[backend], weight)}
+	}
+	res := make([]*models.V2Service, 0, len(podEndpoints))
+	for _, endpoint := range podEndpoints {
+		res = append(res, computeEdgeLBServiceForPodEndpoint(endpoint, computeEdgeLBServiceMiscStrForIngressBackend(ingress, backendSpec, weight)))
+	}
+	return res
+}
+
+// computeEdgeLBServiceForIngressBackend computes the EdgeLB service that targets the specified node port using the specified Ingress backend configuration.
+// If a weight is specified, it is set on every server of the EdgeLB service.
+func computeEdgeLBServiceForIngressBackend(ingress *networkingv1.Ingress, backendSpec *translatorapi.IngressEdgeLBPoolBackendSpec, nodePort int32, weight *int32) *models.V2Service {
+	miscStr := computeEdgeLBServiceMiscStrForIngressBackend(ingress, backendSpec, weight)
+	return &models.V2Service{
+		Endpoint: &models.V2Endpoint{
+			Check: &models.V2EndpointCheck{
+				Enabled: pointers.NewBool(true),
 			},
+			MiscStr: miscStr,
+			Port:    nodePort,
+			Type:    models.V2EndpointTypeCONTAINERIP,
 		},
-		RewriteHTTP: &models.V2RewriteHTTP{
-			Request: &models.V2RewriteHTTPRequest{
-				// Add the "X-Forwarded-For" header to requests.
-				Forwardfor: pointers.NewBool(true),
-				// Add the "X-Forwarded-Port" header to requests.
-				XForwardedPort: pointers.NewBool(true),
-				// Add the "X-Forwarded-Proto" header to requests.
-				XForwardedProtoHTTPSIfTLS: pointers.NewBool(true),
-				// Explicitly disable rewriting of paths.
-				RewritePath: pointers.NewBool(false),
-				// Explicitly disable setting the "Host" header on requests.
-				// This header should be set by clients alone.
-				SetHostHeader: pointers.NewBool(false),
-			},
-			Response: &models.V2RewriteHTTPResponse{
-				// Explicitly disable rewriting locations.
-				RewriteLocation: pointers.NewBool(false),
-			},
+		Marathon: &models.V2ServiceMarathon{
+			// We don't want to use any Marathon service as the backend.
+		},
+		Mesos: &models.V2ServiceMesos{
+			FrameworkName:   cluster.Name,
+			TaskNamePattern: constants.KubeNodeTaskPattern,
 		},
 	}
 }
 
+// computeEdgeLBServiceMiscStrForIngressBackend computes the value to be used as "miscStr" on the servers of a given EdgeLB service using the specified Ingress backend configuration and weight.
+func computeEdgeLBServiceMiscStrForIngressBackend(ingress *networkingv1.Ingress, backendSpec *translatorapi.IngressEdgeLBPoolBackendSpec, weight *int32) string {
+	miscStr := computeEdgeLBBackendMiscStr(ingress, backendSpec)
+	if weight != nil {
+		miscStr = strings.TrimSpace(miscStr + " " + fmt.Sprintf(edgeLBWeightFormatString, *weight))
+	}
+	return miscStr
+}
+
+// computeIngressCanaryBackends computes the set of Ingress backends used as canaries by the Ingress backends of the specified Ingress resource.
+// The value associated with each canary indicates whether requests can be forced to it, in which case it requires an EdgeLB backend of its own.
+func computeIngressCanaryBackends(ingress *networkingv1.Ingress, spec translatorapi.IngressEdgeLBPoolSpec) map[networkingv1.IngressServiceBackend]bool {
+	res := make(map[networkingv1.IngressServiceBackend]bool)
+	kubernetesutil.ForEachIngresBackend(ingress, func(_ *string, _ *networkingv1.HTTPIngressPath, backend networkingv1.IngressServiceBackend) {
+		for _, canary := range spec.BackendSpecFor(backend).Canaries {
+			res[canary.IngressBackend()] = res[canary.IngressBackend()] || canary.IsForceable()
+		}
+	})
+	return res
+}
+
+// computeEdgeLBRewriteHTTPForIngressBackend computes the HTTP rewriting configuration of the EdgeLB backend that corresponds to the specified Ingress backend.
+func computeEdgeLBRewriteHTTPForIngressBackend(ingress *networkingv1.Ingress, spec translatorapi.IngressEdgeLBPoolSpec, backend networkingv1.IngressServiceBackend) *models.V2RewriteHTTP {
+	rewriteSpec := spec.RewriteSpecFor(backend)
+	res := &models.V2RewriteHTTP{
+		Request: &models.V2RewriteHTTPRequest{
+			// Add the "X-Forwarded-For" header to requests unless explicitly disabled.
+			Forwardfor: pointers.NewBool(rewriteSpec.ForwardedFor == nil || *rewriteSpec.ForwardedFor),
+			// Add the "X-Forwarded-Port" header to requests.
+			XForwardedPort: pointers.NewBool(true),
+			// Add the "X-Forwarded-Proto" header to requests.
+			XForwardedProtoHTTPSIfTLS: pointers.NewBool(true),
+			// Disable rewriting of paths unless a rewrite target has been specified.
+			RewritePath: pointers.NewBool(false),
+			// Disable setting the "Host" header on requests unless an upstream host has been specified.
+			// By default, this header should be set by clients alone.
+			SetHostHeader: pointers.NewBool(false),
+		},
+		Response: &models.V2RewriteHTTPResponse{
+			// Disable rewriting locations unless explicitly enabled.
+			RewriteLocation: pointers.NewBool(rewriteSpec.RewriteLocation != nil && *rewriteSpec.RewriteLocation),
+		},
+		Sticky: computeEdgeLBStickyForIngressBackend(spec, backend),
+	}
+	if rewriteSpec.UpstreamHost != nil {
+		res.Host = *rewriteSpec.UpstreamHost
+		res.Request.SetHostHeader = pointers.NewBool(true)
+	}
+	if rewriteSpec.RewriteTarget != nil {
+		// Validation guarantees that the Ingress backend is referenced by at most one path, which is the prefix to be replaced by the rewrite target.
+		// If the Ingress backend is only used as the default backend there is no prefix to replace, and hence paths are not rewritten.
+		if paths := kubernetesutil.IngressBackendPaths(ingress, backend); len(paths) == 1 {
+			fromPath := strings.TrimRight(paths[0], "/")
+			if fromPath == "" {
+				fromPath = "/"
+			}
+			res.Path = &models.V2RewriteHTTPPath{
+				FromPath: fromPath,
+				ToPath:   *rewriteSpec.RewriteTarget,
+			}
+			res.Request.RewritePath = pointers.NewBool(true)
+		}
+	}
+	return res
+}
+
+// computeEdgeLBStickyForIngressBackend computes the session affinity configuration of the EdgeLB backend that corresponds to the specified Ingress backend.
+// It returns nil in case session affinity is disabled for the Ingress backend.
+func computeEdgeLBStickyForIngressBackend(spec translatorapi.IngressEdgeLBPoolSpec, backend networkingv1.IngressServiceBackend) *models.V2RewriteHTTPSticky {
+	affinitySpec := spec.AffinitySpecFor(backend)
+	if affinitySpec == nil || *affinitySpec.Mode == translatorapi.IngressAffinityModeNone {
+		return nil
+	}
+	// Compute the arguments to the HAProxy "cookie" directive.
+	// This will result in an HAProxy config similar to the following one:
+	//
+	// backend ingress-backend
+	//    cookie DKLB_AFFINITY insert indirect nocache maxlife 3600000ms
+	var args string
+	switch *affinitySpec.Mode {
+	case translatorapi.IngressAffinityModePrefix:
+		args = fmt.Sprintf(edgeLBStickyPrefixFormatString, *affinitySpec.CookieName)
+	default:
+		args = fmt.Sprintf(edgeLBStickyInsertFormatString, *affinitySpec.CookieName)
+		if affinitySpec.TTL != nil {
+			args = fmt.Sprintf(edgeLBStickyMaxLifeFormatString, args, toHAProxyDuration(*affinitySpec.TTL))
+		}
+	}
+	return &models.V2RewriteHTTPSticky{
+		Enabled:   pointers.NewBool(true),
+		CustomStr: args,
+	}
+}
+
 // computeEdgeLBBackendNameForIngressBackend computes the name of the EdgeLB backend that corresponds to the specified Ingress backend.
-func computeEdgeLBBackendNameForIngressBackend(ingress *extsv1beta1.Ingress, backend extsv1beta1.IngressBackend) string {
-	return fmt.Sprintf(edgeLBIngressBackendNameFormatString, dklbstrings.ReplaceForwardSlashesWithDots(cluster.Name), ingress.Namespace, ingress.Name, backend.ServiceName, backend.ServicePort.String())
+func computeEdgeLBBackendNameForIngressBackend(ingress *networkingv1.Ingress, backend networkingv1.IngressServiceBackend) string {
+	return fmt.Sprintf(edgeLBIngressBackendNameFormatString, dklbstrings.ReplaceForwardSlashesWithDots(cluster.Name), ingress.Namespace, ingress.Name, backend.Name, kubernetesutil.IngressServiceBackendPort(backend))
 }
 
 // findFrontends returns a copy of the frontend from edgelb pool bound to the
@@ -150,9 +391,15 @@ func findFrontend(pool *models.V2Pool, port int32, name string) *models.V2Fronte
 }
 
 // computeEdgeLBFrontendForIngress computes the EdgeLB frontend that corresponds to the specified Ingress resource.
-func computeEdgeLBFrontendForIngress(ingress *extsv1beta1.Ingress, spec translatorapi.IngressEdgeLBPoolSpec, pool *models.V2Pool) []*models.V2Frontend {
+func computeEdgeLBFrontendForIngress(ingress *networkingv1.Ingress, spec translatorapi.IngressEdgeLBPoolSpec, pool *models.V2Pool) []*models.V2Frontend {
 	// Compute the base frontend object.
 	frontends := make([]*models.V2Frontend, 0)
+	// Compute the client certificate authentication configuration (if any).
+	// It must be applied to the HTTP frontend as well, so that clients cannot forge the header used to pass the subject of client certificates.
+	var clientAuth *translatorapi.IngressEdgeLBPoolClientAuthSpec
+	if spec.Frontends.HTTPS != nil {
+		clientAuth = spec.Frontends.HTTPS.ClientAuth
+	}
 	// check if HTTP frontend is enabled
 	if spec.Frontends.HTTP != nil && *spec.Frontends.HTTP.Mode != translatorapi.IngressEdgeLBHTTPModeDisabled {
 		// check if there's already an http frontend
@@ -168,6 +415,12 @@ func computeEdgeLBFrontendForIngress(ingress *extsv1beta1.Ingress, spec translat
 			}
 		}
 		httpFrontend.BindPort = spec.Frontends.HTTP.Port
+		// Only set the maximum number of connections, the client-side timeouts and the client certificate configuration on frontends owned by the current Ingress resource, as other Ingress resources may share the frontend.
+		if computeIngressOwnedEdgeLBObjectMetadata(httpFrontend.Name).IsOwnedBy(ingress) {
+			applyEdgeLBFrontendMaxConn(httpFrontend, spec.Frontends.HTTP.MaxConn)
+			replaceEdgeLBFrontendMiscStrs(httpFrontend, isEdgeLBFrontendTimeoutMiscStr, computeEdgeLBFrontendTimeoutMiscStrs(spec.Timeouts))
+			applyEdgeLBFrontendClientAuthHeader(httpFrontend, clientAuth)
+		}
 		if *spec.Frontends.HTTP.Mode == translatorapi.IngressEdgeLBHTTPModeRedirect {
 			// Setting this to the empty object is enough to redirect all
 			// traffic from HTTP (this frontend) to HTTPS (port 443).
@@ -191,12 +444,20 @@ func computeEdgeLBFrontendForIngress(ingress *extsv1beta1.Ingress, spec translat
 			}
 		}
 		httpsFrontend.BindPort = spec.Frontends.HTTPS.Port
+		// Only set the maximum number of connections, the client-side timeouts, the HSTS policy and the client certificate configuration on frontends owned by the current Ingress resource, as other Ingress resources may share the frontend.
+		if computeIngressOwnedEdgeLBObjectMetadata(httpsFrontend.Name).IsOwnedBy(ingress) {
+			applyEdgeLBFrontendMaxConn(httpsFrontend, spec.Frontends.HTTPS.MaxConn)
+			replaceEdgeLBFrontendMiscStrs(httpsFrontend, isEdgeLBFrontendTimeoutMiscStr, computeEdgeLBFrontendTimeoutMiscStrs(spec.Timeouts))
+			applyEdgeLBFrontendHSTS(httpsFrontend, spec.Frontends.HTTPS.HSTS)
+			applyEdgeLBFrontendClientAuthHeader(httpsFrontend, clientAuth)
+			httpsFrontend.BindModifier = computeEdgeLBClientAuthBindModifier(ingress, clientAuth)
+		}
 
 		// filter certicates created for this ingress in case any updates
 		// were made
 		certificates := make([]string, 0)
+		secretPrefix := computeEdgeLBSecretFilePath(secretsreflector.ComputeDCOSSecretName(string(ingress.UID), ""))
 		for _, c := range httpsFrontend.Certificates {
-			secretPrefix := fmt.Sprintf("$SECRETS/%s__", string(ingress.UID))
 			if !strings.HasPrefix(c, secretPrefix) {
 				certificates = append(certificates, c)
 			}
@@ -205,7 +466,7 @@ func computeEdgeLBFrontendForIngress(ingress *extsv1beta1.Ingress, spec translat
 		// add the certificates required by this ingress
 		for _, ingressTLS := range ingress.Spec.TLS {
 			// Compute the filename for the given secret
-			cert := fmt.Sprintf("$SECRETS/%s__%s", ingress.UID, ingressTLS.SecretName)
+			cert := computeEdgeLBSecretFilePath(secretsreflector.ComputeDCOSSecretName(string(ingress.UID), ingressTLS.SecretName))
 			certificates = append(certificates, cert)
 		}
 		httpsFrontend.Certificates = certificates
@@ -213,13 +474,21 @@ func computeEdgeLBFrontendForIngress(ingress *extsv1beta1.Ingress, spec translat
 		frontends = append(frontends, httpsFrontend)
 	}
 
+	// Access to the Ingress resource is restricted on its EdgeLB backends, so make sure that no restriction for it is left on the (possibly shared) frontends.
+	removeIngressAllowedSourcesFromEdgeLBFrontends(ingress, frontends)
+
 	// Create the slice that will hold the set of matching rules.
 	var rules []prioritizedMatchingRule
+	// defaultBackend holds the default backend of the Ingress resource.
+	var defaultBackend *networkingv1.IngressServiceBackend
 
 	// Iterate over Ingress backends, building the corresponding "V2FrontendLinkBackendMapItems0" EdgeLB object.
-	kubernetesutil.ForEachIngresBackend(ingress, func(host, path *string, backend extsv1beta1.IngressBackend) {
+	kubernetesutil.ForEachIngresBackend(ingress, func(host *string, path *networkingv1.HTTPIngressPath, backend networkingv1.IngressServiceBackend) {
 		switch {
 		case host == nil && path == nil:
+			// Pin "backend" so we can take its address.
+			backend := backend
+			defaultBackend = &backend
 			// link frontends and backends
 			for _, frontend := range frontends {
 				// don't override default backend
@@ -229,10 +498,10 @@ func computeEdgeLBFrontendForIngress(ingress *extsv1beta1.Ingress, spec translat
 			}
 		default:
 			rule := prioritizedMatchingRule{
+				backend: backend,
 				item: &models.V2FrontendLinkBackendMapItems0{
 					Backend: computeEdgeLBBackendNameForIngressBackend(ingress, backend),
 				},
-				priority: 0,
 			}
 
 			switch {
@@ -240,28 +509,33 @@ func computeEdgeLBFrontendForIngress(ingress *extsv1beta1.Ingress, spec translat
 				// No value (or an empty value) was specified for ".host".
 				// Hence we set this rule's priority as the lowest possible one, causing HAProxy to match it only **AFTER** any other rules specifying a non-empty ".host".
 				rule.item.HostReg = edgeLBHostCatchAllRegex
-				rule.priority = math.MinInt32
+				rule.hostPriority = math.MinInt32
+			case strings.HasPrefix(*host, edgeLBHostWildcardPrefix):
+				// A wildcard value was specified for ".host".
+				// Hence we set this rule's priority right below the normal level, causing HAProxy to match it **AFTER** any other rules specifying an exact ".host" but **BEFORE** any other rules specifying an empty ".host".
+				rule.item.HostReg = fmt.Sprintf(edgeLBHostWildcardRegexFormatString, regexp.QuoteMeta(strings.TrimPrefix(*host, edgeLBHostWildcardPrefix)))
+				rule.hostPriority = -1
 			default:
 				// A non-empty value was specified for ".host".
-				// Hence we set this rule's priority to a normal level, causing HAProxy to match it **BEFORE** any other rules specifying an empty ".host".
+				// Hence we set this rule's priority to a normal level, causing HAProxy to match it **BEFORE** any other rules specifying a wildcard or an empty ".host".
 				rule.item.HostEq = *host
-				rule.priority = 0
+				rule.hostPriority = 0
 			}
 
 			switch {
-			case path == nil || *path == "":
+			case path == nil || path.Path == "":
 				// No value (or an empty value) was specified for ".path".
 				// Hence we keep this rule's priority as-is, causing HAProxy to match it only **AFTER** any other rules specifying a non-empty ".path".
 				rule.item.PathReg = edgeLBPathCatchAllRegex
-				rule.priority += 0
+				rule.pathPriority = 0
 			default:
 				// A non-empty value was specified for ".path".
-				// Hence we add the length of the path to this rule's priority, causing HAProxy to match it **BEFORE** any other rules specifying a shorter ".path".
-				// TODO (@bcustodio) HAProxy uses PCRE regular expressions, while the Ingress spec dictates that regular expressions follow the egrep (IEEE Std 1003.1) syntax.
-				// TODO (@bcustodio) https://kubernetes.io/docs/reference/generated/kubernetes-api/v1.13/#httpingresspath-v1beta1-extensions
-				// TODO (@bcustodio) We need to understand whether "translation" is required/desirable (and possible), or accept PCRE and document that the syntax for paths does not follow the spec.
-				rule.item.PathReg = fmt.Sprintf(edgeLBPathRegexFormatString, *path)
-				rule.priority += len(*path)
+				// Hence we use the length of the path as this rule's priority, causing HAProxy to match it **BEFORE** any other rules specifying a shorter ".path".
+				// Exact matches take precedence over any other kind of match, as dictated by the Ingress spec.
+				pathType := spec.PathTypeFor(path)
+				rule.item.PathReg = computeEdgeLBPathRegex(path.Path, pathType)
+				rule.exact = pathType == translatorapi.IngressPathTypeExact
+				rule.pathPriority = len(path.Path)
 			}
 
 			rules = append(rules, rule)
@@ -270,7 +544,7 @@ func computeEdgeLBFrontendForIngress(ingress *extsv1beta1.Ingress, spec translat
 
 	// Sort rules by descending order of their priority.
 	sort.SliceStable(rules, func(i, j int) bool {
-		return rules[i].priority > rules[j].priority
+		return rules[i].precedes(rules[j])
 	})
 	// Add each rule to the final slice of rules but check for potencial duplicates first.
 	for _, rule := range rules {
@@ -280,10 +554,50 @@ func computeEdgeLBFrontendForIngress(ingress *extsv1beta1.Ingress, spec translat
 			}
 		}
 	}
+	// Force requests carrying the header or cookie of a canary to the canary's EdgeLB backend, following the same order as the matching rules.
+	// Frontends may be shared with other Ingress resources, so we only replace the directives previously created for this Ingress resource.
+	// As HAProxy evaluates "use_backend" rules in the order in which they appear, these directives are then moved ahead of any other directive of the EdgeLB frontend, so that they come before the rules computed from its map.
+	for _, frontend := range frontends {
+		miscStrs := make([]string, 0)
+		for _, rule := range rules {
+			miscStrs = append(miscStrs, computeEdgeLBForcedCanaryMiscStrs(ingress, spec, rule.backend, rule.item)...)
+		}
+		if defaultBackend != nil && frontend.LinkBackend.DefaultBackend == computeEdgeLBBackendNameForIngressBackend(ingress, *defaultBackend) {
+			miscStrs = append(miscStrs, computeEdgeLBForcedCanaryMiscStrs(ingress, spec, *defaultBackend, nil)...)
+		}
+		replaceEdgeLBFrontendMiscStrs(frontend, func(m string) bool {
+			return isEdgeLBUseBackendMiscStrOwnedBy(m, ingress)
+		}, miscStrs)
+		moveEdgeLBUseBackendMiscStrsFirst(frontend)
+	}
 	// Return the computed EdgeLB frontend objects.
 	return frontends
 }
 
+// computeEdgeLBPathRegex computes the regular expression used by EdgeLB to match the specified (non-empty) path according to the specified path type.
+func computeEdgeLBPathRegex(path, pathType string) string {
+	switch pathType {
+	case translatorapi.IngressPathTypeExact:
+		// Match the path literally.
+		return fmt.Sprintf(edgeLBPathRegexFormatString, escapeHAProxyRegex(regexp.QuoteMeta(path)))
+	case translatorapi.IngressPathTypePrefix:
+		// Match the path and any path below it, ignoring any trailing "/" as dictated by the Ingress spec (e.g. "/foo/" matches "/foo" and "/foo/bar" but not "/foobar").
+		prefix := strings.TrimRight(path, "/")
+		if prefix == "" {
+			return edgeLBPathRootPrefixRegex
+		}
+		return fmt.Sprintf(edgeLBPathPrefixRegexFormatString, escapeHAProxyRegex(regexp.QuoteMeta(prefix)))
+	default:
+		// The path is an egrep (IEEE Std 1003.1) regular expression, which must be converted to the PCRE syntax used by HAProxy.
+		return fmt.Sprintf(edgeLBPathRegexFormatString, escapeHAProxyRegex(translatorapi.ConvertEgrepToPCRE(path)))
+	}
+}
+
+// escapeHAProxyRegex escapes the specified regular expression so that it can be safely used as an argument in the HAProxy configuration file.
+func escapeHAProxyRegex(expr string) string {
+	return haproxyRegexReplacer.Replace(expr)
+}
+
 func findRule(list []*models.V2FrontendLinkBackendMapItems0, item *models.V2FrontendLinkBackendMapItems0) bool {
 	for _, entry := range list {
 		if reflect.DeepEqual(item, entry) {
@@ -294,51 +608,308 @@ func findRule(list []*models.V2FrontendLinkBackendMapItems0, item *models.V2Fron
 }
 
 // computeEdgeLBFrontendNameForIngress computes the name of the EdgeLB frontend that corresponds to the specified Ingress resource.
-func computeEdgeLBFrontendNameForIngress(ingress *extsv1beta1.Ingress, protocol string) string {
+func computeEdgeLBFrontendNameForIngress(ingress *networkingv1.Ingress, protocol string) string {
 	return fmt.Sprintf(edgeLBIngressFrontendNameFormatString, dklbstrings.ReplaceForwardSlashesWithDots(cluster.Name), ingress.Namespace, ingress.Name, strings.ToLower(protocol))
 }
 
 // computeEdgeLBBackendMiscStr computes the value to be used as "miscStr" on a given backend given the specified options.
-func computeEdgeLBBackendMiscStr(ingress *extsv1beta1.Ingress, backend extsv1beta1.IngressBackend) string {
-	// try to resolve service port if it's using a string
-	var err error
-	port := backend.ServicePort.IntValue()
-	if port == 0 {
-		port, err = net.LookupPort("tcp", backend.ServicePort.String())
-		if err != nil {
-			log.Printf("error looking up port %s: %v", backend.ServicePort.String(), err)
-			return ""
+func computeEdgeLBBackendMiscStr(ingress *networkingv1.Ingress, backendSpec *translatorapi.IngressEdgeLBPoolBackendSpec) string {
+	parts := make([]string, 0, 3)
+	switch *backendSpec.BackendProtocol {
+	case translatorapi.IngressBackendProtocolHTTPS:
+		fileName := secretsreflector.ComputeDCOSSecretFileName(secretsreflector.ComputeDCOSCASecretName(string(ingress.UID), *backendSpec.CASecretName))
+		parts = append(parts, constants.EdgeLBBackendTLSCheck, fmt.Sprintf(constants.EdgeLBBackendVerifyTLSFormatString, fileName))
+	case translatorapi.IngressBackendProtocolH2C:
+		parts = append(parts, constants.EdgeLBBackendH2C)
+	}
+	// Limit the number of concurrent connections established to each server, if requested.
+	if backendSpec.MaxConn != nil {
+		parts = append(parts, fmt.Sprintf(edgeLBMaxConnFormatString, *backendSpec.MaxConn))
+	}
+	return strings.Join(parts, " ")
+}
+
+// computeEdgeLBBasicAuthMiscStrs computes the HAProxy directives that require HTTP basic authentication for requests going through a given EdgeLB backend.
+// It returns nil in case no authentication has been requested.
+// Directives are set on the EdgeLB backend (which is owned by a single Ingress resource and only reached through its hosts and paths) rather than on the EdgeLB frontend (which may be shared by several Ingress resources).
+func computeEdgeLBBasicAuthMiscStrs(ingress *networkingv1.Ingress, spec *translatorapi.IngressEdgeLBPoolBasicAuthSpec) []string {
+	if spec == nil {
+		return nil
+	}
+	// The reflected secret is an HAProxy map file associating each user with the base64-encoded SHA-1 hash of its password.
+	// Requests are denied unless the hash of the provided password matches the one associated with the provided user.
+	// This will result in an HAProxy config similar to the following one:
+	//
+	// backend ingress-backend
+	//    http-request set-var(txn.dklb_auth_hash) http_auth_user,map("$SECRETS/<uid>__<secret>__auth")
+	//    http-request auth realm dklb unless { http_auth_pass,sha1,base64,strcmp(txn.dklb_auth_hash) eq 0 }
+	fileName := secretsreflector.ComputeDCOSSecretFileName(secretsreflector.ComputeDCOSBasicAuthSecretName(string(ingress.UID), *spec.SecretName))
+	return []string{
+		fmt.Sprintf(edgeLBBasicAuthSetHashFormatString, fileName),
+		fmt.Sprintf(edgeLBBasicAuthRequireFormatString, *spec.Realm),
+	}
+}
+
+// applyEdgeLBRateLimit applies the rate limiting configuration for the specified Ingress resource to the specified EdgeLB backends, which must be all the EdgeLB backends owned by the Ingress resource.
+// The request rate of each client is stored in a single table declared by the first EdgeLB backend (by name), and tracked by every EdgeLB backend, so that the limit applies to the Ingress resource as a whole.
+// Directives are set on the EdgeLB backends (which are owned by a single Ingress resource) rather than on the EdgeLB frontend (which may be shared by several Ingress resources).
+func applyEdgeLBRateLimit(backends []*models.V2Backend, spec *translatorapi.IngressEdgeLBPoolRateLimitSpec) {
+	if spec == nil || len(backends) == 0 {
+		return
+	}
+	table := backends[0]
+	for _, backend := range backends[1:] {
+		if backend.Name < table.Name {
+			table = backend
 		}
 	}
+	for _, backend := range backends {
+		backend.MiscStrs = append(backend.MiscStrs, computeEdgeLBRateLimitMiscStrs(spec, table.Name, backend == table)...)
+	}
+}
 
-	// dklb (probably) runs in a different kubernetes namespace than the ingress and the service
-	// so we need to add the namespace to the addr for dns to resolve properly.
-	// we only check if the service name contains a dot since the ingress can live
-	// in a different namespace than the service and in this case the operator
-	// will have to necessarly add a dot '.'
-	var addr string
-	if strings.Index(backend.ServiceName, ".") > 0 {
-		addr = fmt.Sprintf("%s:%d", backend.ServiceName, port)
-	} else {
-		addr = fmt.Sprintf("%s.%s:%d", backend.ServiceName, ingress.Namespace, port)
+// computeEdgeLBRateLimitMiscStrs computes the HAProxy directives that limit the rate at which a single client IP is allowed to send requests through a given EdgeLB backend.
+// Requests are tracked in the table declared by the EdgeLB backend with the specified name, and the table is declared by the current EdgeLB backend in case "declaresTable" is true.
+// It returns nil in case no rate limiting has been requested.
+func computeEdgeLBRateLimitMiscStrs(spec *translatorapi.IngressEdgeLBPoolRateLimitSpec, table string, declaresTable bool) []string {
+	if spec == nil {
+		return nil
 	}
+	// Requests are tracked by source IP, and denied once more than "requests + burst" requests have been sent during the current period.
+	// This will result in an HAProxy config similar to the following one:
+	//
+	// backend ingress-backend-1
+	//    stick-table type ipv6 size 100k expire 10000ms store http_req_rate(10000ms)
+	//    http-request track-sc0 src table ingress-backend-1
+	//    http-request deny deny_status 429 if { sc_http_req_rate(0) gt 120 }
+	//
+	// backend ingress-backend-2
+	//    http-request track-sc0 src table ingress-backend-1
+	//    http-request deny deny_status 429 if { sc_http_req_rate(0) gt 120 }
+	var res []string
+	if declaresTable {
+		period := toHAProxyDuration(*spec.Period)
+		res = append(res, fmt.Sprintf(edgeLBRateLimitStickTableFormatString, period, period))
+	}
+	return append(res,
+		fmt.Sprintf(edgeLBRateLimitTrackSourceFormatString, table),
+		fmt.Sprintf(edgeLBRateLimitDenyFormatString, *spec.Requests+*spec.Burst),
+	)
+}
 
-	conn, err := net.DialTimeout("tcp", addr, 3*time.Second)
-	if err == nil {
-		tlsConn := tls.Client(conn, &tls.Config{InsecureSkipVerify: true})
-		defer conn.Close()
-		err = tlsConn.Handshake()
-		if err == nil {
-			log.Printf("connected to %s\n", addr)
-			return strings.Join([]string{constants.EdgeLBBackendTLSCheck, constants.EdgeLBBackendInsecureSkipTLSVerify}, " ")
+// computeEdgeLBHeadersMiscStrs computes the HAProxy directives that change the HTTP headers of requests and responses going through a given EdgeLB backend.
+// It returns nil in case no changes have been requested.
+// Directives are set on the EdgeLB backend (which is owned by a single Ingress resource) rather than on the EdgeLB frontend (which may be shared by several Ingress resources).
+func computeEdgeLBHeadersMiscStrs(spec *translatorapi.IngressEdgeLBPoolHeadersSpec) []string {
+	if spec == nil {
+		return nil
+	}
+	// Headers are first removed, then set, and finally added, so that a header may be both removed and added back with a different value.
+	// This will result in an HAProxy config similar to the following one:
+	//
+	// backend ingress-backend
+	//    http-request del-header X-Request-ID
+	//    http-request set-header X-Request-ID "%[uuid()]"
+	//    http-response set-header X-Frame-Options "DENY"
+	res := make([]string, 0)
+	for _, actions := range []struct {
+		prefix string
+		spec   *translatorapi.IngressEdgeLBPoolHeaderActionsSpec
+	}{
+		{prefix: "http-request", spec: spec.Request},
+		{prefix: "http-response", spec: spec.Response},
+	} {
+		if actions.spec == nil {
+			continue
+		}
+		for _, name := range actions.spec.Remove {
+			res = append(res, fmt.Sprintf(edgeLBHeaderDirectiveFormatString, actions.prefix+" del-header", name))
+		}
+		for _, header := range actions.spec.Set {
+			res = append(res, fmt.Sprintf(edgeLBHeaderValueDirectiveFormatString, actions.prefix+" set-header", header.Name, escapeHAProxyHeaderValue(header.Value)))
+		}
+		for _, header := range actions.spec.Add {
+			res = append(res, fmt.Sprintf(edgeLBHeaderValueDirectiveFormatString, actions.prefix+" add-header", header.Name, escapeHAProxyHeaderValue(header.Value)))
 		}
-		log.Printf("error connecting to %s: %v", addr, err)
-	} else {
-		log.Printf("error dialing %s: %v", addr, err)
 	}
+	if len(res) == 0 {
+		return nil
+	}
+	return res
+}
 
-	// service does not have TLS enabled
-	return ""
+// escapeHAProxyHeaderValue escapes the provided header value so that it can be safely used between double quotes in an HAProxy directive.
+// "%" characters are deliberately left untouched, as HAProxy interprets header values as log-format strings (e.g. "%[uuid()]").
+func escapeHAProxyHeaderValue(value string) string {
+	return haproxyHeaderValueReplacer.Replace(value)
+}
+
+// applyEdgeLBFrontendClientAuthHeader configures the specified EdgeLB frontend to pass the subject of the certificates presented by clients to the backends, replacing any previous configuration.
+// The header used to pass the subject is removed from requests sent without a client certificate, so that it cannot be forged by clients.
+// A nil value causes any previous configuration to be removed.
+func applyEdgeLBFrontendClientAuthHeader(frontend *models.V2Frontend, spec *translatorapi.IngressEdgeLBPoolClientAuthSpec) {
+	var miscStrs []string
+	if spec != nil {
+		// This will result in an HAProxy config similar to the following one:
+		//
+		// frontend ingress-frontend
+		//    http-request del-header X-SSL-Client-Subject unless { ssl_c_used }
+		//    http-request set-header X-SSL-Client-Subject %{+Q}[ssl_c_s_dn] if { ssl_c_used }
+		miscStrs = []string{
+			fmt.Sprintf(edgeLBClientAuthDelSubjectHeaderFormatString, *spec.SubjectHeader),
+			fmt.Sprintf(edgeLBClientAuthSetSubjectHeaderFormatString, *spec.SubjectHeader),
+		}
+	}
+	replaceEdgeLBFrontendMiscStrs(frontend, func(m string) bool {
+		return strings.HasSuffix(m, edgeLBClientAuthCondition)
+	}, miscStrs)
+}
+
+// computeEdgeLBClientAuthBindModifier computes the options to append to the "bind" line of the HTTPS EdgeLB frontend in order for it to verify the certificates presented by clients.
+// It returns an empty string in case no client certificate authentication has been requested.
+func computeEdgeLBClientAuthBindModifier(ingress *networkingv1.Ingress, spec *translatorapi.IngressEdgeLBPoolClientAuthSpec) string {
+	if spec == nil {
+		return ""
+	}
+	fileName := secretsreflector.ComputeDCOSSecretFileName(secretsreflector.ComputeDCOSCASecretName(string(ingress.UID), *spec.CASecretName))
+	return fmt.Sprintf(edgeLBClientAuthBindModifierFormatString, fileName, *spec.Verify)
+}
+
+// applyEdgeLBFrontendHSTS sets the HSTS policy advertised by the specified EdgeLB frontend, replacing any previous policy.
+// A nil value causes any previous policy to be removed.
+func applyEdgeLBFrontendHSTS(frontend *models.V2Frontend, spec *translatorapi.IngressEdgeLBPoolHSTSSpec) {
+	var miscStrs []string
+	if spec != nil {
+		directives := []string{fmt.Sprintf(edgeLBHSTSMaxAgeFormatString, *spec.MaxAge)}
+		if spec.IncludeSubDomains != nil && *spec.IncludeSubDomains {
+			directives = append(directives, edgeLBHSTSIncludeSubDomains)
+		}
+		if spec.Preload != nil && *spec.Preload {
+			directives = append(directives, edgeLBHSTSPreload)
+		}
+		miscStrs = append(miscStrs, fmt.Sprintf("%s\"%s\"", edgeLBHSTSPrefix, strings.Join(directives, "; ")))
+	}
+	replaceEdgeLBFrontendMiscStrs(frontend, func(m string) bool {
+		return strings.HasPrefix(m, edgeLBHSTSPrefix)
+	}, miscStrs)
+}
+
+// applyEdgeLBFrontendMaxConn sets the maximum number of concurrent connections accepted by the specified EdgeLB frontend, replacing any previous value.
+// A nil value causes any previous value to be removed.
+func applyEdgeLBFrontendMaxConn(frontend *models.V2Frontend, maxConn *int32) {
+	var miscStrs []string
+	if maxConn != nil {
+		miscStrs = append(miscStrs, fmt.Sprintf(edgeLBMaxConnFormatString, *maxConn))
+	}
+	replaceEdgeLBFrontendMiscStrs(frontend, func(m string) bool {
+		return strings.HasPrefix(m, edgeLBMaxConnPrefix)
+	}, miscStrs)
+}
+
+// computeEdgeLBForcedCanaryMiscStrs computes the HAProxy directives that route requests carrying the header or cookie of a canary of the specified Ingress backend to the canary's EdgeLB backend.
+// The directives only match the host and path of the specified rule, or every request in case no rule is specified (i.e. when the Ingress backend is the default one).
+func computeEdgeLBForcedCanaryMiscStrs(ingress *networkingv1.Ingress, spec translatorapi.IngressEdgeLBPoolSpec, backend networkingv1.IngressServiceBackend, item *models.V2FrontendLinkBackendMapItems0) []string {
+	// This will result in an HAProxy config similar to the following one:
+	//
+	// frontend ingress-frontend
+	//    use_backend <canary-backend> if { req.hdr(X-Canary) -m str always } { req.hdr(host),field(1,:) -m str -i example.com } { path -m reg ^/foo(/.*)?$ }
+	var conditions []string
+	if item != nil {
+		switch {
+		case item.HostEq != "":
+			conditions = append(conditions, fmt.Sprintf(edgeLBForcedCanaryHostEqConditionFormatString, item.HostEq))
+		case item.HostReg != "" && item.HostReg != edgeLBHostCatchAllRegex:
+			conditions = append(conditions, fmt.Sprintf(edgeLBForcedCanaryHostRegConditionFormatString, item.HostReg))
+		}
+		if item.PathReg != "" && item.PathReg != edgeLBPathCatchAllRegex {
+			conditions = append(conditions, fmt.Sprintf(edgeLBForcedCanaryPathRegConditionFormatString, item.PathReg))
+		}
+	}
+	var res []string
+	for _, canary := range spec.BackendSpecFor(backend).Canaries {
+		name := computeEdgeLBBackendNameForIngressBackend(ingress, canary.IngressBackend())
+		if canary.Header != nil {
+			match := fmt.Sprintf(edgeLBForcedCanaryHeaderConditionFormatString, canary.Header.Name, canary.Header.Value)
+			res = append(res, fmt.Sprintf(edgeLBUseBackendFormatString, name, strings.Join(append([]string{match}, conditions...), " ")))
+		}
+		if canary.Cookie != nil {
+			match := fmt.Sprintf(edgeLBForcedCanaryCookieConditionFormatString, canary.Cookie.Name, canary.Cookie.Value)
+			res = append(res, fmt.Sprintf(edgeLBUseBackendFormatString, name, strings.Join(append([]string{match}, conditions...), " ")))
+		}
+	}
+	return res
+}
+
+// computeEdgeLBUseBackendMiscStrTarget returns the name of the EdgeLB backend targeted by the specified "use_backend" directive.
+// It returns an empty string in case the specified value is not a "use_backend" directive.
+func computeEdgeLBUseBackendMiscStrTarget(miscStr string) string {
+	if !strings.HasPrefix(miscStr, edgeLBUseBackendPrefix) {
+		return ""
+	}
+	return strings.SplitN(strings.TrimPrefix(miscStr, edgeLBUseBackendPrefix), " ", 2)[0]
+}
+
+// moveEdgeLBUseBackendMiscStrsFirst moves the "use_backend" directives of the specified EdgeLB frontend ahead of any other directive.
+// The relative order of the "use_backend" directives (as well as the one of the remaining directives) is preserved, so that Ingress resources sharing the EdgeLB frontend don't reorder each other's directives.
+func moveEdgeLBUseBackendMiscStrsFirst(frontend *models.V2Frontend) {
+	sort.SliceStable(frontend.MiscStrs, func(i, j int) bool {
+		return computeEdgeLBUseBackendMiscStrTarget(frontend.MiscStrs[i]) != "" && computeEdgeLBUseBackendMiscStrTarget(frontend.MiscStrs[j]) == ""
+	})
+}
+
+// isEdgeLBUseBackendMiscStrOwnedBy indicates whether the specified value is a "use_backend" directive targeting an EdgeLB backend owned by the specified Ingress resource.
+func isEdgeLBUseBackendMiscStrOwnedBy(miscStr string, ingress *networkingv1.Ingress) bool {
+	target := computeEdgeLBUseBackendMiscStrTarget(miscStr)
+	return target != "" && computeIngressOwnedEdgeLBObjectMetadata(target).IsOwnedBy(ingress)
+}
+
+// checkEdgeLBFrontendClientAuthForIngress returns an error in case the specified Ingress resource requires client certificates but the specified EdgeLB frontend is owned by another Ingress resource.
+// Such an EdgeLB frontend verifies client certificates (if at all) according to the configuration of its owner, so serving the Ingress resource through it could bypass client certificate authentication.
+func checkEdgeLBFrontendClientAuthForIngress(ingress *networkingv1.Ingress, spec translatorapi.IngressEdgeLBPoolSpec, frontend *models.V2Frontend) error {
+	if spec.Frontends.HTTPS == nil || spec.Frontends.HTTPS.ClientAuth == nil || frontend.Protocol != models.V2ProtocolHTTPS {
+		return nil
+	}
+	if computeIngressOwnedEdgeLBObjectMetadata(frontend.Name).IsOwnedBy(ingress) {
+		return nil
+	}
+	return fmt.Errorf("client certificates cannot be required as edgelb frontend %q is owned by another ingress", frontend.Name)
+}
+
+// computeIgnoredEdgeLBFrontendSettingsForIngress computes the frontend-level settings of the specified Ingress resource that are not applied to the specified EdgeLB frontend.
+// These are the settings specified for the Ingress resource when the EdgeLB frontend is shared with (and owned by) another Ingress resource, in which case the settings of the owner are kept.
+func computeIgnoredEdgeLBFrontendSettingsForIngress(ingress *networkingv1.Ingress, spec translatorapi.IngressEdgeLBPoolSpec, frontend *models.V2Frontend) []string {
+	if computeIngressOwnedEdgeLBObjectMetadata(frontend.Name).IsOwnedBy(ingress) {
+		return nil
+	}
+	var res []string
+	switch frontend.Protocol {
+	case models.V2ProtocolHTTP:
+		if spec.Frontends.HTTP != nil && spec.Frontends.HTTP.MaxConn != nil {
+			res = append(res, ".frontends.http.maxConn")
+		}
+	case models.V2ProtocolHTTPS:
+		if spec.Frontends.HTTPS != nil && spec.Frontends.HTTPS.MaxConn != nil {
+			res = append(res, ".frontends.https.maxConn")
+		}
+		if spec.Frontends.HTTPS != nil && spec.Frontends.HTTPS.HSTS != nil {
+			res = append(res, ".frontends.https.hsts")
+		}
+	}
+	if len(computeEdgeLBFrontendTimeoutMiscStrs(spec.Timeouts)) > 0 {
+		res = append(res, ".timeouts")
+	}
+	return res
+}
+
+// removeIngressAllowedSourcesFromEdgeLBFrontends removes the HAProxy directives restricting access to the specified Ingress resource from the specified EdgeLB frontends.
+// Such directives used to be set on the EdgeLB frontends themselves, where they also affected every other Ingress resource sharing them.
+func removeIngressAllowedSourcesFromEdgeLBFrontends(ingress *networkingv1.Ingress, frontends []*models.V2Frontend) {
+	aclName := fmt.Sprintf(ingressAllowedSourcesACLNameFormatString, ingress.UID)
+	for _, frontend := range frontends {
+		replaceEdgeLBFrontendMiscStrs(frontend, func(m string) bool {
+			return strings.Contains(m, aclName)
+		}, nil)
+	}
 }
 
 // computeServiceOwnedEdgeLBObjectMetadata parses the provided EdgeLB backend/frontend name and returns metadata about the Ingress resource that owns it.
@@ -350,15 +921,13 @@ func computeIngressOwnedEdgeLBObjectMetadata(name string) *ingressOwnedEdgeLBObj
 	case 5:
 		// The provided name is composed of 5 parts separated by "separator".
 		// Hence, it most likely corresponds to an EdgeLB backend owned by an Ingress resource.
+		// Reconstruct the Ingress backend so we can compare it with the computed (desired) state later on.
+		backend := kubernetesutil.NewIngressServiceBackend(parts[3], parts[4])
 		return &ingressOwnedEdgeLBObjectMetadata{
-			ClusterName: dklbstrings.ReplaceDotsWithForwardSlashes(parts[0]),
-			Namespace:   parts[1],
-			Name:        parts[2],
-			// Reconstruct the Ingress backend so we can compare it with the computed (desired) state later on.
-			IngressBackend: &extsv1beta1.IngressBackend{
-				ServiceName: parts[3],
-				ServicePort: intstr.Parse(parts[4]),
-			},
+			ClusterName:    dklbstrings.ReplaceDotsWithForwardSlashes(parts[0]),
+			Namespace:      parts[1],
+			Name:           parts[2],
+			IngressBackend: &backend,
 		}
 	case 4:
 		// The provided name is composed of 3 parts separated by "separator".
@@ -378,24 +947,36 @@ func computeIngressOwnedEdgeLBObjectMetadata(name string) *ingressOwnedEdgeLBObj
 }
 
 // computeEdgeLBSecretsForIngress generates the list of DC/OS secrets
-// required for the given ingress. Returns nil if TLS is disabled.
+// required for the given ingress. Returns nil if neither TLS, HTTPS
+// backends nor basic authentication are in use.
 // edgelb models.V2PoolSecretsItems0
 // https://github.com/mesosphere/dcos-edge-lb/blob/master/pkg/apis/models/v2_pool.go#L346-L356
 // we use the dcos secret name with forward slashes replaces by dots
 // as the filename for the edgelb V2PoolSecretsItems0.File parameter
-func computeEdgeLBSecretsForIngress(ingress *extsv1beta1.Ingress) []*models.V2PoolSecretsItems0 {
-	if !translatorapi.IsIngressTLSEnabled(ingress) {
+func computeEdgeLBSecretsForIngress(ingress *networkingv1.Ingress, spec translatorapi.IngressEdgeLBPoolSpec) []*models.V2PoolSecretsItems0 {
+	dcosSecretNames := make([]string, 0)
+	if translatorapi.IsIngressTLSEnabled(ingress) {
+		for _, ingressTLS := range ingress.Spec.TLS {
+			dcosSecretNames = append(dcosSecretNames, secretsreflector.ComputeDCOSSecretName(string(ingress.UID), ingressTLS.SecretName))
+		}
+	}
+	// Add the CA bundles used to verify HTTPS backends.
+	for _, caSecretName := range spec.CASecretNames(ingress) {
+		dcosSecretNames = append(dcosSecretNames, secretsreflector.ComputeDCOSCASecretName(string(ingress.UID), caSecretName))
+	}
+	// Add the credentials used to authenticate clients.
+	for _, basicAuthSecretName := range spec.BasicAuthSecretNames() {
+		dcosSecretNames = append(dcosSecretNames, secretsreflector.ComputeDCOSBasicAuthSecretName(string(ingress.UID), basicAuthSecretName))
+	}
+	if len(dcosSecretNames) == 0 {
 		return nil
 	}
 
-	secrets := make([]*models.V2PoolSecretsItems0, 0)
-
-	for _, ingressTLS := range ingress.Spec.TLS {
-		dcosSecretName := fmt.Sprintf("%s__%s", ingress.UID, ingressTLS.SecretName)
-		filename := secretsreflector.ComputeDCOSSecretFileName(dcosSecretName)
+	secrets := make([]*models.V2PoolSecretsItems0, 0, len(dcosSecretNames))
+	for _, dcosSecretName := range dcosSecretNames {
 		secret := &models.V2PoolSecretsItems0{
 			Secret: dcosSecretName,
-			File:   filename,
+			File:   secretsreflector.ComputeDCOSSecretFileName(dcosSecretName),
 		}
 		secrets = append(secrets, secret)
 	}
@@ -0,0 +1,964 @@
+package translator
+
+import (
+	"testing"
+
+	"github.com/mesosphere/dcos-edge-lb/pkg/apis/models"
+	"github.com/stretchr/testify/assert"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/mesosphere/dklb/pkg/cluster"
+	translatorapi "github.com/mesosphere/dklb/pkg/translator/api"
+	"github.com/mesosphere/dklb/pkg/util/pointers"
+)
+
+func TestComputeEdgeLBPathRegex(t *testing.T) {
+	tests := []struct {
+		description string
+		path        string
+		pathType    string
+		expected    string
+	}{
+		{
+			description: "should match an exact path literally",
+			path:        "/foo.html",
+			pathType:    translatorapi.IngressPathTypeExact,
+			expected:    `^/foo\.html$`,
+		},
+		{
+			description: "should match a prefix on path-segment boundaries",
+			path:        "/foo/bar",
+			pathType:    translatorapi.IngressPathTypePrefix,
+			expected:    `^/foo/bar(/.*)?$`,
+		},
+		{
+			description: "should ignore trailing slashes on a prefix",
+			path:        "/foo/",
+			pathType:    translatorapi.IngressPathTypePrefix,
+			expected:    `^/foo(/.*)?$`,
+		},
+		{
+			description: "should match all paths for the root prefix",
+			path:        "/",
+			pathType:    translatorapi.IngressPathTypePrefix,
+			expected:    `^/.*$`,
+		},
+		{
+			description: "should keep an implementation-specific path as a regular expression",
+			path:        "/foo/.*",
+			pathType:    translatorapi.IngressPathTypeImplementationSpecific,
+			expected:    `^/foo/.*$`,
+		},
+		{
+			description: "should escape backslashes inside bracket expressions",
+			path:        `/foo/[a-z\]+`,
+			pathType:    translatorapi.IngressPathTypeImplementationSpecific,
+			expected:    `^/foo/[a-z\\]+$`,
+		},
+		{
+			description: "should escape a leading closing bracket inside bracket expressions",
+			path:        "/foo/[]a]",
+			pathType:    translatorapi.IngressPathTypeImplementationSpecific,
+			expected:    `^/foo/[\]a]$`,
+		},
+		{
+			description: "should keep character classes inside bracket expressions",
+			path:        "/foo/[[:digit:]]+",
+			pathType:    translatorapi.IngressPathTypeImplementationSpecific,
+			expected:    `^/foo/[[:digit:]]+$`,
+		},
+		{
+			description: "should replace collating symbols inside bracket expressions",
+			path:        "/foo/[a[.-.]z]",
+			pathType:    translatorapi.IngressPathTypeImplementationSpecific,
+			expected:    `^/foo/[a\-z]$`,
+		},
+		{
+			description: "should escape characters with a special meaning to haproxy",
+			path:        "/foo bar#baz",
+			pathType:    translatorapi.IngressPathTypeExact,
+			expected:    `^/foo\x20bar\x23baz$`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+		assert.Equal(t, test.expected, computeEdgeLBPathRegex(test.path, test.pathType))
+	}
+}
+
+func TestComputeEdgeLBFrontendForIngress_rulePriority(t *testing.T) {
+	cluster.Name = "test-cluster"
+
+	newIngress := func(host string, paths ...string) *networkingv1.Ingress {
+		ingress := &networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "test-namespace",
+				Name:      "test-ingress",
+			},
+			Spec: networkingv1.IngressSpec{
+				Rules: []networkingv1.IngressRule{
+					{
+						Host: host,
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{},
+						},
+					},
+				},
+			},
+		}
+		for _, path := range paths {
+			ingress.Spec.Rules[0].HTTP.Paths = append(ingress.Spec.Rules[0].HTTP.Paths, networkingv1.HTTPIngressPath{
+				Path: path,
+				Backend: networkingv1.IngressBackend{
+					Service: &networkingv1.IngressServiceBackend{
+						Name: "test-service",
+						Port: networkingv1.ServiceBackendPort{Number: 80},
+					},
+				},
+			})
+		}
+		return ingress
+	}
+	newSpec := func(pathType string) translatorapi.IngressEdgeLBPoolSpec {
+		return translatorapi.IngressEdgeLBPoolSpec{
+			Frontends: &translatorapi.IngressEdgeLBPoolFrontendsSpec{
+				HTTP: &translatorapi.IngressEdgeLBPoolHTTPFrontendSpec{
+					Mode: pointers.NewString(translatorapi.IngressEdgeLBHTTPModeEnabled),
+					Port: pointers.NewInt32(80),
+				},
+			},
+			PathType: pointers.NewString(pathType),
+		}
+	}
+
+	tests := []struct {
+		description string
+		ingress     *networkingv1.Ingress
+		spec        translatorapi.IngressEdgeLBPoolSpec
+		expected    []string
+	}{
+		{
+			description: "should rank longer prefixes first",
+			ingress:     newIngress("example.com", "/", "/foo/bar", "/foo"),
+			spec:        newSpec(translatorapi.IngressPathTypePrefix),
+			expected:    []string{`^/foo/bar(/.*)?$`, `^/foo(/.*)?$`, `^/.*$`},
+		},
+		{
+			description: "should rank longer exact paths first",
+			ingress:     newIngress("", "/a", "/abc"),
+			spec:        newSpec(translatorapi.IngressPathTypeExact),
+			expected:    []string{`^/abc$`, `^/a$`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+		frontends := computeEdgeLBFrontendForIngress(test.ingress, test.spec, nil)
+		assert.Len(t, frontends, 1)
+		var actual []string
+		for _, item := range frontends[0].LinkBackend.Map {
+			actual = append(actual, item.PathReg)
+		}
+		assert.Equal(t, test.expected, actual)
+	}
+}
+
+func TestPrioritizedMatchingRule_precedes(t *testing.T) {
+	exact := prioritizedMatchingRule{item: &models.V2FrontendLinkBackendMapItems0{}, exact: true, pathPriority: 2}
+	prefix := prioritizedMatchingRule{item: &models.V2FrontendLinkBackendMapItems0{}, pathPriority: 8}
+	wildcard := prioritizedMatchingRule{item: &models.V2FrontendLinkBackendMapItems0{}, hostPriority: -1, exact: true, pathPriority: 8}
+
+	assert.True(t, exact.precedes(prefix))
+	assert.False(t, prefix.precedes(exact))
+	assert.True(t, prefix.precedes(wildcard))
+	assert.True(t, exact.precedes(wildcard))
+}
+
+func TestComputeEdgeLBFrontendForIngress_wildcardHost(t *testing.T) {
+	cluster.Name = "test-cluster"
+
+	backend := networkingv1.IngressServiceBackend{
+		Name: "test-service",
+		Port: networkingv1.ServiceBackendPort{Number: 80},
+	}
+	newRule := func(host string) networkingv1.IngressRule {
+		return networkingv1.IngressRule{
+			Host: host,
+			IngressRuleValue: networkingv1.IngressRuleValue{
+				HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{
+						{Backend: networkingv1.IngressBackend{Service: &backend}},
+					},
+				},
+			},
+		}
+	}
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-namespace",
+			Name:      "test-ingress",
+		},
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{
+				newRule(""),
+				newRule("*.example.com"),
+				newRule("foo.example.com"),
+			},
+		},
+	}
+	spec := translatorapi.IngressEdgeLBPoolSpec{
+		Frontends: &translatorapi.IngressEdgeLBPoolFrontendsSpec{
+			HTTP: &translatorapi.IngressEdgeLBPoolHTTPFrontendSpec{
+				Mode: pointers.NewString(translatorapi.IngressEdgeLBHTTPModeEnabled),
+				Port: pointers.NewInt32(80),
+			},
+		},
+		PathType: pointers.NewString(translatorapi.IngressPathTypeImplementationSpecific),
+	}
+
+	frontends := computeEdgeLBFrontendForIngress(ingress, spec, nil)
+	assert.Len(t, frontends, 1)
+	backendName := "test-cluster:test-namespace:test-ingress:test-service:80"
+	assert.Equal(t, []*models.V2FrontendLinkBackendMapItems0{
+		{Backend: backendName, HostEq: "foo.example.com", PathReg: edgeLBPathCatchAllRegex},
+		{Backend: backendName, HostReg: `^[^.]+\.example\.com$`, PathReg: edgeLBPathCatchAllRegex},
+		{Backend: backendName, HostReg: edgeLBHostCatchAllRegex, PathReg: edgeLBPathCatchAllRegex},
+	}, frontends[0].LinkBackend.Map)
+}
+
+func TestComputeEdgeLBForIngress_allowedSourceRanges(t *testing.T) {
+	cluster.Name = "test-cluster"
+
+	backend := networkingv1.IngressServiceBackend{
+		Name: "test-service",
+		Port: networkingv1.ServiceBackendPort{Number: 80},
+	}
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-namespace",
+			Name:      "test-ingress",
+			UID:       "uid",
+		},
+		Spec: networkingv1.IngressSpec{
+			DefaultBackend: &networkingv1.IngressBackend{Service: &backend},
+		},
+	}
+	spec := translatorapi.IngressEdgeLBPoolSpec{
+		AllowedSourceRanges: []string{"10.0.0.0/8"},
+		Frontends: &translatorapi.IngressEdgeLBPoolFrontendsSpec{
+			HTTP: &translatorapi.IngressEdgeLBPoolHTTPFrontendSpec{
+				Mode: pointers.NewString(translatorapi.IngressEdgeLBHTTPModeEnabled),
+				Port: pointers.NewInt32(80),
+			},
+		},
+		PathType: pointers.NewString(translatorapi.IngressPathTypeImplementationSpecific),
+	}
+
+	// Access must be restricted on the EdgeLB backend of the Ingress resource, which is only reached through its hosts and paths.
+	res := computeEdgeLBBackendForIngressBackend(ingress, spec, backend, IngressBackendNodePortMap{backend: 30080}, nil, nil)
+	assert.Equal(t, []string{
+		"acl dklb_uid_allowed_sources src 10.0.0.0/8",
+		"http-request deny if !dklb_uid_allowed_sources",
+	}, res.MiscStrs)
+
+	// Simulate a frontend shared with another Ingress resource, containing outdated directives for the current one.
+	pool := &models.V2Pool{
+		Haproxy: &models.V2Haproxy{
+			Frontends: []*models.V2Frontend{
+				{
+					BindPort:    pointers.NewInt32(80),
+					LinkBackend: &models.V2FrontendLinkBackend{},
+					MiscStrs: []string{
+						"acl dklb_other_allowed_sources src 192.168.0.0/16",
+						"tcp-request connection reject if !dklb_other_allowed_sources",
+						"acl dklb_uid_allowed_sources src 172.16.0.0/12",
+						"tcp-request connection reject if !dklb_uid_allowed_sources",
+					},
+				},
+			},
+		},
+	}
+
+	// Directives restricting access to the current Ingress resource must be removed from the frontend, while the ones created for other Ingress resources must be left untouched.
+	frontends := computeEdgeLBFrontendForIngress(ingress, spec, pool)
+	assert.Len(t, frontends, 1)
+	assert.Equal(t, []string{
+		"acl dklb_other_allowed_sources src 192.168.0.0/16",
+		"tcp-request connection reject if !dklb_other_allowed_sources",
+	}, frontends[0].MiscStrs)
+}
+
+func TestComputeEdgeLBBackendMiscStr(t *testing.T) {
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-namespace",
+			Name:      "test-ingress",
+			UID:       "uid",
+		},
+	}
+	tests := []struct {
+		description string
+		backendSpec *translatorapi.IngressEdgeLBPoolBackendSpec
+		expected    string
+	}{
+		{
+			description: "should not add options for http backends",
+			backendSpec: &translatorapi.IngressEdgeLBPoolBackendSpec{
+				BackendProtocol: pointers.NewString(translatorapi.IngressBackendProtocolHTTP),
+			},
+			expected: "",
+		},
+		{
+			description: "should verify the certificate of https backends",
+			backendSpec: &translatorapi.IngressEdgeLBPoolBackendSpec{
+				BackendProtocol: pointers.NewString(translatorapi.IngressBackendProtocolHTTPS),
+				CASecretName:    pointers.NewString("test-ca"),
+			},
+			expected: `check-ssl ssl verify required ca-file "$SECRETS/uid__test-ca__ca"`,
+		},
+		{
+			description: "should talk http/2 to h2c backends",
+			backendSpec: &translatorapi.IngressEdgeLBPoolBackendSpec{
+				BackendProtocol: pointers.NewString(translatorapi.IngressBackendProtocolH2C),
+			},
+			expected: "proto h2",
+		},
+		{
+			description: "should limit the number of connections to each server",
+			backendSpec: &translatorapi.IngressEdgeLBPoolBackendSpec{
+				BackendProtocol: pointers.NewString(translatorapi.IngressBackendProtocolH2C),
+				MaxConn:         pointers.NewInt32(50),
+			},
+			expected: "proto h2 maxconn 50",
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+		assert.Equal(t, test.expected, computeEdgeLBBackendMiscStr(ingress, test.backendSpec))
+	}
+}
+
+func TestComputeEdgeLBRewriteHTTPForIngressBackend(t *testing.T) {
+	backend := networkingv1.IngressServiceBackend{
+		Name: "test-service",
+		Port: networkingv1.ServiceBackendPort{Number: 80},
+	}
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-namespace",
+			Name:      "test-ingress",
+		},
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{
+				{
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{Path: "/app/", Backend: networkingv1.IngressBackend{Service: &backend}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		description string
+		rewrite     *translatorapi.IngressEdgeLBPoolRewriteSpec
+		expected    *models.V2RewriteHTTP
+	}{
+		{
+			description: "should only add forwarding headers by default",
+			rewrite:     nil,
+			expected: &models.V2RewriteHTTP{
+				Request: &models.V2RewriteHTTPRequest{
+					Forwardfor:                pointers.NewBool(true),
+					XForwardedPort:            pointers.NewBool(true),
+					XForwardedProtoHTTPSIfTLS: pointers.NewBool(true),
+					RewritePath:               pointers.NewBool(false),
+					SetHostHeader:             pointers.NewBool(false),
+				},
+				Response: &models.V2RewriteHTTPResponse{
+					RewriteLocation: pointers.NewBool(false),
+				},
+			},
+		},
+		{
+			description: "should rewrite the path, host and location when requested",
+			rewrite: &translatorapi.IngressEdgeLBPoolRewriteSpec{
+				ForwardedFor:    pointers.NewBool(false),
+				RewriteTarget:   pointers.NewString("/"),
+				RewriteLocation: pointers.NewBool(true),
+				UpstreamHost:    pointers.NewString("app.internal"),
+			},
+			expected: &models.V2RewriteHTTP{
+				Host: "app.internal",
+				Path: &models.V2RewriteHTTPPath{
+					FromPath: "/app",
+					ToPath:   "/",
+				},
+				Request: &models.V2RewriteHTTPRequest{
+					Forwardfor:                pointers.NewBool(false),
+					XForwardedPort:            pointers.NewBool(true),
+					XForwardedProtoHTTPSIfTLS: pointers.NewBool(true),
+					RewritePath:               pointers.NewBool(true),
+					SetHostHeader:             pointers.NewBool(true),
+				},
+				Response: &models.V2RewriteHTTPResponse{
+					RewriteLocation: pointers.NewBool(true),
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+		spec := translatorapi.IngressEdgeLBPoolSpec{
+			Rewrite: test.rewrite,
+		}
+		assert.Equal(t, test.expected, computeEdgeLBRewriteHTTPForIngressBackend(ingress, spec, backend))
+	}
+}
+
+func TestComputeEdgeLBStickyForIngressBackend(t *testing.T) {
+	backend := networkingv1.IngressServiceBackend{
+		Name: "test-service",
+		Port: networkingv1.ServiceBackendPort{Number: 80},
+	}
+	tests := []struct {
+		description string
+		affinity    *translatorapi.IngressEdgeLBPoolAffinitySpec
+		expected    *models.V2RewriteHTTPSticky
+	}{
+		{
+			description: "should not enable session affinity by default",
+			affinity:    nil,
+			expected:    nil,
+		},
+		{
+			description: "should not enable session affinity when explicitly disabled",
+			affinity: &translatorapi.IngressEdgeLBPoolAffinitySpec{
+				Mode: pointers.NewString(translatorapi.IngressAffinityModeNone),
+			},
+			expected: nil,
+		},
+		{
+			description: "should insert a cookie with the default name",
+			affinity:    &translatorapi.IngressEdgeLBPoolAffinitySpec{},
+			expected: &models.V2RewriteHTTPSticky{
+				Enabled:   pointers.NewBool(true),
+				CustomStr: "DKLB_AFFINITY insert indirect nocache",
+			},
+		},
+		{
+			description: "should insert a cookie with a maximum lifetime",
+			affinity: &translatorapi.IngressEdgeLBPoolAffinitySpec{
+				CookieName: pointers.NewString("SERVERID"),
+				TTL:        pointers.NewString("1h"),
+			},
+			expected: &models.V2RewriteHTTPSticky{
+				Enabled:   pointers.NewBool(true),
+				CustomStr: "SERVERID insert indirect nocache maxlife 3600000ms",
+			},
+		},
+		{
+			description: "should prefix a cookie set by the application",
+			affinity: &translatorapi.IngressEdgeLBPoolAffinitySpec{
+				CookieName: pointers.NewString("JSESSIONID"),
+				Mode:       pointers.NewString(translatorapi.IngressAffinityModePrefix),
+			},
+			expected: &models.V2RewriteHTTPSticky{
+				Enabled:   pointers.NewBool(true),
+				CustomStr: "JSESSIONID prefix nocache",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+		spec := translatorapi.IngressEdgeLBPoolSpec{
+			Affinity: test.affinity,
+		}
+		assert.Equal(t, test.expected, computeEdgeLBStickyForIngressBackend(spec, backend))
+	}
+}
+
+func TestComputeEdgeLBBasicAuthMiscStrs(t *testing.T) {
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-namespace",
+			Name:      "test-ingress",
+			UID:       "uid",
+		},
+	}
+
+	assert.Nil(t, computeEdgeLBBasicAuthMiscStrs(ingress, nil))
+	assert.Equal(t, []string{
+		`http-request set-var(txn.dklb_auth_hash) http_auth_user,map("$SECRETS/uid__test-auth__auth")`,
+		"http-request auth realm dashboards unless { http_auth_pass,sha1,base64,strcmp(txn.dklb_auth_hash) eq 0 }",
+	}, computeEdgeLBBasicAuthMiscStrs(ingress, &translatorapi.IngressEdgeLBPoolBasicAuthSpec{
+		Realm:      pointers.NewString("dashboards"),
+		SecretName: pointers.NewString("test-auth"),
+	}))
+}
+
+func TestApplyEdgeLBRateLimit(t *testing.T) {
+	spec := &translatorapi.IngressEdgeLBPoolRateLimitSpec{
+		Burst:    pointers.NewInt32(20),
+		Period:   pointers.NewString("10s"),
+		Requests: pointers.NewInt32(100),
+	}
+	backends := []*models.V2Backend{
+		{Name: "test-cluster:test-namespace:test-ingress:web:80"},
+		{Name: "test-cluster:test-namespace:test-ingress:api:80"},
+	}
+
+	// No directives must be added when no rate limiting has been requested.
+	applyEdgeLBRateLimit(backends, nil)
+	assert.Nil(t, backends[0].MiscStrs)
+	assert.Nil(t, backends[1].MiscStrs)
+
+	// Every EdgeLB backend must track requests in the single table declared by the first EdgeLB backend (by name).
+	applyEdgeLBRateLimit(backends, spec)
+	assert.Equal(t, []string{
+		"http-request track-sc0 src table test-cluster:test-namespace:test-ingress:api:80",
+		"http-request deny deny_status 429 if { sc_http_req_rate(0) gt 120 }",
+	}, backends[0].MiscStrs)
+	assert.Equal(t, []string{
+		"stick-table type ipv6 size 100k expire 10000ms store http_req_rate(10000ms)",
+		"http-request track-sc0 src table test-cluster:test-namespace:test-ingress:api:80",
+		"http-request deny deny_status 429 if { sc_http_req_rate(0) gt 120 }",
+	}, backends[1].MiscStrs)
+}
+
+func TestComputeEdgeLBFrontendForIngress_maxConn(t *testing.T) {
+	cluster.Name = "test-cluster"
+
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-namespace",
+			Name:      "test-ingress",
+		},
+	}
+	spec := translatorapi.IngressEdgeLBPoolSpec{
+		Frontends: &translatorapi.IngressEdgeLBPoolFrontendsSpec{
+			HTTP: &translatorapi.IngressEdgeLBPoolHTTPFrontendSpec{
+				MaxConn: pointers.NewInt32(1000),
+				Mode:    pointers.NewString(translatorapi.IngressEdgeLBHTTPModeEnabled),
+				Port:    pointers.NewInt32(80),
+			},
+		},
+		PathType: pointers.NewString(translatorapi.IngressPathTypeImplementationSpecific),
+	}
+	newPool := func(frontendName string) *models.V2Pool {
+		return &models.V2Pool{
+			Haproxy: &models.V2Haproxy{
+				Frontends: []*models.V2Frontend{
+					{
+						BindPort:    pointers.NewInt32(80),
+						LinkBackend: &models.V2FrontendLinkBackend{},
+						MiscStrs:    []string{"maxconn 10"},
+						Name:        frontendName,
+						Protocol:    models.V2ProtocolHTTP,
+					},
+				},
+			},
+		}
+	}
+
+	// The maximum number of connections must be replaced on frontends owned by the current Ingress resource.
+	frontends := computeEdgeLBFrontendForIngress(ingress, spec, newPool("test-cluster:test-namespace:test-ingress:http"))
+	assert.Equal(t, []string{"maxconn 1000"}, frontends[0].MiscStrs)
+	assert.Nil(t, computeIgnoredEdgeLBFrontendSettingsForIngress(ingress, spec, frontends[0]))
+	// The maximum number of connections must be left untouched on frontends owned by other Ingress resources, and reported as ignored.
+	frontends = computeEdgeLBFrontendForIngress(ingress, spec, newPool("test-cluster:test-namespace:other-ingress:http"))
+	assert.Equal(t, []string{"maxconn 10"}, frontends[0].MiscStrs)
+	assert.Equal(t, []string{".frontends.http.maxConn"}, computeIgnoredEdgeLBFrontendSettingsForIngress(ingress, spec, frontends[0]))
+}
+
+func TestComputeEdgeLBHeadersMiscStrs(t *testing.T) {
+	assert.Nil(t, computeEdgeLBHeadersMiscStrs(nil))
+	assert.Nil(t, computeEdgeLBHeadersMiscStrs(&translatorapi.IngressEdgeLBPoolHeadersSpec{}))
+	assert.Equal(t, []string{
+		"http-request del-header X-Request-ID",
+		`http-request set-header X-Request-ID "%[uuid()]"`,
+		"http-response del-header Server",
+		`http-response set-header Content-Security-Policy "default-src 'self'; img-src \"data:\\\""`,
+		`http-response add-header Link "</style.css>; rel=preload"`,
+	}, computeEdgeLBHeadersMiscStrs(&translatorapi.IngressEdgeLBPoolHeadersSpec{
+		Request: &translatorapi.IngressEdgeLBPoolHeaderActionsSpec{
+			Remove: []string{"X-Request-ID"},
+			Set: []translatorapi.IngressEdgeLBPoolHeaderSpec{
+				{Name: "X-Request-ID", Value: "%[uuid()]"},
+			},
+		},
+		Response: &translatorapi.IngressEdgeLBPoolHeaderActionsSpec{
+			Add: []translatorapi.IngressEdgeLBPoolHeaderSpec{
+				{Name: "Link", Value: "</style.css>; rel=preload"},
+			},
+			Remove: []string{"Server"},
+			Set: []translatorapi.IngressEdgeLBPoolHeaderSpec{
+				{Name: "Content-Security-Policy", Value: `default-src 'self'; img-src "data:\"`},
+			},
+		},
+	}))
+}
+
+func TestComputeEdgeLBFrontendForIngress_hsts(t *testing.T) {
+	cluster.Name = "test-cluster"
+
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-namespace",
+			Name:      "test-ingress",
+		},
+	}
+	spec := translatorapi.IngressEdgeLBPoolSpec{
+		Frontends: &translatorapi.IngressEdgeLBPoolFrontendsSpec{
+			HTTPS: &translatorapi.IngressEdgeLBPoolHTTPSFrontendSpec{
+				HSTS: &translatorapi.IngressEdgeLBPoolHSTSSpec{
+					IncludeSubDomains: pointers.NewBool(true),
+					MaxAge:            pointers.NewInt32(63072000),
+					Preload:           pointers.NewBool(true),
+				},
+				Port: pointers.NewInt32(443),
+			},
+		},
+		PathType: pointers.NewString(translatorapi.IngressPathTypeImplementationSpecific),
+	}
+	newPool := func(frontendName string) *models.V2Pool {
+		return &models.V2Pool{
+			Haproxy: &models.V2Haproxy{
+				Frontends: []*models.V2Frontend{
+					{
+						BindPort:    pointers.NewInt32(443),
+						LinkBackend: &models.V2FrontendLinkBackend{},
+						MiscStrs:    []string{`http-response set-header Strict-Transport-Security "max-age=300"`},
+						Name:        frontendName,
+					},
+				},
+			},
+		}
+	}
+
+	// The HSTS policy must be replaced on frontends owned by the current Ingress resource.
+	frontends := computeEdgeLBFrontendForIngress(ingress, spec, newPool("test-cluster:test-namespace:test-ingress:https"))
+	assert.Equal(t, []string{`http-response set-header Strict-Transport-Security "max-age=63072000; includeSubDomains; preload"`}, frontends[0].MiscStrs)
+	// The HSTS policy must be left untouched on frontends owned by other Ingress resources.
+	frontends = computeEdgeLBFrontendForIngress(ingress, spec, newPool("test-cluster:test-namespace:other-ingress:https"))
+	assert.Equal(t, []string{`http-response set-header Strict-Transport-Security "max-age=300"`}, frontends[0].MiscStrs)
+	// The HSTS policy must be removed from frontends owned by the current Ingress resource when it is not requested anymore.
+	spec.Frontends.HTTPS.HSTS = nil
+	frontends = computeEdgeLBFrontendForIngress(ingress, spec, newPool("test-cluster:test-namespace:test-ingress:https"))
+	assert.Nil(t, frontends[0].MiscStrs)
+}
+
+func TestComputeEdgeLBFrontendForIngress_clientAuth(t *testing.T) {
+	cluster.Name = "test-cluster"
+
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-namespace",
+			Name:      "test-ingress",
+			UID:       "test-uid",
+		},
+	}
+	spec := translatorapi.IngressEdgeLBPoolSpec{
+		Frontends: &translatorapi.IngressEdgeLBPoolFrontendsSpec{
+			HTTP: &translatorapi.IngressEdgeLBPoolHTTPFrontendSpec{
+				Mode: pointers.NewString(translatorapi.IngressEdgeLBHTTPModeRedirect),
+				Port: pointers.NewInt32(80),
+			},
+			HTTPS: &translatorapi.IngressEdgeLBPoolHTTPSFrontendSpec{
+				ClientAuth: &translatorapi.IngressEdgeLBPoolClientAuthSpec{
+					CASecretName:  pointers.NewString("client-ca"),
+					SubjectHeader: pointers.NewString("X-Client-DN"),
+					Verify:        pointers.NewString(translatorapi.IngressClientAuthVerifyOptional),
+				},
+				Port: pointers.NewInt32(443),
+			},
+		},
+		PathType: pointers.NewString(translatorapi.IngressPathTypeImplementationSpecific),
+	}
+	expectedMiscStrs := []string{
+		"http-request del-header X-Client-DN unless { ssl_c_used }",
+		"http-request set-header X-Client-DN %{+Q}[ssl_c_s_dn] if { ssl_c_used }",
+	}
+
+	// Client certificates must be verified by the HTTPS frontend, and the subject header must be sanitized on both frontends.
+	frontends := computeEdgeLBFrontendForIngress(ingress, spec, &models.V2Pool{Haproxy: &models.V2Haproxy{}})
+	assert.Len(t, frontends, 2)
+	assert.Equal(t, expectedMiscStrs, frontends[0].MiscStrs)
+	assert.Equal(t, "", frontends[0].BindModifier)
+	assert.Equal(t, expectedMiscStrs, frontends[1].MiscStrs)
+	assert.Equal(t, `ca-file "$SECRETS/test-uid__client-ca__ca" verify optional`, frontends[1].BindModifier)
+	assert.NoError(t, checkEdgeLBFrontendClientAuthForIngress(ingress, spec, frontends[1]))
+	// Client certificates must not be required through an HTTPS frontend owned by another Ingress resource.
+	shared := &models.V2Frontend{
+		BindPort:    pointers.NewInt32(443),
+		LinkBackend: &models.V2FrontendLinkBackend{},
+		Name:        "test-cluster:test-namespace:other-ingress:https",
+		Protocol:    models.V2ProtocolHTTPS,
+	}
+	sharedFrontends := computeEdgeLBFrontendForIngress(ingress, spec, &models.V2Pool{Haproxy: &models.V2Haproxy{Frontends: []*models.V2Frontend{shared}}})
+	assert.Equal(t, "", sharedFrontends[1].BindModifier)
+	assert.Error(t, checkEdgeLBFrontendClientAuthForIngress(ingress, spec, sharedFrontends[1]))
+	// The configuration must be removed from the frontends when client certificate authentication is not requested anymore.
+	pool := &models.V2Pool{Haproxy: &models.V2Haproxy{Frontends: frontends}}
+	spec.Frontends.HTTPS.ClientAuth = nil
+	frontends = computeEdgeLBFrontendForIngress(ingress, spec, pool)
+	assert.Nil(t, frontends[0].MiscStrs)
+	assert.Nil(t, frontends[1].MiscStrs)
+	assert.Equal(t, "", frontends[1].BindModifier)
+}
+
+func TestComputeEdgeLBForIngress_canaries(t *testing.T) {
+	cluster.Name = "test-cluster"
+
+	primary := networkingv1.IngressServiceBackend{
+		Name: "web",
+		Port: networkingv1.ServiceBackendPort{Number: 80},
+	}
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-namespace",
+			Name:      "test-ingress",
+		},
+		Spec: networkingv1.IngressSpec{
+			DefaultBackend: &networkingv1.IngressBackend{Service: &primary},
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: "example.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{Path: "/app", Backend: networkingv1.IngressBackend{Service: &primary}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	spec := translatorapi.IngressEdgeLBPoolSpec{
+		Backends: []*translatorapi.IngressEdgeLBPoolBackendSpec{
+			{
+				ServiceName:     "web",
+				ServicePort:     "80",
+				BackendProtocol: pointers.NewString(translatorapi.IngressBackendProtocolHTTP),
+				Canaries: []*translatorapi.IngressEdgeLBPoolCanarySpec{
+					{
+						ServiceName: "web-canary",
+						ServicePort: "80",
+						Weight:      pointers.NewInt32(10),
+						Header:      &translatorapi.IngressEdgeLBPoolCanaryMatchSpec{Name: "X-Canary", Value: "always"},
+					},
+					{
+						ServiceName: "web-beta",
+						ServicePort: "8080",
+						Weight:      pointers.NewInt32(5),
+					},
+				},
+			},
+		},
+		Frontends: &translatorapi.IngressEdgeLBPoolFrontendsSpec{
+			HTTP: &translatorapi.IngressEdgeLBPoolHTTPFrontendSpec{
+				Mode: pointers.NewString(translatorapi.IngressEdgeLBHTTPModeEnabled),
+				Port: pointers.NewInt32(80),
+			},
+		},
+		PathType: pointers.NewString(translatorapi.IngressPathTypePrefix),
+	}
+	backendMap := IngressBackendNodePortMap{
+		primary: 30080,
+		{Name: "web-canary", Port: networkingv1.ServiceBackendPort{Number: 80}}:   30081,
+		{Name: "web-beta", Port: networkingv1.ServiceBackendPort{Number: 8080}}:   30082,
+		{Name: "web-unused", Port: networkingv1.ServiceBackendPort{Number: 8080}}: 30083,
+	}
+
+	// Only the canaries to which requests can be forced must be included in the set of canaries requiring an EdgeLB backend of their own.
+	assert.Equal(t, map[networkingv1.IngressServiceBackend]bool{
+		{Name: "web-canary", Port: networkingv1.ServiceBackendPort{Number: 80}}: true,
+		{Name: "web-beta", Port: networkingv1.ServiceBackendPort{Number: 8080}}: false,
+	}, computeIngressCanaryBackends(ingress, spec))
+
+	// Traffic sent to the primary EdgeLB backend must be split between the target Service resource and its canaries.
+	backend := computeEdgeLBBackendForIngressBackend(ingress, spec, primary, backendMap, nil, nil)
+	assert.Len(t, backend.Services, 3)
+	for idx, expected := range []struct {
+		miscStr  string
+		nodePort int32
+	}{
+		{miscStr: "weight 85", nodePort: 30080},
+		{miscStr: "weight 10", nodePort: 30081},
+		{miscStr: "weight 5", nodePort: 30082},
+	} {
+		assert.Equal(t, expected.miscStr, backend.Services[idx].Endpoint.MiscStr)
+		assert.Equal(t, expected.nodePort, backend.Services[idx].Endpoint.Port)
+	}
+
+	// Requests carrying the header of a canary must be forced to the canary's EdgeLB backend, both for the matching rule and for the default backend.
+	frontends := computeEdgeLBFrontendForIngress(ingress, spec, nil)
+	assert.Equal(t, []string{
+		"use_backend test-cluster:test-namespace:test-ingress:web-canary:80 if { req.hdr(X-Canary) -m str always } { req.hdr(host),field(1,:) -m str -i example.com } { path -m reg ^/app(/.*)?$ }",
+		"use_backend test-cluster:test-namespace:test-ingress:web-canary:80 if { req.hdr(X-Canary) -m str always }",
+	}, frontends[0].MiscStrs)
+
+	// Directives created for other Ingress resources must be left untouched.
+	pool := &models.V2Pool{
+		Haproxy: &models.V2Haproxy{
+			Frontends: []*models.V2Frontend{
+				{
+					BindPort:    pointers.NewInt32(80),
+					LinkBackend: &models.V2FrontendLinkBackend{},
+					MiscStrs: []string{
+						"use_backend test-cluster:test-namespace:test-ingress:old-canary:80 if { req.hdr(X-Canary) -m str always }",
+						"use_backend test-cluster:test-namespace:other-ingress:canary:80 if { req.hdr(X-Canary) -m str always }",
+					},
+					Name: "test-cluster:test-namespace:other-ingress:http",
+				},
+			},
+		},
+	}
+	spec.Backends[0].Canaries[0].Header = nil
+	frontends = computeEdgeLBFrontendForIngress(ingress, spec, pool)
+	assert.Equal(t, []string{
+		"use_backend test-cluster:test-namespace:other-ingress:canary:80 if { req.hdr(X-Canary) -m str always }",
+	}, frontends[0].MiscStrs)
+
+	// Directives forcing requests to a canary must come before any other directive of the EdgeLB frontend, so that they are evaluated before the rules computed from its map.
+	spec.Backends[0].Canaries[0].Header = &translatorapi.IngressEdgeLBPoolCanaryMatchSpec{Name: "X-Canary", Value: "always"}
+	pool.Haproxy.Frontends[0].MiscStrs = []string{
+		"timeout client 30s",
+		"use_backend test-cluster:test-namespace:other-ingress:canary:80 if { req.hdr(X-Canary) -m str always }",
+		"http-response set-header Strict-Transport-Security \"max-age=31536000\"",
+	}
+	frontends = computeEdgeLBFrontendForIngress(ingress, spec, pool)
+	assert.Equal(t, []string{
+		"use_backend test-cluster:test-namespace:other-ingress:canary:80 if { req.hdr(X-Canary) -m str always }",
+		"use_backend test-cluster:test-namespace:test-ingress:web-canary:80 if { req.hdr(X-Canary) -m str always } { req.hdr(host),field(1,:) -m str -i example.com } { path -m reg ^/app(/.*)?$ }",
+		"use_backend test-cluster:test-namespace:test-ingress:web-canary:80 if { req.hdr(X-Canary) -m str always }",
+		"timeout client 30s",
+		"http-response set-header Strict-Transport-Security \"max-age=31536000\"",
+	}, frontends[0].MiscStrs)
+	spec.Backends[0].Canaries[0].Header = nil
+
+	// Canaries to which requests cannot be forced must not have an EdgeLB backend of their own.
+	backends := computeEdgeLBBackendForIngress(ingress, spec, backendMap, nil, nil)
+	names := make([]string, 0, len(backends))
+	for _, b := range backends {
+		names = append(names, b.Name)
+	}
+	assert.ElementsMatch(t, []string{
+		"test-cluster:test-namespace:test-ingress:web:80",
+		"test-cluster:test-namespace:test-ingress:web-unused:8080",
+	}, names)
+}
+
+func TestComputeEdgeLBBackendForIngressBackend_externalTrafficPolicyLocal(t *testing.T) {
+	cluster.Name = "test-cluster"
+
+	primary := networkingv1.IngressServiceBackend{Name: "web", Port: networkingv1.ServiceBackendPort{Number: 80}}
+	canary := networkingv1.IngressServiceBackend{Name: "web-canary", Port: networkingv1.ServiceBackendPort{Number: 80}}
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-namespace",
+			Name:      "test-ingress",
+			UID:       "uid",
+		},
+		Spec: networkingv1.IngressSpec{
+			DefaultBackend: &networkingv1.IngressBackend{Service: &primary},
+		},
+	}
+	spec := translatorapi.IngressEdgeLBPoolSpec{
+		Backends: []*translatorapi.IngressEdgeLBPoolBackendSpec{
+			{
+				BaseEdgeLBPoolBackendSpec: translatorapi.BaseEdgeLBPoolBackendSpec{
+					HealthCheck: &translatorapi.EdgeLBBackendHealthCheckSpec{
+						Path:     pointers.NewString("/ready"),
+						Interval: pointers.NewString("2s"),
+					},
+				},
+				ServiceName:     "web",
+				ServicePort:     "80",
+				BackendProtocol: pointers.NewString(translatorapi.IngressBackendProtocolHTTP),
+				Canaries: []*translatorapi.IngressEdgeLBPoolCanarySpec{
+					{
+						ServiceName: "web-canary",
+						ServicePort: "80",
+						Weight:      pointers.NewInt32(10),
+					},
+				},
+			},
+		},
+		PathType: pointers.NewString(translatorapi.IngressPathTypeImplementationSpecific),
+	}
+	backendMap := IngressBackendNodePortMap{
+		primary: 30080,
+		canary:  30081,
+	}
+
+	// Each server must be health-checked using the health-check node port of the Service resource it targets, preserving the health-check interval.
+	backend := computeEdgeLBBackendForIngressBackend(ingress, spec, primary, backendMap, nil, IngressBackendHealthCheckNodePortMap{primary: 32000, canary: 32001})
+	assert.Equal(t, &models.V2BackendCustomCheck{Httpchk: true, HttpchkMiscStr: "GET /healthz"}, backend.CustomCheck)
+	assert.Len(t, backend.Services, 2)
+	assert.Equal(t, "inter 2000ms port 32000", backend.Services[0].Endpoint.Check.CustomStr)
+	assert.Equal(t, "inter 2000ms port 32001", backend.Services[1].Endpoint.Check.CustomStr)
+	// The configured health-checks must be used in case any of the target Service resources has an external traffic policy of "Cluster".
+	backend = computeEdgeLBBackendForIngressBackend(ingress, spec, primary, backendMap, nil, IngressBackendHealthCheckNodePortMap{primary: 32000})
+	assert.Equal(t, &models.V2BackendCustomCheck{Httpchk: true, HttpchkMiscStr: "GET /ready"}, backend.CustomCheck)
+	assert.Equal(t, "inter 2000ms", backend.Services[0].Endpoint.Check.CustomStr)
+	assert.Equal(t, "inter 2000ms", backend.Services[1].Endpoint.Check.CustomStr)
+}
+
+func TestComputeEdgeLBSecretsForIngress(t *testing.T) {
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-namespace",
+			Name:      "test-ingress",
+			UID:       "uid",
+		},
+		Spec: networkingv1.IngressSpec{
+			DefaultBackend: &networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{
+					Name: "test-service",
+					Port: networkingv1.ServiceBackendPort{Name: "https"},
+				},
+			},
+			TLS: []networkingv1.IngressTLS{
+				{SecretName: "test-secret"},
+			},
+		},
+	}
+	spec := translatorapi.IngressEdgeLBPoolSpec{
+		Backends: []*translatorapi.IngressEdgeLBPoolBackendSpec{
+			{
+				ServiceName:     "test-service",
+				ServicePort:     "https",
+				BackendProtocol: pointers.NewString(translatorapi.IngressBackendProtocolHTTPS),
+				CASecretName:    pointers.NewString("test-ca"),
+			},
+		},
+		BasicAuth: &translatorapi.IngressEdgeLBPoolBasicAuthSpec{
+			Realm:      pointers.NewString("dklb"),
+			SecretName: pointers.NewString("test-auth"),
+		},
+		Frontends: &translatorapi.IngressEdgeLBPoolFrontendsSpec{
+			HTTPS: &translatorapi.IngressEdgeLBPoolHTTPSFrontendSpec{
+				ClientAuth: &translatorapi.IngressEdgeLBPoolClientAuthSpec{
+					CASecretName: pointers.NewString("test-client-ca"),
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, []*models.V2PoolSecretsItems0{
+		{Secret: "uid__test-secret", File: "uid__test-secret"},
+		{Secret: "uid__test-client-ca__ca", File: "uid__test-client-ca__ca"},
+		{Secret: "uid__test-ca__ca", File: "uid__test-ca__ca"},
+		{Secret: "uid__test-auth__auth", File: "uid__test-auth__auth"},
+	}, computeEdgeLBSecretsForIngress(ingress, spec))
+}
@@ -0,0 +1,85 @@
+package translator
+
+import (
+	"fmt"
+
+	"github.com/mesosphere/dcos-edge-lb/pkg/apis/models"
+
+	secretsreflector "github.com/mesosphere/dklb/pkg/secrets_reflector"
+	translatorapi "github.com/mesosphere/dklb/pkg/translator/api"
+)
+
+const (
+	// edgeLBSecretFileFormatString is the format string used to compute the path to a given pool secret file.
+	edgeLBSecretFileFormatString = "$SECRETS/%s"
+)
+
+// computeEdgeLBSecretFilePath computes the path to the pool secret file holding the specified DC/OS secret (e.g. a certificate).
+func computeEdgeLBSecretFilePath(dcosSecretName string) string {
+	return fmt.Sprintf(edgeLBSecretFileFormatString, secretsreflector.ComputeDCOSSecretFileName(dcosSecretName))
+}
+
+// edgeLBPoolSettingsChange describes a change made to the settings of an EdgeLB pool (e.g. its CPU request) in order to reconcile them with an EdgeLB pool configuration object.
+type edgeLBPoolSettingsChange struct {
+	// Setting is the human-readable name of the setting that was changed (e.g. "cpu request").
+	Setting string
+	// Redeploy indicates whether the change causes EdgeLB to redeploy the EdgeLB pool's load balancer instances.
+	Redeploy bool
+}
+
+// reconcileEdgeLBPoolSettings updates the CPU, memory and size requests, the placement constraints and the role of the specified EdgeLB pool in-place so that they match the specified EdgeLB pool configuration object.
+// It returns the list of changes made to the EdgeLB pool, which is empty in case the EdgeLB pool was already up-to-date.
+// Changing the size of an EdgeLB pool only causes load balancer instances to be added or removed, and hence doesn't cause a redeployment.
+// Placement constraints are only reconciled when they have been specified, as EdgeLB applies its own defaults otherwise.
+func reconcileEdgeLBPoolSettings(pool *models.V2Pool, spec translatorapi.BaseEdgeLBPoolSpec) []edgeLBPoolSettingsChange {
+	res := make([]edgeLBPoolSettingsChange, 0)
+	// Update the CPU request as necessary.
+	if pool.Cpus != *spec.CPUs {
+		pool.Cpus = *spec.CPUs
+		res = append(res, edgeLBPoolSettingsChange{Setting: "cpu request", Redeploy: true})
+	}
+	// Update the memory request as necessary.
+	if pool.Mem != *spec.Memory {
+		pool.Mem = *spec.Memory
+		res = append(res, edgeLBPoolSettingsChange{Setting: "memory request", Redeploy: true})
+	}
+	// Update the size request as necessary.
+	if pool.Count == nil || *pool.Count != *spec.Size {
+		size := *spec.Size
+		pool.Count = &size
+		res = append(res, edgeLBPoolSettingsChange{Setting: "size request", Redeploy: false})
+	}
+	// Update the placement constraints as necessary.
+	if spec.Constraints != nil && (pool.Constraints == nil || *pool.Constraints != *spec.Constraints) {
+		constraints := *spec.Constraints
+		pool.Constraints = &constraints
+		res = append(res, edgeLBPoolSettingsChange{Setting: "placement constraints", Redeploy: true})
+	}
+	// Update the role as necessary.
+	if pool.Role != *spec.Role {
+		pool.Role = *spec.Role
+		res = append(res, edgeLBPoolSettingsChange{Setting: "role", Redeploy: true})
+	}
+	return res
+}
+
+// redeployedEdgeLBPoolSettings returns the names of the settings whose change causes EdgeLB to redeploy the EdgeLB pool's load balancer instances.
+// It returns nil in case none of the specified changes causes a redeployment.
+func redeployedEdgeLBPoolSettings(changes []edgeLBPoolSettingsChange) []string {
+	var res []string
+	for _, change := range changes {
+		if change.Redeploy {
+			res = append(res, change.Setting)
+		}
+	}
+	return res
+}
+
+// replacedEdgeLBPoolStrategies returns the strategies to use when removing an Ingress/Service resource from the EdgeLB pool being replaced by its target EdgeLB pool.
+// As replacing an EdgeLB pool requires the Ingress/Service resource to be actually removed from the EdgeLB pool being replaced, the "Orphan" and "Retain" deletion strategies (which may leave its EdgeLB frontends and EdgeLB backends in place) are overridden with the default one.
+func replacedEdgeLBPoolStrategies(strategies translatorapi.EdgeLBPoolManagementStrategies) *translatorapi.EdgeLBPoolManagementStrategies {
+	if *strategies.Deletion == translatorapi.EdgeLBPoolDeletionStrategyOrphan || *strategies.Deletion == translatorapi.EdgeLBPoolDeletionStrategyRetain {
+		strategies.Deletion = &translatorapi.DefaultEdgeLBPoolDeletionStrategy
+	}
+	return &strategies
+}
@@ -0,0 +1,119 @@
+package translator
+
+import (
+	"testing"
+
+	"github.com/mesosphere/dcos-edge-lb/pkg/apis/models"
+	"github.com/stretchr/testify/assert"
+
+	translatorapi "github.com/mesosphere/dklb/pkg/translator/api"
+	"github.com/mesosphere/dklb/pkg/util/pointers"
+)
+
+// TestReconcileEdgeLBPoolSettings tests the "reconcileEdgeLBPoolSettings" function.
+func TestReconcileEdgeLBPoolSettings(t *testing.T) {
+	tests := []struct {
+		description       string
+		mutator           func(*translatorapi.BaseEdgeLBPoolSpec)
+		expectedPool      *models.V2Pool
+		expectedRedeploys []string
+		expectedChanges   int
+	}{
+		{
+			description: "no changes",
+			mutator:     func(*translatorapi.BaseEdgeLBPoolSpec) {},
+			expectedPool: &models.V2Pool{
+				Role:  "*",
+				Cpus:  0.1,
+				Mem:   128,
+				Count: pointers.NewInt32(1),
+			},
+			expectedRedeploys: nil,
+			expectedChanges:   0,
+		},
+		{
+			description: "size change",
+			mutator: func(spec *translatorapi.BaseEdgeLBPoolSpec) {
+				spec.Size = pointers.NewInt32(3)
+			},
+			expectedPool: &models.V2Pool{
+				Role:  "*",
+				Cpus:  0.1,
+				Mem:   128,
+				Count: pointers.NewInt32(3),
+			},
+			expectedRedeploys: nil,
+			expectedChanges:   1,
+		},
+		{
+			description: "cpu, memory, constraints and role changes",
+			mutator: func(spec *translatorapi.BaseEdgeLBPoolSpec) {
+				spec.CPUs = pointers.NewFloat64(0.5)
+				spec.Memory = pointers.NewInt32(256)
+				spec.Constraints = pointers.NewString(`[["hostname","UNIQUE"]]`)
+				spec.Role = pointers.NewString("custom-role")
+			},
+			expectedPool: &models.V2Pool{
+				Role:        "custom-role",
+				Cpus:        0.5,
+				Mem:         256,
+				Count:       pointers.NewInt32(1),
+				Constraints: pointers.NewString(`[["hostname","UNIQUE"]]`),
+			},
+			expectedRedeploys: []string{"cpu request", "memory request", "placement constraints", "role"},
+			expectedChanges:   4,
+		},
+	}
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+		pool := &models.V2Pool{
+			Role:  "*",
+			Cpus:  0.1,
+			Mem:   128,
+			Count: pointers.NewInt32(1),
+		}
+		spec := translatorapi.BaseEdgeLBPoolSpec{
+			Role:   pointers.NewString("*"),
+			CPUs:   pointers.NewFloat64(0.1),
+			Memory: pointers.NewInt32(128),
+			Size:   pointers.NewInt32(1),
+		}
+		test.mutator(&spec)
+		changes := reconcileEdgeLBPoolSettings(pool, spec)
+		assert.Len(t, changes, test.expectedChanges)
+		assert.Equal(t, test.expectedRedeploys, redeployedEdgeLBPoolSettings(changes))
+		assert.Equal(t, test.expectedPool, pool)
+	}
+}
+
+// TestReplacedEdgeLBPoolStrategies tests the "replacedEdgeLBPoolStrategies" function.
+func TestReplacedEdgeLBPoolStrategies(t *testing.T) {
+	tests := []struct {
+		deletion         translatorapi.EdgeLBPoolDeletionStrategy
+		expectedDeletion translatorapi.EdgeLBPoolDeletionStrategy
+	}{
+		{
+			deletion:         translatorapi.EdgeLBPoolDeletionStrategyDeleteWhenEmpty,
+			expectedDeletion: translatorapi.EdgeLBPoolDeletionStrategyDeleteWhenEmpty,
+		},
+		{
+			deletion:         translatorapi.EdgeLBPoolDeletionStrategyOrphan,
+			expectedDeletion: translatorapi.EdgeLBPoolDeletionStrategyDeleteWhenEmpty,
+		},
+		{
+			deletion:         translatorapi.EdgeLBPoolDeletionStrategyRetain,
+			expectedDeletion: translatorapi.EdgeLBPoolDeletionStrategyDeleteWhenEmpty,
+		},
+	}
+	for _, test := range tests {
+		t.Logf("test case: %s", test.deletion)
+		deletion := test.deletion
+		strategies := translatorapi.EdgeLBPoolManagementStrategies{
+			Creation: &translatorapi.EdgeLBPoolCreationStrategyIfNotPresent,
+			Deletion: &deletion,
+		}
+		res := replacedEdgeLBPoolStrategies(strategies)
+		assert.Equal(t, test.expectedDeletion, *res.Deletion)
+		assert.Equal(t, test.deletion, *strategies.Deletion)
+	}
+}
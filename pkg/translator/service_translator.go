@@ -4,10 +4,14 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/mesosphere/dcos-edge-lb/pkg/apis/models"
 	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 
 	dklbcache "github.com/mesosphere/dklb/pkg/cache"
 	"github.com/mesosphere/dklb/pkg/constants"
@@ -33,15 +37,26 @@ type ServiceTranslator struct {
 	logger *log.Entry
 	// poolGroup is the DC/OS service group in which to create EdgeLB pools.
 	poolGroup string
+	// endpoints is the Endpoints resource associated with the Service resource.
+	// It is only used (and populated) when EdgeLB routes traffic directly to pod IPs.
+	endpoints *corev1.Endpoints
+	// recorder is the event recorder used to emit events associated with a given Service resource.
+	recorder record.EventRecorder
+	// redeployedSettings holds the names of the settings of the target EdgeLB pool whose change causes EdgeLB to redeploy its load balancer instances.
+	// It is populated when updating the target EdgeLB pool object.
+	redeployedSettings []string
+	// replacedEdgeLBPool holds whether the Service resource has been removed from the EdgeLB pool replaced by the target EdgeLB pool (if any).
+	replacedEdgeLBPool bool
 }
 
 // NewServiceTranslator returns a service translator that can be used to translate the specified Service resource into an EdgeLB pool.
-func NewServiceTranslator(service *corev1.Service, kubeCache dklbcache.KubernetesResourceCache, manager manager.EdgeLBManager) *ServiceTranslator {
+func NewServiceTranslator(service *corev1.Service, kubeCache dklbcache.KubernetesResourceCache, manager manager.EdgeLBManager, recorder record.EventRecorder) *ServiceTranslator {
 	return &ServiceTranslator{
 		service:   service,
 		kubeCache: kubeCache,
 		manager:   manager,
 		logger:    log.WithField("service", kubernetesutil.Key(service)),
+		recorder:  recorder,
 		poolGroup: manager.PoolGroup(),
 	}
 }
@@ -58,6 +73,16 @@ func (st *ServiceTranslator) Translate() (*corev1.LoadBalancerStatus, error) {
 	// Dump the EdgeLB pool configuration object for debugging purposes.
 	prettyprint.LogfSpew(log.Tracef, spec, "edgelb pool configuration object for %q", kubernetesutil.Key(st.service))
 
+	// Grab the Endpoints resource associated with the Service resource in case EdgeLB routes traffic directly to pod IPs.
+	// A missing Endpoints resource (e.g. because the Service resource has just been created) is interpreted as the absence of ready pods.
+	if st.spec.RoutesToPodIPs() {
+		endpoints, err := st.kubeCache.GetEndpoints(st.service.Namespace, st.service.Name)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to read the endpoints for service %q: %v", kubernetesutil.Key(st.service), err)
+		}
+		st.endpoints = endpoints
+	}
+
 	// Check whether a pool with the requested name already exists in EdgeLB.
 	ctx, fn := context.WithTimeout(context.Background(), defaultEdgeLBManagerTimeout)
 	defer fn()
@@ -67,18 +92,87 @@ func (st *ServiceTranslator) Translate() (*corev1.LoadBalancerStatus, error) {
 			return nil, fmt.Errorf("failed to check for the existence of the %q edgelb pool: %v", *st.spec.Name, err)
 		}
 	}
-	// If the target EdgeLB pool does not exist, we must try to create it,
+	var status *corev1.LoadBalancerStatus
 	if pool == nil {
-		return st.createEdgeLBPool()
+		// If the target EdgeLB pool does not exist, we must try to create it,
+		status, err = st.createEdgeLBPool()
+	} else {
+		// If the target EdgeLB pool already exists, we must check whether it needs to be updated/deleted.
+		status, err = st.updateOrDeleteEdgeLBPool(pool)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Proceed with the replacement of the EdgeLB pool previously targeted by the Service resource (if any).
+	return st.replaceEdgeLBPool(status)
+}
+
+// ReplacedEdgeLBPool returns a value indicating whether the Service resource has been removed from the EdgeLB pool being replaced by the target EdgeLB pool.
+// In case it returns true, the "kubernetes.dcos.io/dklb-replaced-pool" annotation can be removed from the Service resource.
+func (st *ServiceTranslator) ReplacedEdgeLBPool() bool {
+	return st.replacedEdgeLBPool
+}
+
+// replacedEdgeLBPoolName returns the name of the EdgeLB pool being replaced by the target EdgeLB pool, or an empty string if no EdgeLB pool is being replaced.
+func (st *ServiceTranslator) replacedEdgeLBPoolName() string {
+	if name := st.service.Annotations[constants.DklbReplacedPoolAnnotationKey]; name != *st.spec.Name {
+		return name
+	}
+	return ""
+}
+
+// replaceEdgeLBPool removes the Service resource from the EdgeLB pool being replaced by the target EdgeLB pool (if any) as soon as the target EdgeLB pool has become ready.
+// The specified status is the status of the target EdgeLB pool, and is returned unchanged unless the target EdgeLB pool is not ready yet.
+// In that case, the status of the EdgeLB pool being replaced is returned instead, so that clients keep connecting to it.
+func (st *ServiceTranslator) replaceEdgeLBPool(status *corev1.LoadBalancerStatus) (*corev1.LoadBalancerStatus, error) {
+	replacedPoolName := st.replacedEdgeLBPoolName()
+	if replacedPoolName == "" {
+		return status, nil
+	}
+	// Wait for the target EdgeLB pool to report the addresses at which the Service resource can be reached before removing the Service resource from the EdgeLB pool being replaced.
+	// There is no point in waiting in case the Service resource has been deleted.
+	serviceDeleted := st.service.DeletionTimestamp != nil || !kubernetesutil.IsEdgeLBService(st.service)
+	if !serviceDeleted && (status == nil || len(status.Ingress) == 0) {
+		st.logger.Infof("waiting for edgelb pool %q to become ready before removing %q from edgelb pool %q", *st.spec.Name, kubernetesutil.Key(st.service), replacedPoolName)
+		return computeLoadBalancerStatus(st.manager, replacedPoolName, st.service, nil), nil
 	}
-	// If the target EdgeLB pool already exists, we must check whether it needs to be updated/deleted.
-	return st.updateOrDeleteEdgeLBPool(pool)
+	ctx, fn := context.WithTimeout(context.Background(), defaultEdgeLBManagerTimeout)
+	defer fn()
+	pool, err := st.manager.GetPool(ctx, replacedPoolName)
+	if err != nil && !dklberrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to check for the existence of the %q edgelb pool: %v", replacedPoolName, err)
+	}
+	// Remove the Service resource from the EdgeLB pool being replaced (if it still exists) by pretending it has been deleted.
+	if pool != nil {
+		deletionTimestamp := metav1.Now()
+		replaced := *st
+		replaced.service = st.service.DeepCopy()
+		replaced.service.DeletionTimestamp = &deletionTimestamp
+		replacedSpec := *st.spec
+		replacedSpec.Name = &replacedPoolName
+		replacedSpec.Strategies = replacedEdgeLBPoolStrategies(*st.spec.Strategies)
+		replaced.spec = &replacedSpec
+		replaced.redeployedSettings = nil
+		if _, err := replaced.updateOrDeleteEdgeLBPool(pool); err != nil {
+			return nil, fmt.Errorf("failed to remove %q from edgelb pool %q: %v", kubernetesutil.Key(st.service), replacedPoolName, err)
+		}
+	}
+	st.logger.Infof("removed %q from edgelb pool %q", kubernetesutil.Key(st.service), replacedPoolName)
+	st.replacedEdgeLBPool = true
+	return status, nil
 }
 
 // createEdgeLBPool makes a decision on whether an EdgeLB pool should be created for the associated Service resource.
 // This decision is based on the pool creation strategy specified for the Service resource.
 // In case it should be created, it proceeds to actually creating it.
 func (st *ServiceTranslator) createEdgeLBPool() (*corev1.LoadBalancerStatus, error) {
+	// If the Service resource has been deleted (or is not meant to be provisioned by EdgeLB anymore), there is nothing to clean up.
+	// Hence, and as the target EdgeLB pool must not be re-created, we should just exit.
+	if st.service.DeletionTimestamp != nil || !kubernetesutil.IsEdgeLBService(st.service) {
+		st.logger.Debugf("edgelb pool %q does not exist, so there is nothing to clean up", *st.spec.Name)
+		return &corev1.LoadBalancerStatus{}, nil
+	}
+
 	// If the pool creation strategy is "Never", the target pool must be provisioned manually.
 	// Hence, we should just exit.
 	if *st.spec.Strategies.Creation == translatorapi.EdgeLBPoolCreationStrategyNever {
@@ -87,7 +181,8 @@ func (st *ServiceTranslator) createEdgeLBPool() (*corev1.LoadBalancerStatus, err
 
 	// If the Service resource's ".status" field contains at least one IP/host, that means a pool has once existed, but has been deleted manually.
 	// Hence, and if the pool creation strategy is "Once", we should also just exit.
-	if len(st.service.Status.LoadBalancer.Ingress) > 0 && *st.spec.Strategies.Creation == translatorapi.EdgeLBPoolCreationStrategyOnce {
+	// This doesn't apply while the pool targeted by the Service resource is being replaced, as the Service resource's ".status" field then refers to the pool being replaced.
+	if len(st.service.Status.LoadBalancer.Ingress) > 0 && *st.spec.Strategies.Creation == translatorapi.EdgeLBPoolCreationStrategyOnce && st.replacedEdgeLBPoolName() == "" {
 		return nil, fmt.Errorf("edgelb pool %q targeted by service %q has probably been manually deleted, and the pool creation strategy is %q", *st.spec.Name, kubernetesutil.Key(st.service), *st.spec.Strategies.Creation)
 	}
 
@@ -105,13 +200,19 @@ func (st *ServiceTranslator) createEdgeLBPool() (*corev1.LoadBalancerStatus, err
 		return nil, err
 	}
 	// Compute and return the status of the load-balancer.
-	return computeLoadBalancerStatus(st.manager, pool.Name, st.service, nil), nil
+	return computeLoadBalancerStatus(st.manager, pool.Name, st.service, computeSNIFrontendsForService(st.service, *st.spec)), nil
 }
 
 // updateOrDeleteEdgeLBPool makes a decision on whether the specified EdgeLB pool should be updated/deleted based on the current status of the associated Service resource.
 // In case it should be updated/deleted, it proceeds to actually updating/deleting it.
-// TODO (@bcustodio) Decide whether we should also update the pool's role and its CPU/memory/size requests when updating a pool.
 func (st *ServiceTranslator) updateOrDeleteEdgeLBPool(pool *models.V2Pool) (*corev1.LoadBalancerStatus, error) {
+	// If the Service resource has been deleted and the pool deletion strategy is "Orphan", we leave its backends and frontends untouched.
+	serviceDeleted := st.service.DeletionTimestamp != nil || !kubernetesutil.IsEdgeLBService(st.service)
+	if serviceDeleted && *st.spec.Strategies.Deletion == translatorapi.EdgeLBPoolDeletionStrategyOrphan {
+		st.logger.Debugf("leaving the backends and frontends of %q in edgelb pool %q as the pool deletion strategy is %q", kubernetesutil.Key(st.service), pool.Name, *st.spec.Strategies.Deletion)
+		return &corev1.LoadBalancerStatus{}, nil
+	}
+
 	// Check whether the pool object must be updated.
 	wasChanged, report, err := st.updateEdgeLBPoolObject(pool)
 	if err != nil {
@@ -126,7 +227,7 @@ func (st *ServiceTranslator) updateOrDeleteEdgeLBPool(pool *models.V2Pool) (*cor
 	// If the pool doesn't need to be updated, we just compute and return an updated "LoadBalancerStatus" object.
 	if !wasChanged {
 		st.logger.Debugf("edgelb pool %q is synced", pool.Name)
-		return computeLoadBalancerStatus(st.manager, pool.Name, st.service, nil), nil
+		return computeLoadBalancerStatus(st.manager, pool.Name, st.service, computeSNIFrontendsForService(st.service, *st.spec)), nil
 	}
 
 	// At this point we know that the pool must be either updated or deleted.
@@ -134,8 +235,14 @@ func (st *ServiceTranslator) updateOrDeleteEdgeLBPool(pool *models.V2Pool) (*cor
 	ctx, fn := context.WithTimeout(context.Background(), defaultEdgeLBManagerTimeout)
 	defer fn()
 
-	// If the pool is empty (i.e. it has no frontends or backends) we proceed to deleting it and reporting an empty status.
+	// If the pool is empty (i.e. it has no frontends or backends) we proceed to deleting (or retaining) it and reporting an empty status.
 	if len(pool.Haproxy.Frontends) == 0 && len(pool.Haproxy.Backends) == 0 {
+		// If the pool deletion strategy is "Retain", the pool must be kept.
+		// As EdgeLB pools are not meant to be left without any frontends or backends, we keep the pool alive by leaving it untouched (i.e. with the frontends and backends of the Service resource).
+		if *st.spec.Strategies.Deletion == translatorapi.EdgeLBPoolDeletionStrategyRetain {
+			st.logger.Infof("leaving edgelb pool %q untouched as it would become empty and the pool deletion strategy is %q", pool.Name, *st.spec.Strategies.Deletion)
+			return &corev1.LoadBalancerStatus{}, nil
+		}
 		// The pool is empty, so we must delete it.
 		st.logger.Debugf("edgelb pool %q is empty and must be deleted", pool.Name)
 		if err := st.manager.DeletePool(ctx, pool.Name); err != nil {
@@ -149,23 +256,32 @@ func (st *ServiceTranslator) updateOrDeleteEdgeLBPool(pool *models.V2Pool) (*cor
 	if _, err := st.manager.UpdatePool(ctx, pool); err != nil {
 		return nil, err
 	}
-	return computeLoadBalancerStatus(st.manager, pool.Name, st.service, nil), nil
+	// Let the user know in case the update causes EdgeLB to redeploy the pool's load balancer instances, as this may cause a short disruption.
+	if len(st.redeployedSettings) > 0 {
+		st.recorder.Eventf(st.service, corev1.EventTypeNormal, constants.ReasonEdgeLBPoolRedeploying, "edgelb pool %q will be redeployed as its %s changed", pool.Name, strings.Join(st.redeployedSettings, ", "))
+	}
+	return computeLoadBalancerStatus(st.manager, pool.Name, st.service, computeSNIFrontendsForService(st.service, *st.spec)), nil
 }
 
 // createEdgeLBPoolObject creates an EdgeLB pool object that satisfies the current Service resource.
 func (st *ServiceTranslator) createEdgeLBPoolObject() (*models.V2Pool, error) {
-	backends := make([]*models.V2Backend, 0, len(st.service.Spec.Ports))
-	frontends := make([]*models.V2Frontend, 0, len(st.service.Spec.Ports))
+	ports := kubernetesutil.SupportedServicePorts(st.service)
+	backends := make([]*models.V2Backend, 0, len(ports))
+	frontends := make([]*models.V2Frontend, 0, len(ports))
 
 	// Iterate over port definitions and create the corresponding backend and frontend objects.
-	for _, port := range st.service.Spec.Ports {
-		// Compute the backend and frontend for the current service port.
-		backend, frontend := computeBackendForServicePort(st.service, port), computeFrontendForServicePort(st.service, *st.spec, port)
-		// Append the backend to the slice of backends.
-		backends = append(backends, backend)
-		// Append the frontend to the slice of frontends.
-		frontends = append(frontends, frontend)
+	for _, port := range ports {
+		// Compute the backend for the current service port and append it to the slice of backends.
+		backends = append(backends, computeBackendForServicePort(st.service, *st.spec, port, st.endpoints))
+		// Service ports routed based on SNI share a frontend with other service ports, which is computed below.
+		if len(st.spec.SNIHostnamesFor(port.Port)) > 0 {
+			continue
+		}
+		// Compute the frontend for the current service port and append it to the slice of frontends.
+		frontends = append(frontends, computeFrontendForServicePort(st.service, *st.spec, port))
 	}
+	// Append the frontends shared by service ports routed based on SNI.
+	frontends = append(frontends, computeSNIFrontendsForService(st.service, *st.spec)...)
 
 	// Create and return the pool object.
 	p := &models.V2Pool{
@@ -189,6 +305,9 @@ func (st *ServiceTranslator) createEdgeLBPoolObject() (*models.V2Pool, error) {
 		p.Constraints = st.spec.Constraints
 	}
 
+	// Add the secrets holding the certificates used to terminate TLS connections (if any).
+	p.Secrets = computeEdgeLBSecretsForService(st.service, *st.spec)
+
 	// Request for a cloud load-balancer to be configured if applicable.
 	if *st.spec.CloudProviderConfiguration != "" {
 		o, err := st.unmarshalCloudProviderObject(*st.spec.CloudProviderConfiguration)
@@ -218,18 +337,36 @@ func (st *ServiceTranslator) createEdgeLBPoolObject() (*models.V2Pool, error) {
 // * If the object is owned by the current Service resource and the corresponding service port still exists, it is checked for correctness and updated if necessary.
 // Furthermore, service ports are iterated over in order to understand which objects must be added to the pool.
 func (st *ServiceTranslator) updateEdgeLBPoolObject(pool *models.V2Pool) (wasChanged bool, report poolInspectionReport, err error) {
-	// serviceDeleted holds whether the Service resource has been deleted (or changed to a different type, which must produce a similar effect).
-	serviceDeleted := st.service.DeletionTimestamp != nil || st.service.Spec.Type != corev1.ServiceTypeLoadBalancer
+	// serviceDeleted holds whether the Service resource has been deleted (or is not meant to be provisioned by EdgeLB anymore, which must produce a similar effect).
+	serviceDeleted := st.service.DeletionTimestamp != nil || !kubernetesutil.IsEdgeLBService(st.service)
 
 	// If the service has not been deleted, we iterate over ports defined on the service and re-compute the corresponding backend and frontend objects.
 	// These will be later compared with the backend and frontend objects reported by the EdgeLB API server (i.e. those in "pool").
-	desiredBackendFrontends := make(map[int32]servicePortBackendFrontend, len(st.service.Spec.Ports))
+	// Service ports routed based on SNI don't have a frontend of their own, and are instead routed to by the (shared) frontends in "desiredSNIFrontends".
+	// Service ports that cannot be exposed by EdgeLB (i.e. non-TCP ones) are ignored.
+	ports := kubernetesutil.SupportedServicePorts(st.service)
+	desiredBackendFrontends := make(map[int32]servicePortBackendFrontend, len(ports))
+	desiredSNIFrontends := make(map[string]*models.V2Frontend)
 	if !serviceDeleted {
-		for _, port := range st.service.Spec.Ports {
-			desiredBackendFrontends[port.Port] = servicePortBackendFrontend{
-				Backend:  computeBackendForServicePort(st.service, port),
-				Frontend: computeFrontendForServicePort(st.service, *st.spec, port),
+		// desiredFrontends holds every frontend (SNI or not) that must route to the service ports of the current service.
+		desiredFrontends := make([]*models.V2Frontend, 0, len(ports))
+		for _, port := range ports {
+			dbf := servicePortBackendFrontend{
+				Backend: computeBackendForServicePort(st.service, *st.spec, port, st.endpoints),
+			}
+			if len(st.spec.SNIHostnamesFor(port.Port)) == 0 {
+				dbf.Frontend = computeFrontendForServicePort(st.service, *st.spec, port)
+				desiredFrontends = append(desiredFrontends, dbf.Frontend)
 			}
+			desiredBackendFrontends[port.Port] = dbf
+		}
+		for _, frontend := range computeSNIFrontendsForService(st.service, *st.spec) {
+			desiredSNIFrontends[frontend.Name] = frontend
+			desiredFrontends = append(desiredFrontends, frontend)
+		}
+		// Make sure that the desired frontends do not conflict with the ones used by other Service resources sharing the pool.
+		if err := checkEdgeLBFrontendConflictsForService(st.service, pool, desiredFrontends); err != nil {
+			return false, report, err
 		}
 	}
 
@@ -283,6 +420,9 @@ func (st *ServiceTranslator) updateEdgeLBPoolObject(pool *models.V2Pool) (wasCha
 	// visitedFrontends holds the set of service ports corresponding to visited (existing) frontends.
 	// It is used to understand which service ports currently have frontend objects in the pool, and which don't.
 	visitedFrontends := make(map[int32]bool, len(pool.Haproxy.Frontends))
+	// visitedSNIFrontends holds the set of names of visited (existing) SNI frontends.
+	// It is used to understand which SNI frontends must be created.
+	visitedSNIFrontends := make(map[string]bool)
 	// updatedFrontends holds the set of updated frontend objects.
 	// It is used as the final set of frontends for the pool if we find out we need to update it.
 	updatedFrontends := make([]*models.V2Frontend, 0, len(pool.Haproxy.Frontends))
@@ -291,6 +431,27 @@ func (st *ServiceTranslator) updateEdgeLBPoolObject(pool *models.V2Pool) (wasCha
 	// In case a frontend isn't owned by the current service, it is left unchanged and added to the set of "updated" frontends.
 	// Otherwise, it is checked for correctness and, if necessary, replaced with the computed frontends for the target service port.
 	for _, frontend := range pool.Haproxy.Frontends {
+		// SNI frontends are shared between Service resources, so we only replace the routes to service ports of the current service.
+		// SNI frontends that don't route to any service port anymore are removed.
+		if isSNIFrontendName(frontend.Name) {
+			visitedSNIFrontends[frontend.Name] = true
+			updatedFrontend := *frontend
+			replaceSNIFrontendRoutesForService(&updatedFrontend, st.service, desiredSNIFrontends[frontend.Name])
+			if !hasSNIFrontendRoutes(&updatedFrontend) {
+				wasChanged = true
+				report.Report("must delete frontend %q as it does not route to any service port", frontend.Name)
+				continue
+			}
+			if !reflect.DeepEqual(frontend, &updatedFrontend) {
+				wasChanged = true
+				updatedFrontends = append(updatedFrontends, &updatedFrontend)
+				report.Report("must modify frontend %q", frontend.Name)
+			} else {
+				updatedFrontends = append(updatedFrontends, frontend)
+				report.Report("no changes required for frontend %q", frontend.Name)
+			}
+			continue
+		}
 		// Parse the name of the frontend in order to determine if the current service owns it.
 		// If the current frontend isn't owned by the current service, it is left unchanged.
 		frontendMetadata, err := computeServiceOwnedEdgeLBObjectMetadata(frontend.Name)
@@ -312,6 +473,12 @@ func (st *ServiceTranslator) updateEdgeLBPoolObject(pool *models.V2Pool) (wasCha
 			report.Report("must delete backend %q as port %d is missing from %s", frontend.Name, frontendMetadata.ServicePort, kubernetesutil.Key(st.service))
 			continue
 		}
+		// Check whether the target service port is now routed based on SNI and skip (i.e. remove) the frontend if it is.
+		if desiredBackendFrontends[frontendMetadata.ServicePort].Frontend == nil {
+			wasChanged = true
+			report.Report("must delete frontend %q as port %d is routed based on sni", frontend.Name, frontendMetadata.ServicePort)
+			continue
+		}
 		// At this point we know the service port corresponding to the current frontend still exists.
 		// Mark the current frontend/service port as having been visited.
 		visitedFrontends[frontendMetadata.ServicePort] = true
@@ -330,6 +497,22 @@ func (st *ServiceTranslator) updateEdgeLBPoolObject(pool *models.V2Pool) (wasCha
 	// Replace the pool's backends and frontends with the (possibly empty) updated lists.
 	pool.Haproxy.Backends, pool.Haproxy.Frontends = updatedBackends, updatedFrontends
 
+	// Replace the secrets used by the current service with the desired ones, leaving secrets used by other Service/Ingress resources untouched.
+	updatedSecrets := make([]*models.V2PoolSecretsItems0, 0, len(pool.Secrets))
+	for _, secret := range pool.Secrets {
+		if !isEdgeLBSecretOwnedByService(secret, st.service) {
+			updatedSecrets = append(updatedSecrets, secret)
+		}
+	}
+	if !serviceDeleted {
+		updatedSecrets = append(updatedSecrets, computeEdgeLBSecretsForService(st.service, *st.spec)...)
+	}
+	if len(updatedSecrets) != len(pool.Secrets) || (len(updatedSecrets) > 0 && !reflect.DeepEqual(updatedSecrets, pool.Secrets)) {
+		pool.Secrets = updatedSecrets
+		wasChanged = true
+		report.Report("must update the set of secrets")
+	}
+
 	// If the current Service resource was deleted, there is nothing else to compute.
 	if serviceDeleted {
 		return wasChanged, report, err
@@ -345,7 +528,7 @@ func (st *ServiceTranslator) updateEdgeLBPoolObject(pool *models.V2Pool) (wasCha
 			pool.Haproxy.Backends = append(pool.Haproxy.Backends, dbf.Backend)
 			report.Report("must create backend %q", dbf.Backend.Name)
 		}
-		if _, visited := visitedFrontends[port]; !visited {
+		if _, visited := visitedFrontends[port]; !visited && dbf.Frontend != nil {
 			// The current service port doesn't have a matching frontend.
 			// Hence, we add it to the set of updated frontends and mark the pool as requiring an update.
 			wasChanged = true
@@ -354,26 +537,24 @@ func (st *ServiceTranslator) updateEdgeLBPoolObject(pool *models.V2Pool) (wasCha
 		}
 	}
 
-	// Update the CPU request as necessary.
-	desiredCpus := *st.spec.CPUs
-	if pool.Cpus != desiredCpus {
-		pool.Cpus = desiredCpus
-		wasChanged = true
-		report.Report("must update the cpu request")
-	}
-	// Update the memory request as necessary.
-	desiredMem := *st.spec.Memory
-	if pool.Mem != desiredMem {
-		pool.Mem = desiredMem
-		wasChanged = true
-		report.Report("must update the memory request")
+	// Iterate over the SNI frontends for the current service, in order to understand whether there are new bind ports routed based on SNI.
+	for _, frontend := range computeSNIFrontendsForService(st.service, *st.spec) {
+		if !visitedSNIFrontends[frontend.Name] {
+			wasChanged = true
+			pool.Haproxy.Frontends = append(pool.Haproxy.Frontends, frontend)
+			report.Report("must create frontend %q", frontend.Name)
+		}
 	}
-	// Update the size request as necessary.
-	desiredSize := *st.spec.Size
-	if *pool.Count != desiredSize {
-		pool.Count = &desiredSize
-		wasChanged = true
-		report.Report("must update the size request")
+
+	// Update the CPU, memory and size requests, the placement constraints and the role as necessary.
+	// These are left untouched in case the service has been deleted, as the pool may be shared with other services.
+	if !serviceDeleted {
+		changes := reconcileEdgeLBPoolSettings(pool, st.spec.BaseEdgeLBPoolSpec)
+		for _, change := range changes {
+			wasChanged = true
+			report.Report("must update the %s", change.Setting)
+		}
+		st.redeployedSettings = redeployedEdgeLBPoolSettings(changes)
 	}
 
 	// Update the cloud-provider configuration as required.
@@ -0,0 +1,141 @@
+package translator
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mesosphere/dcos-edge-lb/pkg/apis/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	dklbcache "github.com/mesosphere/dklb/pkg/cache"
+	"github.com/mesosphere/dklb/pkg/cluster"
+	"github.com/mesosphere/dklb/pkg/constants"
+	translatorapi "github.com/mesosphere/dklb/pkg/translator/api"
+	"github.com/mesosphere/dklb/pkg/util/pointers"
+	cachetestutil "github.com/mesosphere/dklb/test/util/cache"
+	mockedgelb "github.com/mesosphere/dklb/test/util/edgelb/manager"
+	servicetestutil "github.com/mesosphere/dklb/test/util/kubernetes/service"
+)
+
+// TestServiceTranslator_deletionStrategies tests that the EdgeLB pool deletion strategies are honored when a Service resource is deleted.
+func TestServiceTranslator_deletionStrategies(t *testing.T) {
+	cluster.Name = "test-cluster"
+
+	// newService returns a Service resource using the specified EdgeLB pool deletion strategy.
+	newService := func(deletion translatorapi.EdgeLBPoolDeletionStrategy) *corev1.Service {
+		return servicetestutil.DummyServiceResource("test-namespace", "test-service", func(service *corev1.Service) {
+			service.Annotations = map[string]string{
+				constants.DklbConfigAnnotationKey: fmt.Sprintf(`
+name: test-pool
+strategies:
+  deletion: %s
+`, deletion),
+			}
+			service.UID = "uid"
+			service.Spec.Type = corev1.ServiceTypeLoadBalancer
+			service.Spec.Ports = []corev1.ServicePort{
+				{Port: 80, NodePort: 31889, Protocol: corev1.ProtocolTCP},
+			}
+		})
+	}
+	// newPool returns the EdgeLB pool created for the specified Service resource, optionally shared with an unmanaged EdgeLB frontend and EdgeLB backend.
+	newPool := func(service *corev1.Service, shared bool) *models.V2Pool {
+		var res *models.V2Pool
+		edgelbManager := new(mockedgelb.MockEdgeLBManager)
+		edgelbManager.On("PoolGroup").Return("test-pool-group")
+		edgelbManager.On("GetPool", mock.Anything, mock.Anything).Return(nil, nil)
+		edgelbManager.On("CreatePool", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			res = args.Get(1).(*models.V2Pool)
+		}).Return(&models.V2Pool{}, nil)
+		edgelbManager.On("GetPoolMetadata", mock.Anything, mock.Anything).Return(&models.V2PoolMetadata{}, nil)
+		kubeCache := dklbcache.NewInformerBackedResourceCache(cachetestutil.NewFakeSharedInformerFactory())
+		_, err := NewServiceTranslator(service, kubeCache, edgelbManager, record.NewFakeRecorder(10)).Translate()
+		assert.NoError(t, err)
+		if shared {
+			res.Haproxy.Backends = append(res.Haproxy.Backends, &models.V2Backend{Name: "unmanaged"})
+			res.Haproxy.Frontends = append(res.Haproxy.Frontends, &models.V2Frontend{
+				BindPort:    pointers.NewInt32(8080),
+				LinkBackend: &models.V2FrontendLinkBackend{DefaultBackend: "unmanaged"},
+				Name:        "unmanaged",
+				Protocol:    models.V2ProtocolTCP,
+			})
+		}
+		return res
+	}
+
+	tests := []struct {
+		description         string
+		deletion            translatorapi.EdgeLBPoolDeletionStrategy
+		shared              bool
+		expectedDeletePool  bool
+		expectedUpdatedPool bool
+	}{
+		{
+			description:        "should delete the edgelb pool when it becomes empty",
+			deletion:           translatorapi.EdgeLBPoolDeletionStrategyDeleteWhenEmpty,
+			expectedDeletePool: true,
+		},
+		{
+			description: "should leave the edgelb pool untouched instead of emptying it when the strategy is Retain",
+			deletion:    translatorapi.EdgeLBPoolDeletionStrategyRetain,
+		},
+		{
+			description:         "should remove the service from a shared edgelb pool when the strategy is Retain",
+			deletion:            translatorapi.EdgeLBPoolDeletionStrategyRetain,
+			shared:              true,
+			expectedUpdatedPool: true,
+		},
+		{
+			description: "should leave the edgelb pool untouched when the strategy is Orphan",
+			deletion:    translatorapi.EdgeLBPoolDeletionStrategyOrphan,
+		},
+		{
+			description: "should leave a shared edgelb pool untouched when the strategy is Orphan",
+			deletion:    translatorapi.EdgeLBPoolDeletionStrategyOrphan,
+			shared:      true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+
+		service := newService(test.deletion)
+		pool := newPool(service, test.shared)
+		service.DeletionTimestamp = &metav1.Time{}
+
+		var updatedPool *models.V2Pool
+		edgelbManager := new(mockedgelb.MockEdgeLBManager)
+		edgelbManager.On("PoolGroup").Return("test-pool-group")
+		edgelbManager.On("GetPool", mock.Anything, "test-pool").Return(pool, nil)
+		edgelbManager.On("GetPoolMetadata", mock.Anything, mock.Anything).Return(&models.V2PoolMetadata{}, nil)
+		edgelbManager.On("DeletePool", mock.Anything, "test-pool").Return(nil)
+		edgelbManager.On("UpdatePool", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			updatedPool = args.Get(1).(*models.V2Pool)
+		}).Return(&models.V2Pool{}, nil)
+		kubeCache := dklbcache.NewInformerBackedResourceCache(cachetestutil.NewFakeSharedInformerFactory())
+
+		status, err := NewServiceTranslator(service, kubeCache, edgelbManager, record.NewFakeRecorder(10)).Translate()
+		assert.NoError(t, err)
+		assert.Equal(t, &corev1.LoadBalancerStatus{}, status)
+		if test.expectedDeletePool {
+			edgelbManager.AssertCalled(t, "DeletePool", mock.Anything, "test-pool")
+		} else {
+			edgelbManager.AssertNotCalled(t, "DeletePool", mock.Anything, mock.Anything)
+		}
+		if test.expectedUpdatedPool {
+			assert.NotNil(t, updatedPool)
+			frontends := make([]string, 0, len(updatedPool.Haproxy.Frontends))
+			for _, frontend := range updatedPool.Haproxy.Frontends {
+				frontends = append(frontends, frontend.Name)
+			}
+			assert.Equal(t, []string{"unmanaged"}, frontends)
+			assert.Equal(t, []*models.V2Backend{{Name: "unmanaged"}}, updatedPool.Haproxy.Backends)
+		} else {
+			edgelbManager.AssertNotCalled(t, "UpdatePool", mock.Anything, mock.Anything)
+		}
+	}
+}
@@ -11,7 +11,9 @@ import (
 
 	"github.com/mesosphere/dklb/pkg/cluster"
 	"github.com/mesosphere/dklb/pkg/constants"
+	secretsreflector "github.com/mesosphere/dklb/pkg/secrets_reflector"
 	translatorapi "github.com/mesosphere/dklb/pkg/translator/api"
+	kubernetesutil "github.com/mesosphere/dklb/pkg/util/kubernetes"
 	"github.com/mesosphere/dklb/pkg/util/pointers"
 	stringsutil "github.com/mesosphere/dklb/pkg/util/strings"
 )
@@ -21,6 +23,26 @@ const (
 	serviceBackendNameFormatString = "%s" + separator + "%s" + separator + "%s" + separator + "%d"
 	// serviceFrontendNameFormatString is the format string used to compute the name for a frontend for a given Service resource.
 	serviceFrontendNameFormatString = serviceBackendNameFormatString
+	// serviceAllowedSourcesACLNameFormatString is the format string used to compute the name of the HAProxy ACL matching the source ranges allowed to access a frontend for a given Service resource.
+	// The resulting name is of the form "dklb_<service-uid>_allowed_sources", so that it does not collide with the ones of other Service resources sharing the frontend.
+	serviceAllowedSourcesACLNameFormatString = "dklb_%s_allowed_sources"
+	// edgeLBAcceptProxyBindModifier is the HAProxy bind option that causes client connection information to be read from the PROXY protocol header sent by the client (i.e. the cloud load balancer).
+	edgeLBAcceptProxyBindModifier = "accept-proxy"
+	// serviceSNIFrontendNamePrefix is the prefix of the name of a frontend shared by the service ports routed based on SNI on a given bind port.
+	// The resulting name is of the form "sni:<bind-port>".
+	serviceSNIFrontendNamePrefix = "sni" + separator
+	// edgeLBSNIAcceptClientHello is the HAProxy directive that accepts a connection as soon as the TLS "ClientHello" message (which carries the SNI hostname) has been received.
+	edgeLBSNIAcceptClientHello = "tcp-request content accept if { req_ssl_hello_type 1 }"
+	// edgeLBSNIConditionFormatString is the format string used to compute the HAProxy condition that matches TLS connections for a given set of SNI hostnames.
+	edgeLBSNIConditionFormatString = edgeLBSNIConditionPrefix + "%s" + edgeLBSNIConditionSuffix
+	// edgeLBSNIConditionPrefix is the prefix of the HAProxy condition that matches TLS connections for a given set of SNI hostnames.
+	edgeLBSNIConditionPrefix = "{ req_ssl_sni -i "
+	// edgeLBSNIConditionSuffix is the suffix of the HAProxy condition that matches TLS connections for a given set of SNI hostnames.
+	edgeLBSNIConditionSuffix = " }"
+	// edgeLBSNIInspectDelay is the HAProxy directive that sets the maximum time to wait for the TLS "ClientHello" message to be received.
+	edgeLBSNIInspectDelay = "tcp-request inspect-delay 5s"
+	// edgeLBSourceConditionFormatString is the format string used to compute the HAProxy condition that matches connections from a given set of source ranges.
+	edgeLBSourceConditionFormatString = "{ src %s }"
 	// separator is the separator used between the different parts that comprise the name of a backend/frontend.
 	separator = ":"
 )
@@ -59,10 +81,11 @@ func (sp *serviceOwnedEdgeLBObjectMetadata) IsOwnedBy(service *corev1.Service) b
 }
 
 // computeBackendForServicePort computes the backend that correspond to the specified service port.
-func computeBackendForServicePort(service *corev1.Service, servicePort corev1.ServicePort) *models.V2Backend {
+// In case EdgeLB routes traffic directly to pod IPs, the backend targets the ready endpoints contained in the specified Endpoints resource.
+// Otherwise, said Endpoints resource is ignored and may be nil.
+func computeBackendForServicePort(service *corev1.Service, spec translatorapi.ServiceEdgeLBPoolSpec, servicePort corev1.ServicePort, endpoints *corev1.Endpoints) *models.V2Backend {
 	// Compute the name to give to the backend.
-	return &models.V2Backend{
-		Balance:  constants.EdgeLBBackendBalanceLeastConnections,
+	res := &models.V2Backend{
 		Name:     backendNameForServicePort(service, servicePort),
 		Protocol: models.V2ProtocolTCP,
 		Services: []*models.V2Service{
@@ -97,32 +120,80 @@ func computeBackendForServicePort(service *corev1.Service, servicePort corev1.Se
 			},
 		},
 	}
+	// Target the ready pods backing the service port directly instead of the service port's node port, if requested.
+	if spec.RoutesToPodIPs() {
+		podEndpoints := computePodEndpointsForServicePort(endpoints, servicePort)
+		res.Services = make([]*models.V2Service, 0, len(podEndpoints))
+		for _, endpoint := range podEndpoints {
+			res.Services = append(res.Services, computeEdgeLBServiceForPodEndpoint(endpoint, ""))
+		}
+	}
+	// Apply the load-balancing and health-check configuration for the service port.
+	backendSpec := spec.BackendSpecFor(servicePort.Port)
+	applyBaseEdgeLBPoolBackendSpec(res, backendSpec)
+	// Only route traffic to nodes hosting ready endpoints for the Service resource in case its external traffic policy is "Local".
+	if !spec.RoutesToPodIPs() {
+		applyHealthCheckNodePort(res, service)
+	}
+	// Honor "ClientIP" session affinity by balancing based on the source IP, unless a balance algorithm has been explicitly specified.
+	if service.Spec.SessionAffinity == corev1.ServiceAffinityClientIP && (backendSpec == nil || backendSpec.Balance == nil || *backendSpec.Balance == "") {
+		res.Balance = constants.EdgeLBBackendBalanceSource
+	}
+	// Apply the server-side timeouts for the service port.
+	res.MiscStrs = append(res.MiscStrs, computeEdgeLBBackendTimeoutMiscStrs(spec.TimeoutsSpecFor(servicePort.Port))...)
+	return res
 }
 
-// computeFrontendForServicePort computes the frontend that correspond to the specified service port.
-func computeFrontendForServicePort(service *corev1.Service, spec translatorapi.ServiceEdgeLBPoolSpec, servicePort corev1.ServicePort) *models.V2Frontend {
-	var (
-		bindPort     int32
-		frontendName string
-	)
+// applyHealthCheckNodePort configures the specified EdgeLB backend to health-check each Kubernetes node using the health-check node port of the specified Service resource, in case its external traffic policy is "Local".
+// kube-proxy only reports a node as healthy on said port if the node hosts ready endpoints for the Service resource, meaning that EdgeLB won't route traffic to nodes where it would be dropped.
+func applyHealthCheckNodePort(backend *models.V2Backend, service *corev1.Service) {
+	healthCheckNodePort := computeHealthCheckNodePortForService(service)
+	if healthCheckNodePort == 0 {
+		return
+	}
+	healthCheckNodePorts := make([]int32, 0, len(backend.Services))
+	for range backend.Services {
+		healthCheckNodePorts = append(healthCheckNodePorts, healthCheckNodePort)
+	}
+	applyEdgeLBBackendHealthCheckNodePorts(backend, healthCheckNodePorts)
+}
+
+// computeHealthCheckNodePortForService returns the health-check node port of the specified Service resource.
+// It returns zero in case the external traffic policy of the Service resource is not "Local", in which case every Kubernetes node can route traffic to its pods.
+func computeHealthCheckNodePortForService(service *corev1.Service) int32 {
+	if service.Spec.ExternalTrafficPolicy != corev1.ServiceExternalTrafficPolicyTypeLocal {
+		return 0
+	}
+	return service.Spec.HealthCheckNodePort
+}
 
+// computeFrontendBindPortForServicePort computes the frontend bind port to use for the specified service port.
+func computeFrontendBindPortForServicePort(spec translatorapi.ServiceEdgeLBPoolSpec, servicePort corev1.ServicePort) int32 {
 	// If a cloud-provider configuration is being specified, force a dynamic frontend port.
-	// Otherwise, compute the value to use as the frontend bind port, falling back to the service port in case one isn't provided.
 	if *spec.CloudProviderConfiguration != "" {
-		bindPort = 0
-	} else {
-		bindPort = servicePort.Port
-		for _, frontend := range spec.Frontends {
-			if frontend.ServicePort == servicePort.Port {
-				bindPort = *frontend.Port
-			}
+		return 0
+	}
+	// Compute the value to use as the frontend bind port, falling back to the service port in case one isn't provided.
+	bindPort := servicePort.Port
+	for _, frontend := range spec.Frontends {
+		if frontend.ServicePort == servicePort.Port {
+			bindPort = *frontend.Port
 		}
 	}
+	return bindPort
+}
 
-	// Compute the name to give to the frontend.
-	frontendName = frontendNameForServicePort(service, servicePort)
-	// Compute the backend and frontend objects and return them.
-	return &models.V2Frontend{
+// computeFrontendForServicePort computes the frontend that correspond to the specified service port.
+func computeFrontendForServicePort(service *corev1.Service, spec translatorapi.ServiceEdgeLBPoolSpec, servicePort corev1.ServicePort) *models.V2Frontend {
+	// Compute the frontend bind port and the name to give to the frontend.
+	bindPort := computeFrontendBindPortForServicePort(spec, servicePort)
+	frontendName := frontendNameForServicePort(service, servicePort)
+	// Restrict access to the frontend to the source ranges specified on the Service resource (if any).
+	miscStrs := computeEdgeLBFrontendSourceRangesMiscStrs(fmt.Sprintf(serviceAllowedSourcesACLNameFormatString, service.UID), service.Spec.LoadBalancerSourceRanges)
+	// Apply the client-side timeouts for the service port.
+	miscStrs = append(miscStrs, computeEdgeLBFrontendTimeoutMiscStrs(spec.TimeoutsSpecFor(servicePort.Port))...)
+	// Compute the frontend object.
+	res := &models.V2Frontend{
 		BindAddress: constants.EdgeLBFrontendBindAddress,
 		Name:        frontendName,
 		Protocol:    models.V2ProtocolTCP,
@@ -130,7 +201,190 @@ func computeFrontendForServicePort(service *corev1.Service, spec translatorapi.S
 		LinkBackend: &models.V2FrontendLinkBackend{
 			DefaultBackend: backendNameForServicePort(service, servicePort),
 		},
+		MiscStrs: miscStrs,
+	}
+	// Expect client connection information to be passed by the cloud load balancer using the PROXY protocol, if requested.
+	if spec.AcceptProxy != nil && *spec.AcceptProxy {
+		res.BindModifier = edgeLBAcceptProxyBindModifier
+	}
+	// Terminate TLS connections using the certificate reflected from the specified secret, if requested.
+	if tlsSecretName := spec.TLSSecretNameFor(servicePort.Port); tlsSecretName != nil {
+		res.Protocol = models.V2ProtocolTLS
+		res.Certificates = []string{computeEdgeLBSecretFilePath(secretsreflector.ComputeDCOSSecretName(string(service.UID), *tlsSecretName))}
+	}
+	return res
+}
+
+// computeEdgeLBSecretsForService computes the EdgeLB pool secrets holding the certificates used to terminate TLS connections to the service ports of the specified Service resource.
+// It returns nil in case TLS connections are not terminated for any service port.
+func computeEdgeLBSecretsForService(service *corev1.Service, spec translatorapi.ServiceEdgeLBPoolSpec) []*models.V2PoolSecretsItems0 {
+	var res []*models.V2PoolSecretsItems0
+	for _, tlsSecretName := range spec.TLSSecretNames() {
+		dcosSecretName := secretsreflector.ComputeDCOSSecretName(string(service.UID), tlsSecretName)
+		res = append(res, &models.V2PoolSecretsItems0{
+			Secret: dcosSecretName,
+			File:   secretsreflector.ComputeDCOSSecretFileName(dcosSecretName),
+		})
+	}
+	return res
+}
+
+// isEdgeLBSecretOwnedByService indicates whether the specified EdgeLB pool secret has been reflected from a Secret resource used by the specified Service resource.
+func isEdgeLBSecretOwnedByService(secret *models.V2PoolSecretsItems0, service *corev1.Service) bool {
+	return service.UID != "" && strings.HasPrefix(secret.Secret, secretsreflector.ComputeDCOSSecretName(string(service.UID), ""))
+}
+
+// sniFrontendNameForBindPort computes the name of the frontend shared by the service ports routed based on SNI on the specified bind port.
+func sniFrontendNameForBindPort(bindPort int32) string {
+	return fmt.Sprintf("%s%d", serviceSNIFrontendNamePrefix, bindPort)
+}
+
+// isSNIFrontendName indicates whether the specified name is the name of a frontend shared by the service ports routed based on SNI on a given bind port.
+func isSNIFrontendName(name string) bool {
+	return strings.HasPrefix(name, serviceSNIFrontendNamePrefix)
+}
+
+// computeSNIFrontendsForService computes the frontends that route TLS connections to the service ports of the specified Service resource based on SNI.
+// Service ports sharing the same bind port share the same frontend, which only contains the routes to the service ports of the current Service resource.
+// Frontends are returned in the order in which their bind port first appears in the Service resource.
+func computeSNIFrontendsForService(service *corev1.Service, spec translatorapi.ServiceEdgeLBPoolSpec) []*models.V2Frontend {
+	res := make([]*models.V2Frontend, 0)
+	frontends := make(map[int32]*models.V2Frontend)
+	for _, servicePort := range kubernetesutil.SupportedServicePorts(service) {
+		hostnames := spec.SNIHostnamesFor(servicePort.Port)
+		if len(hostnames) == 0 {
+			continue
+		}
+		bindPort := computeFrontendBindPortForServicePort(spec, servicePort)
+		frontend, exists := frontends[bindPort]
+		if !exists {
+			frontend = &models.V2Frontend{
+				BindAddress: constants.EdgeLBFrontendBindAddress,
+				Name:        sniFrontendNameForBindPort(bindPort),
+				Protocol:    models.V2ProtocolTCP,
+				BindPort:    &bindPort,
+				LinkBackend: &models.V2FrontendLinkBackend{},
+				MiscStrs:    []string{edgeLBSNIInspectDelay, edgeLBSNIAcceptClientHello},
+			}
+			frontends[bindPort] = frontend
+			res = append(res, frontend)
+		}
+		// Route TLS connections for the SNI hostnames of the current service port to its backend, restricting access to the source ranges specified on the Service resource (if any).
+		// This will result in an HAProxy config similar to the following one:
+		//
+		// frontend sni:443
+		//    tcp-request inspect-delay 5s
+		//    tcp-request content accept if { req_ssl_hello_type 1 }
+		//    use_backend <cluster-name>:<namespace>:<name>:<port> if { req_ssl_sni -i foo.example.com } { src 10.0.0.0/8 }
+		conditions := []string{fmt.Sprintf(edgeLBSNIConditionFormatString, strings.Join(hostnames, " "))}
+		if len(service.Spec.LoadBalancerSourceRanges) > 0 {
+			conditions = append(conditions, fmt.Sprintf(edgeLBSourceConditionFormatString, computeEdgeLBSourceRanges(service.Spec.LoadBalancerSourceRanges)))
+		}
+		frontend.MiscStrs = append(frontend.MiscStrs, fmt.Sprintf(edgeLBUseBackendFormatString, backendNameForServicePort(service, servicePort), strings.Join(conditions, " ")))
+	}
+	return res
+}
+
+// replaceSNIFrontendRoutesForService replaces the routes to the service ports of the specified Service resource on the specified SNI frontend with the ones contained in the specified desired frontend.
+// A nil desired frontend causes all routes to the service ports of the Service resource to be removed.
+func replaceSNIFrontendRoutesForService(frontend *models.V2Frontend, service *corev1.Service, desired *models.V2Frontend) {
+	var miscStrs []string
+	if desired != nil {
+		for _, m := range desired.MiscStrs {
+			if computeEdgeLBUseBackendMiscStrTarget(m) != "" {
+				miscStrs = append(miscStrs, m)
+			}
+		}
+	}
+	replaceEdgeLBFrontendMiscStrs(frontend, func(m string) bool {
+		target := computeEdgeLBUseBackendMiscStrTarget(m)
+		if target == "" {
+			return false
+		}
+		metadata, err := computeServiceOwnedEdgeLBObjectMetadata(target)
+		return err == nil && metadata.IsOwnedBy(service)
+	}, miscStrs)
+}
+
+// hasSNIFrontendRoutes indicates whether the specified SNI frontend routes TLS connections to at least one service port.
+func hasSNIFrontendRoutes(frontend *models.V2Frontend) bool {
+	for _, m := range frontend.MiscStrs {
+		if computeEdgeLBUseBackendMiscStrTarget(m) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// computeSNIHostnamesFromMiscStr returns the SNI hostnames matched by the specified "use_backend" directive of an SNI frontend.
+// It returns nil in case the specified value does not match TLS connections based on SNI.
+func computeSNIHostnamesFromMiscStr(miscStr string) []string {
+	idx := strings.Index(miscStr, edgeLBSNIConditionPrefix)
+	if idx < 0 {
+		return nil
+	}
+	hostnames := miscStr[idx+len(edgeLBSNIConditionPrefix):]
+	if idx = strings.Index(hostnames, edgeLBSNIConditionSuffix); idx >= 0 {
+		hostnames = hostnames[:idx]
+	}
+	return strings.Fields(hostnames)
+}
+
+// isEdgeLBFrontendOwnedByService indicates whether the specified frontend is owned by the specified Service resource.
+// SNI frontends are considered to be owned by the Service resource in case they only route TLS connections to its service ports.
+func isEdgeLBFrontendOwnedByService(frontend *models.V2Frontend, service *corev1.Service) bool {
+	if !isSNIFrontendName(frontend.Name) {
+		metadata, err := computeServiceOwnedEdgeLBObjectMetadata(frontend.Name)
+		return err == nil && metadata.IsOwnedBy(service)
+	}
+	for _, m := range frontend.MiscStrs {
+		target := computeEdgeLBUseBackendMiscStrTarget(m)
+		if target == "" {
+			continue
+		}
+		if metadata, err := computeServiceOwnedEdgeLBObjectMetadata(target); err != nil || !metadata.IsOwnedBy(service) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkEdgeLBFrontendConflictsForService returns an error in case any of the specified desired frontends for the specified Service resource conflicts with a frontend in the specified pool that is (also) used by other Service resources.
+// Frontends conflict when they bind the same port without both being SNI frontends, or when an SNI frontend already routes TLS connections for any of the desired SNI hostnames to another Service resource.
+// Frontends using a dynamic bind port (i.e. 0) never conflict, as EdgeLB assigns a different port to each of them.
+func checkEdgeLBFrontendConflictsForService(service *corev1.Service, pool *models.V2Pool, desiredFrontends []*models.V2Frontend) error {
+	for _, desired := range desiredFrontends {
+		if *desired.BindPort == 0 {
+			continue
+		}
+		for _, frontend := range pool.Haproxy.Frontends {
+			if frontend.BindPort == nil || *frontend.BindPort != *desired.BindPort || isEdgeLBFrontendOwnedByService(frontend, service) {
+				continue
+			}
+			if !isSNIFrontendName(frontend.Name) || !isSNIFrontendName(desired.Name) {
+				return fmt.Errorf("bind port %d is already used by frontend %q", *desired.BindPort, frontend.Name)
+			}
+			// Both frontends route TLS connections based on SNI, so we must check that each SNI hostname is routed to a single service port.
+			hostnames := make(map[string]string)
+			for _, m := range frontend.MiscStrs {
+				target := computeEdgeLBUseBackendMiscStrTarget(m)
+				if metadata, err := computeServiceOwnedEdgeLBObjectMetadata(target); err == nil && metadata.IsOwnedBy(service) {
+					continue
+				}
+				for _, hostname := range computeSNIHostnamesFromMiscStr(m) {
+					hostnames[strings.ToLower(hostname)] = target
+				}
+			}
+			for _, m := range desired.MiscStrs {
+				for _, hostname := range computeSNIHostnamesFromMiscStr(m) {
+					if target, exists := hostnames[strings.ToLower(hostname)]; exists {
+						return fmt.Errorf("sni hostname %q on bind port %d is already routed to backend %q", hostname, *desired.BindPort, target)
+					}
+				}
+			}
+		}
 	}
+	return nil
 }
 
 // computeServiceOwnedEdgeLBObjectMetadata parses the provided backend/frontend name and returns metadata about the Service resource that owns it.
@@ -0,0 +1,323 @@
+package translator
+
+import (
+	"testing"
+
+	"github.com/mesosphere/dcos-edge-lb/pkg/apis/models"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/mesosphere/dklb/pkg/cluster"
+	translatorapi "github.com/mesosphere/dklb/pkg/translator/api"
+	"github.com/mesosphere/dklb/pkg/util/pointers"
+)
+
+func TestComputeSNIFrontendsForService(t *testing.T) {
+	cluster.Name = "test-cluster"
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-namespace",
+			Name:      "test-service",
+		},
+		Spec: corev1.ServiceSpec{
+			LoadBalancerSourceRanges: []string{"10.0.0.0/8"},
+			Ports: []corev1.ServicePort{
+				{Port: 5432},
+				{Port: 8883},
+				{Port: 9000},
+			},
+		},
+	}
+	spec := translatorapi.ServiceEdgeLBPoolSpec{
+		BaseEdgeLBPoolSpec: translatorapi.BaseEdgeLBPoolSpec{
+			CloudProviderConfiguration: pointers.NewString(""),
+		},
+		Frontends: []translatorapi.ServiceEdgeLBPoolFrontendSpec{
+			{ServicePort: 5432, Port: pointers.NewInt32(443), SNIHostnames: []string{"db.example.com"}},
+			{ServicePort: 8883, Port: pointers.NewInt32(443), SNIHostnames: []string{"mqtt.example.com", "broker.example.com"}},
+			{ServicePort: 9000, Port: pointers.NewInt32(9000)},
+		},
+	}
+
+	// Service ports sharing a bind port must share a single frontend routing to each of them.
+	frontends := computeSNIFrontendsForService(service, spec)
+	assert.Len(t, frontends, 1)
+	assert.Equal(t, "sni:443", frontends[0].Name)
+	assert.Equal(t, int32(443), *frontends[0].BindPort)
+	assert.Equal(t, []string{
+		"tcp-request inspect-delay 5s",
+		"tcp-request content accept if { req_ssl_hello_type 1 }",
+		"use_backend test-cluster:test-namespace:test-service:5432 if { req_ssl_sni -i db.example.com } { src 10.0.0.0/8 }",
+		"use_backend test-cluster:test-namespace:test-service:8883 if { req_ssl_sni -i mqtt.example.com broker.example.com } { src 10.0.0.0/8 }",
+	}, frontends[0].MiscStrs)
+
+	// Only the routes to service ports of the current service must be replaced on a shared frontend.
+	shared := &models.V2Frontend{
+		Name: "sni:443",
+		MiscStrs: []string{
+			"tcp-request inspect-delay 5s",
+			"tcp-request content accept if { req_ssl_hello_type 1 }",
+			"use_backend test-cluster:test-namespace:other-service:443 if { req_ssl_sni -i other.example.com }",
+			"use_backend test-cluster:test-namespace:test-service:5432 if { req_ssl_sni -i old.example.com }",
+		},
+	}
+	replaceSNIFrontendRoutesForService(shared, service, frontends[0])
+	assert.Equal(t, []string{
+		"tcp-request inspect-delay 5s",
+		"tcp-request content accept if { req_ssl_hello_type 1 }",
+		"use_backend test-cluster:test-namespace:other-service:443 if { req_ssl_sni -i other.example.com }",
+		"use_backend test-cluster:test-namespace:test-service:5432 if { req_ssl_sni -i db.example.com } { src 10.0.0.0/8 }",
+		"use_backend test-cluster:test-namespace:test-service:8883 if { req_ssl_sni -i mqtt.example.com broker.example.com } { src 10.0.0.0/8 }",
+	}, shared.MiscStrs)
+	assert.True(t, hasSNIFrontendRoutes(shared))
+
+	// Removing all routes from a frontend must be detected.
+	shared.MiscStrs = shared.MiscStrs[:2]
+	assert.False(t, hasSNIFrontendRoutes(shared))
+}
+
+func TestCheckEdgeLBFrontendConflictsForService(t *testing.T) {
+	cluster.Name = "test-cluster"
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-namespace",
+			Name:      "test-service",
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{Port: 5432},
+				{Port: 9000},
+			},
+		},
+	}
+	spec := translatorapi.ServiceEdgeLBPoolSpec{
+		BaseEdgeLBPoolSpec: translatorapi.BaseEdgeLBPoolSpec{
+			CloudProviderConfiguration: pointers.NewString(""),
+		},
+		Frontends: []translatorapi.ServiceEdgeLBPoolFrontendSpec{
+			{ServicePort: 5432, Port: pointers.NewInt32(443), SNIHostnames: []string{"db.example.com"}},
+			{ServicePort: 9000, Port: pointers.NewInt32(9000)},
+		},
+	}
+	desiredFrontends := append(computeSNIFrontendsForService(service, spec), computeFrontendForServicePort(service, spec, service.Spec.Ports[1]))
+	// A cloud-provider configuration forces a dynamic bind port for every frontend.
+	cloudProviderSpec := spec
+	cloudProviderSpec.CloudProviderConfiguration = pointers.NewString(`{"aws":{"elbs":[]}}`)
+	cloudProviderFrontends := []*models.V2Frontend{computeFrontendForServicePort(service, cloudProviderSpec, service.Spec.Ports[1])}
+	newPool := func(frontends ...*models.V2Frontend) *models.V2Pool {
+		return &models.V2Pool{Haproxy: &models.V2Haproxy{Frontends: frontends}}
+	}
+
+	tests := []struct {
+		description      string
+		pool             *models.V2Pool
+		desiredFrontends []*models.V2Frontend
+		expectedError    bool
+	}{
+		{
+			description: "should accept frontends owned by the current service",
+			pool: newPool(
+				&models.V2Frontend{Name: "test-cluster:test-namespace:test-service:9000", BindPort: pointers.NewInt32(9000)},
+				&models.V2Frontend{Name: "sni:443", BindPort: pointers.NewInt32(443), MiscStrs: []string{
+					"use_backend test-cluster:test-namespace:test-service:5432 if { req_ssl_sni -i old.example.com }",
+				}},
+			),
+		},
+		{
+			description: "should accept an sni frontend routing other sni hostnames to other services",
+			pool: newPool(
+				&models.V2Frontend{Name: "sni:443", BindPort: pointers.NewInt32(443), MiscStrs: []string{
+					"use_backend test-cluster:test-namespace:other-service:443 if { req_ssl_sni -i other.example.com }",
+				}},
+			),
+		},
+		{
+			description: "should reject an sni hostname already routed to another service",
+			pool: newPool(
+				&models.V2Frontend{Name: "sni:443", BindPort: pointers.NewInt32(443), MiscStrs: []string{
+					"use_backend test-cluster:test-namespace:other-service:443 if { req_ssl_sni -i other.example.com DB.example.com } { src 10.0.0.0/8 }",
+				}},
+			),
+			expectedError: true,
+		},
+		{
+			description: "should reject an sni frontend on the bind port of a frontend of another service",
+			pool: newPool(
+				&models.V2Frontend{Name: "test-cluster:test-namespace:other-service:443", BindPort: pointers.NewInt32(443)},
+			),
+			expectedError: true,
+		},
+		{
+			description: "should reject a frontend on the bind port of an sni frontend used by another service",
+			pool: newPool(
+				&models.V2Frontend{Name: "sni:9000", BindPort: pointers.NewInt32(9000), MiscStrs: []string{
+					"use_backend test-cluster:test-namespace:other-service:9000 if { req_ssl_sni -i other.example.com }",
+				}},
+			),
+			expectedError: true,
+		},
+		{
+			description: "should accept frontends using a dynamic bind port alongside frontends of another service",
+			pool: newPool(
+				&models.V2Frontend{Name: "test-cluster:test-namespace:other-service:9000", BindPort: pointers.NewInt32(0)},
+			),
+			desiredFrontends: cloudProviderFrontends,
+		},
+	}
+
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+		if test.desiredFrontends == nil {
+			test.desiredFrontends = desiredFrontends
+		}
+		err := checkEdgeLBFrontendConflictsForService(service, test.pool, test.desiredFrontends)
+		assert.Equal(t, test.expectedError, err != nil)
+	}
+}
+
+func TestComputeFrontendForServicePort_tls(t *testing.T) {
+	cluster.Name = "test-cluster"
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-namespace",
+			Name:      "test-service",
+			UID:       "test-uid",
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{Port: 5432},
+				{Port: 9000},
+			},
+		},
+	}
+	spec := translatorapi.ServiceEdgeLBPoolSpec{
+		BaseEdgeLBPoolSpec: translatorapi.BaseEdgeLBPoolSpec{
+			CloudProviderConfiguration: pointers.NewString(""),
+		},
+		Frontends: []translatorapi.ServiceEdgeLBPoolFrontendSpec{
+			{ServicePort: 5432, Port: pointers.NewInt32(5432), TLSSecretName: pointers.NewString("test-tls")},
+			{ServicePort: 9000, Port: pointers.NewInt32(9000)},
+		},
+	}
+
+	// TLS connections must be terminated using the reflected certificate on the frontend for which a secret has been specified.
+	frontend := computeFrontendForServicePort(service, spec, service.Spec.Ports[0])
+	assert.Equal(t, models.V2ProtocolTLS, frontend.Protocol)
+	assert.Equal(t, []string{"$SECRETS/test-uid__test-tls"}, frontend.Certificates)
+	// Other frontends must be left as plain TCP frontends.
+	frontend = computeFrontendForServicePort(service, spec, service.Spec.Ports[1])
+	assert.Equal(t, models.V2ProtocolTCP, frontend.Protocol)
+	assert.Nil(t, frontend.Certificates)
+	// The reflected certificate must be added to the pool's secrets.
+	secrets := computeEdgeLBSecretsForService(service, spec)
+	assert.Equal(t, []*models.V2PoolSecretsItems0{
+		{
+			Secret: "test-uid__test-tls",
+			File:   "test-uid__test-tls",
+		},
+	}, secrets)
+	assert.True(t, isEdgeLBSecretOwnedByService(secrets[0], service))
+	assert.False(t, isEdgeLBSecretOwnedByService(&models.V2PoolSecretsItems0{Secret: "other-uid__test-tls"}, service))
+}
+
+func TestComputeBackendForServicePort_podIP(t *testing.T) {
+	cluster.Name = "test-cluster"
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-namespace",
+			Name:      "test-service",
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{Port: 80, NodePort: 30080},
+			},
+		},
+	}
+	endpoints := &corev1.Endpoints{
+		Subsets: []corev1.EndpointSubset{
+			{
+				Addresses: []corev1.EndpointAddress{
+					{IP: "9.0.0.1"},
+				},
+				Ports: []corev1.EndpointPort{
+					{Port: 8080},
+				},
+			},
+		},
+	}
+	spec := translatorapi.ServiceEdgeLBPoolSpec{
+		BaseEdgeLBPoolSpec: translatorapi.BaseEdgeLBPoolSpec{
+			CloudProviderConfiguration: pointers.NewString(""),
+			RoutingMode:                pointers.NewString(translatorapi.EdgeLBPoolRoutingModePodIP),
+		},
+		Frontends: []translatorapi.ServiceEdgeLBPoolFrontendSpec{
+			{ServicePort: 80, Port: pointers.NewInt32(80)},
+		},
+	}
+
+	// The backend must target the ready pods directly.
+	backend := computeBackendForServicePort(service, spec, service.Spec.Ports[0], endpoints)
+	assert.Len(t, backend.Services, 1)
+	assert.Equal(t, models.V2EndpointTypeADDRESS, backend.Services[0].Endpoint.Type)
+	assert.Equal(t, "9.0.0.1", backend.Services[0].Endpoint.Address)
+	assert.Equal(t, int32(8080), backend.Services[0].Endpoint.Port)
+	// The backend must target the node port when routing to pod ips has not been requested.
+	spec.RoutingMode = pointers.NewString(translatorapi.EdgeLBPoolRoutingModeNodePort)
+	backend = computeBackendForServicePort(service, spec, service.Spec.Ports[0], endpoints)
+	assert.Len(t, backend.Services, 1)
+	assert.Equal(t, models.V2EndpointTypeCONTAINERIP, backend.Services[0].Endpoint.Type)
+	assert.Equal(t, int32(30080), backend.Services[0].Endpoint.Port)
+}
+
+func TestComputeBackendForServicePort_externalTrafficPolicyLocal(t *testing.T) {
+	cluster.Name = "test-cluster"
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-namespace",
+			Name:      "test-service",
+		},
+		Spec: corev1.ServiceSpec{
+			ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal,
+			HealthCheckNodePort:   32000,
+			Ports: []corev1.ServicePort{
+				{Port: 80, NodePort: 30080},
+			},
+		},
+	}
+	spec := translatorapi.ServiceEdgeLBPoolSpec{
+		BaseEdgeLBPoolSpec: translatorapi.BaseEdgeLBPoolSpec{
+			CloudProviderConfiguration: pointers.NewString(""),
+			RoutingMode:                pointers.NewString(translatorapi.EdgeLBPoolRoutingModeNodePort),
+		},
+		Frontends: []translatorapi.ServiceEdgeLBPoolFrontendSpec{
+			{
+				Backend: &translatorapi.BaseEdgeLBPoolBackendSpec{
+					HealthCheck: &translatorapi.EdgeLBBackendHealthCheckSpec{
+						Interval: pointers.NewString("2s"),
+					},
+				},
+				ServicePort: 80,
+				Port:        pointers.NewInt32(80),
+			},
+		},
+	}
+
+	// The backend must health-check the health-check node port, preserving the health-check interval.
+	backend := computeBackendForServicePort(service, spec, service.Spec.Ports[0], nil)
+	assert.Equal(t, &models.V2BackendCustomCheck{Httpchk: true, HttpchkMiscStr: "GET /healthz"}, backend.CustomCheck)
+	assert.Len(t, backend.Services, 1)
+	assert.Equal(t, int32(30080), backend.Services[0].Endpoint.Port)
+	assert.Equal(t, "inter 2000ms port 32000", backend.Services[0].Endpoint.Check.CustomStr)
+	// The backend must be left untouched when the external traffic policy is "Cluster".
+	service.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyTypeCluster
+	service.Spec.HealthCheckNodePort = 0
+	backend = computeBackendForServicePort(service, spec, service.Spec.Ports[0], nil)
+	assert.Nil(t, backend.CustomCheck)
+	assert.Equal(t, "inter 2000ms", backend.Services[0].Endpoint.Check.CustomStr)
+}
@@ -7,7 +7,7 @@ import (
 
 	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
-	extsv1beta1 "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/mesosphere/dcos-edge-lb/pkg/apis/models"
@@ -51,7 +51,7 @@ func computeLoadBalancerStatus(manager manager.EdgeLBManager, poolName string, o
 					case *corev1.Service:
 						m, err := computeServiceOwnedEdgeLBObjectMetadata(*listener.LinkFrontend)
 						isOwnedByObj = err == nil && m.IsOwnedBy(t)
-					case *extsv1beta1.Ingress:
+					case *networkingv1.Ingress:
 						m := computeIngressOwnedEdgeLBObjectMetadata(*listener.LinkFrontend)
 						isOwnedByObj = m.IsOwnedBy(t)
 					default:
@@ -75,21 +75,21 @@ func computeLoadBalancerStatus(manager manager.EdgeLBManager, poolName string, o
 		case *corev1.Service:
 			m, err := computeServiceOwnedEdgeLBObjectMetadata(frontend.Name)
 			isOwnedByObj = err == nil && m.IsOwnedBy(t)
-		case *extsv1beta1.Ingress:
+		case *networkingv1.Ingress:
 			m := computeIngressOwnedEdgeLBObjectMetadata(frontend.Name)
 			isOwnedByObj = m.IsOwnedBy(t)
-			if !isOwnedByObj && desiredFrontends != nil {
-				// we might be sharing the pool so we need to check ...
-				for _, f := range desiredFrontends {
-					if f.Name == frontend.Name {
-						isOwnedByObj = true
-						break
-					}
-				}
-			}
 		default:
 			return nil
 		}
+		if !isOwnedByObj && desiredFrontends != nil {
+			// we might be sharing the frontend so we need to check ...
+			for _, f := range desiredFrontends {
+				if f.Name == frontend.Name {
+					isOwnedByObj = true
+					break
+				}
+			}
+		}
 		// If the current frontend doesn't belong to the Service/Ingress resource being processed, we skip it.
 		if !isOwnedByObj {
 			continue
@@ -1,16 +1,20 @@
 package kubernetes
 
 import (
-	extsv1beta1 "k8s.io/api/extensions/v1beta1"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 
 	"github.com/mesosphere/dklb/pkg/constants"
 )
 
 // ForEachIngressBackend iterates over Ingress backends defined on the specified Ingress resource, calling "fn" with each Ingress backend object and the associated host and path whenever applicable.
-func ForEachIngresBackend(ingress *extsv1beta1.Ingress, fn func(host *string, path *string, backend extsv1beta1.IngressBackend)) {
-	if ingress.Spec.Backend != nil {
+// Only Ingress backends targeting a Service resource are considered, as "resource" Ingress backends cannot be provisioned using EdgeLB.
+func ForEachIngresBackend(ingress *networkingv1.Ingress, fn func(host *string, path *networkingv1.HTTPIngressPath, backend networkingv1.IngressServiceBackend)) {
+	if ingress.Spec.DefaultBackend != nil && ingress.Spec.DefaultBackend.Service != nil {
 		// Use nil values for "host" and "path" so that the caller can identify the current Ingress backend as the default one if it needs to.
-		fn(nil, nil, *ingress.Spec.Backend)
+		fn(nil, nil, *ingress.Spec.DefaultBackend.Service)
 	}
 	for _, rule := range ingress.Spec.Rules {
 		// Pin "rule" so we can take its address.
@@ -19,20 +23,103 @@ func ForEachIngresBackend(ingress *extsv1beta1.Ingress, fn func(host *string, pa
 			for _, path := range rule.HTTP.Paths {
 				// Pin "path" so we can take its address.
 				path := path
+				if path.Backend.Service == nil {
+					continue
+				}
 				// Use the specified (possibly empty) values for "host" and "path".
-				fn(&rule.Host, &path.Path, path.Backend)
+				fn(&rule.Host, &path, *path.Backend.Service)
 			}
 		}
 	}
 }
 
+// ForEachIngressResourceBackend iterates over the "resource" Ingress backends defined on the specified Ingress resource, calling "fn" with each referenced object.
+func ForEachIngressResourceBackend(ingress *networkingv1.Ingress, fn func(resource corev1.TypedLocalObjectReference)) {
+	if ingress.Spec.DefaultBackend != nil && ingress.Spec.DefaultBackend.Resource != nil {
+		fn(*ingress.Spec.DefaultBackend.Resource)
+	}
+	for _, rule := range ingress.Spec.Rules {
+		if rule.HTTP != nil {
+			for _, path := range rule.HTTP.Paths {
+				if path.Backend.Resource != nil {
+					fn(*path.Backend.Resource)
+				}
+			}
+		}
+	}
+}
+
+// IngressBackendPaths returns the (unique, non-empty) paths through which the specified Ingress backend is referenced in the specified Ingress resource.
+func IngressBackendPaths(ingress *networkingv1.Ingress, backend networkingv1.IngressServiceBackend) []string {
+	res := make([]string, 0)
+	seen := make(map[string]bool)
+	ForEachIngresBackend(ingress, func(_ *string, path *networkingv1.HTTPIngressPath, b networkingv1.IngressServiceBackend) {
+		if b != backend || path == nil || path.Path == "" || seen[path.Path] {
+			return
+		}
+		seen[path.Path] = true
+		res = append(res, path.Path)
+	})
+	return res
+}
+
+// IngressServiceBackendPort returns the string representation (i.e. the name or the number) of the service port targeted by the specified Ingress backend.
+func IngressServiceBackendPort(backend networkingv1.IngressServiceBackend) string {
+	if backend.Port.Name != "" {
+		return backend.Port.Name
+	}
+	return strconv.Itoa(int(backend.Port.Number))
+}
+
+// NewIngressServiceBackend returns the Ingress backend that targets the specified service port (name or number) of the Service resource with the specified name.
+func NewIngressServiceBackend(serviceName, servicePort string) networkingv1.IngressServiceBackend {
+	res := networkingv1.IngressServiceBackend{
+		Name: serviceName,
+	}
+	// Port names must contain at least one letter, so a string that can be parsed as an integer is always a port number.
+	if v, err := strconv.ParseInt(servicePort, 10, 32); err == nil {
+		res.Port.Number = int32(v)
+	} else {
+		res.Port.Name = servicePort
+	}
+	return res
+}
+
 // IsEdgeLBIngress returns a value indicating whether the specified Ingress resource is meant to be provisioned by EdgeLB.
-func IsEdgeLBIngress(ingress *extsv1beta1.Ingress) bool {
-	// If the required annotation is not present, return false.
-	v, exists := ingress.Annotations[constants.EdgeLBIngressClassAnnotationKey]
-	if !exists {
-		return false
-	}
-	// Return whether the value of the annotation matches the expected one.
-	return v == constants.EdgeLBIngressClassAnnotationValue
+// An Ingress resource is meant to be provisioned by EdgeLB if it has the "kubernetes.io/ingress.class" annotation set to "edgelb", or otherwise if the IngressClass resource it references (or the default one, in case it doesn't reference any) is handled by EdgeLB.
+// The specified IngressClass resources are the ones against which ".spec.ingressClassName" is resolved.
+func IsEdgeLBIngress(ingress *networkingv1.Ingress, ingressClasses []*networkingv1.IngressClass) bool {
+	// The (deprecated) annotation takes precedence over ".spec.ingressClassName" whenever it is present.
+	if v, exists := ingress.Annotations[constants.EdgeLBIngressClassAnnotationKey]; exists {
+		return v == constants.EdgeLBIngressClassAnnotationValue
+	}
+	for _, ingressClass := range ingressClasses {
+		if ingressClass.Spec.Controller != constants.EdgeLBIngressControllerName {
+			continue
+		}
+		if ingress.Spec.IngressClassName != nil && *ingress.Spec.IngressClassName == ingressClass.Name {
+			return true
+		}
+		if ingress.Spec.IngressClassName == nil && IsDefaultIngressClass(ingressClass) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDefaultIngressClass returns a value indicating whether the specified IngressClass resource is marked as the default one.
+func IsDefaultIngressClass(ingressClass *networkingv1.IngressClass) bool {
+	return ingressClass.Annotations[networkingv1.AnnotationIsDefaultIngressClass] == strconv.FormatBool(true)
+}
+
+// IngressLoadBalancerStatus converts the specified "LoadBalancerStatus" object into the equivalent object used to report the status of Ingress resources.
+func IngressLoadBalancerStatus(status corev1.LoadBalancerStatus) networkingv1.IngressLoadBalancerStatus {
+	res := networkingv1.IngressLoadBalancerStatus{}
+	for _, ingress := range status.Ingress {
+		res.Ingress = append(res.Ingress, networkingv1.IngressLoadBalancerIngress{
+			IP:       ingress.IP,
+			Hostname: ingress.Hostname,
+		})
+	}
+	return res
 }
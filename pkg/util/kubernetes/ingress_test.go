@@ -4,36 +4,44 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
-	extsv1beta1 "k8s.io/api/extensions/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/mesosphere/dklb/pkg/constants"
 	"github.com/mesosphere/dklb/pkg/util/kubernetes"
+	"github.com/mesosphere/dklb/pkg/util/pointers"
 	ingresstestutil "github.com/mesosphere/dklb/test/util/kubernetes/ingress"
 )
 
 // TestForEachIngressBackend tests the "ForEachIngressBackend" function.
 func TestForEachIngressBackend(t *testing.T) {
-	ingress := ingresstestutil.DummyEdgeLBIngressResource("foo", "bar", func(ingress *extsv1beta1.Ingress) {
-		ingress.Spec.Backend = &extsv1beta1.IngressBackend{
-			ServiceName: "1",
+	ingress := ingresstestutil.DummyEdgeLBIngressResource("foo", "bar", func(ingress *networkingv1.Ingress) {
+		ingress.Spec.DefaultBackend = &networkingv1.IngressBackend{
+			Service: &networkingv1.IngressServiceBackend{Name: "1"},
 		}
-		ingress.Spec.Rules = []extsv1beta1.IngressRule{
+		ingress.Spec.Rules = []networkingv1.IngressRule{
 			{
 				Host: "foo.bar",
-				IngressRuleValue: extsv1beta1.IngressRuleValue{
-					HTTP: &extsv1beta1.HTTPIngressRuleValue{
-						Paths: []extsv1beta1.HTTPIngressPath{
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{
 							{
 								Path: "/foo",
-								Backend: extsv1beta1.IngressBackend{
-									ServiceName: "2",
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{Name: "2"},
 								},
 							},
 							{
 								Path: "/bar",
-								Backend: extsv1beta1.IngressBackend{
-									ServiceName: "3",
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{Name: "3"},
+								},
+							},
+							{
+								Path: "/qux",
+								Backend: networkingv1.IngressBackend{
+									Resource: &corev1.TypedLocalObjectReference{Kind: "Bucket", Name: "5"},
 								},
 							},
 						},
@@ -42,13 +50,13 @@ func TestForEachIngressBackend(t *testing.T) {
 			},
 			{
 				Host: "bar.baz",
-				IngressRuleValue: extsv1beta1.IngressRuleValue{
-					HTTP: &extsv1beta1.HTTPIngressRuleValue{
-						Paths: []extsv1beta1.HTTPIngressPath{
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{
 							{
 								Path: "/baz",
-								Backend: extsv1beta1.IngressBackend{
-									ServiceName: "4",
+								Backend: networkingv1.IngressBackend{
+									Service: &networkingv1.IngressServiceBackend{Name: "4"},
 								},
 							},
 						},
@@ -60,13 +68,13 @@ func TestForEachIngressBackend(t *testing.T) {
 
 	// Visit each Ingress backend, adding the corresponding host and path to "visitedHosts" and "visitedPaths", respectively, having the target service's name as the key.
 	visitedHosts := make(map[string]*string)
-	visitedPaths := make(map[string]*string)
-	kubernetes.ForEachIngresBackend(ingress, func(host *string, path *string, backend extsv1beta1.IngressBackend) {
-		visitedHosts[backend.ServiceName] = host
-		visitedPaths[backend.ServiceName] = path
+	visitedPaths := make(map[string]*networkingv1.HTTPIngressPath)
+	kubernetes.ForEachIngresBackend(ingress, func(host *string, path *networkingv1.HTTPIngressPath, backend networkingv1.IngressServiceBackend) {
+		visitedHosts[backend.Name] = host
+		visitedPaths[backend.Name] = path
 	})
 
-	// Make sure that all Ingress backends have been visited.
+	// Make sure that all Ingress backends targeting a Service resource have been visited.
 	assert.Equal(t, 4, len(visitedHosts))
 	assert.Equal(t, 4, len(visitedPaths))
 
@@ -78,20 +86,104 @@ func TestForEachIngressBackend(t *testing.T) {
 
 	// Make sure that all Ingress backends have been visited with the correct path.
 	assert.Nil(t, visitedPaths["1"])
-	assert.Equal(t, "/foo", *visitedPaths["2"])
-	assert.Equal(t, "/bar", *visitedPaths["3"])
-	assert.Equal(t, "/baz", *visitedPaths["4"])
+	assert.Equal(t, "/foo", visitedPaths["2"].Path)
+	assert.Equal(t, "/bar", visitedPaths["3"].Path)
+	assert.Equal(t, "/baz", visitedPaths["4"].Path)
+
+	// Make sure that the "resource" Ingress backend has been visited separately.
+	visitedResources := make([]string, 0)
+	kubernetes.ForEachIngressResourceBackend(ingress, func(resource corev1.TypedLocalObjectReference) {
+		visitedResources = append(visitedResources, resource.Name)
+	})
+	assert.Equal(t, []string{"5"}, visitedResources)
+}
+
+// TestIngressBackendPaths tests the "IngressBackendPaths" function.
+func TestIngressBackendPaths(t *testing.T) {
+	backend := networkingv1.IngressServiceBackend{
+		Name: "1",
+	}
+	ingress := ingresstestutil.DummyEdgeLBIngressResource("foo", "bar", func(ingress *networkingv1.Ingress) {
+		ingress.Spec.DefaultBackend = &networkingv1.IngressBackend{Service: &backend}
+		ingress.Spec.Rules = []networkingv1.IngressRule{
+			{
+				Host: "foo.bar",
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{
+							{Path: "/foo", Backend: networkingv1.IngressBackend{Service: &backend}},
+							{Path: "/bar", Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "2"}}},
+						},
+					},
+				},
+			},
+			{
+				Host: "bar.baz",
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{
+							{Path: "/foo", Backend: networkingv1.IngressBackend{Service: &backend}},
+							{Path: "", Backend: networkingv1.IngressBackend{Service: &backend}},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	assert.Equal(t, []string{"/foo"}, kubernetes.IngressBackendPaths(ingress, backend))
+	assert.Equal(t, []string{"/bar"}, kubernetes.IngressBackendPaths(ingress, networkingv1.IngressServiceBackend{Name: "2"}))
+}
+
+// TestIngressServiceBackendPort tests the "IngressServiceBackendPort" and "NewIngressServiceBackend" functions.
+func TestIngressServiceBackendPort(t *testing.T) {
+	tests := []struct {
+		description string
+		servicePort string
+		backend     networkingv1.IngressServiceBackend
+	}{
+		{
+			description: "port number",
+			servicePort: "80",
+			backend:     networkingv1.IngressServiceBackend{Name: "foo", Port: networkingv1.ServiceBackendPort{Number: 80}},
+		},
+		{
+			description: "port name",
+			servicePort: "http",
+			backend:     networkingv1.IngressServiceBackend{Name: "foo", Port: networkingv1.ServiceBackendPort{Name: "http"}},
+		},
+	}
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+		assert.Equal(t, test.servicePort, kubernetes.IngressServiceBackendPort(test.backend))
+		assert.Equal(t, test.backend, kubernetes.NewIngressServiceBackend("foo", test.servicePort))
+	}
 }
 
 func TestIsEdgeLBIngress(t *testing.T) {
+	edgelbIngressClass := ingresstestutil.DummyEdgeLBIngressClassResource("edgelb-class")
+	defaultEdgeLBIngressClass := ingresstestutil.DummyEdgeLBIngressClassResource("default-edgelb-class")
+	defaultEdgeLBIngressClass.Annotations = map[string]string{
+		networkingv1.AnnotationIsDefaultIngressClass: "true",
+	}
+	otherIngressClass := &networkingv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "other-class",
+		},
+		Spec: networkingv1.IngressClassSpec{
+			Controller: "k8s.io/ingress-nginx",
+		},
+	}
+
 	tests := []struct {
 		description    string
 		expectedResult bool
-		ingress        *extsv1beta1.Ingress
+		ingress        *networkingv1.Ingress
+		ingressClasses []*networkingv1.IngressClass
 	}{
 		{
 			description: "should detect ingress type dklb",
-			ingress: &extsv1beta1.Ingress{
+			ingress: &networkingv1.Ingress{
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{
 						constants.EdgeLBIngressClassAnnotationKey: constants.EdgeLBIngressClassAnnotationValue,
@@ -102,25 +194,68 @@ func TestIsEdgeLBIngress(t *testing.T) {
 		},
 		{
 			description:    "should not detect ingress type dklb with missing annotation",
-			ingress:        &extsv1beta1.Ingress{},
+			ingress:        &networkingv1.Ingress{},
 			expectedResult: false,
 		},
 		{
 			description: "should not detect ingress type dklb with other ingress type",
-			ingress: &extsv1beta1.Ingress{
+			ingress: &networkingv1.Ingress{
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{
 						constants.EdgeLBIngressClassAnnotationKey: "nginx",
 					},
 				},
 			},
+			ingressClasses: []*networkingv1.IngressClass{defaultEdgeLBIngressClass},
+			expectedResult: false,
+		},
+		{
+			description: "should detect ingress referencing an ingress class handled by dklb",
+			ingress: &networkingv1.Ingress{
+				Spec: networkingv1.IngressSpec{
+					IngressClassName: pointers.NewString(edgelbIngressClass.Name),
+				},
+			},
+			ingressClasses: []*networkingv1.IngressClass{otherIngressClass, edgelbIngressClass},
+			expectedResult: true,
+		},
+		{
+			description: "should not detect ingress referencing an ingress class handled by another controller",
+			ingress: &networkingv1.Ingress{
+				Spec: networkingv1.IngressSpec{
+					IngressClassName: pointers.NewString(otherIngressClass.Name),
+				},
+			},
+			ingressClasses: []*networkingv1.IngressClass{otherIngressClass, edgelbIngressClass},
+			expectedResult: false,
+		},
+		{
+			description: "should not detect ingress referencing a missing ingress class",
+			ingress: &networkingv1.Ingress{
+				Spec: networkingv1.IngressSpec{
+					IngressClassName: pointers.NewString("missing-class"),
+				},
+			},
+			ingressClasses: []*networkingv1.IngressClass{defaultEdgeLBIngressClass},
+			expectedResult: false,
+		},
+		{
+			description:    "should detect ingress without an ingress class when the default ingress class is handled by dklb",
+			ingress:        &networkingv1.Ingress{},
+			ingressClasses: []*networkingv1.IngressClass{otherIngressClass, defaultEdgeLBIngressClass},
+			expectedResult: true,
+		},
+		{
+			description:    "should not detect ingress without an ingress class when no default ingress class is handled by dklb",
+			ingress:        &networkingv1.Ingress{},
+			ingressClasses: []*networkingv1.IngressClass{otherIngressClass, edgelbIngressClass},
 			expectedResult: false,
 		},
 	}
 
 	for _, test := range tests {
 		t.Logf("test case: %s", test.description)
-		res := kubernetes.IsEdgeLBIngress(test.ingress)
+		res := kubernetes.IsEdgeLBIngress(test.ingress, test.ingressClasses)
 		assert.Equal(t, test.expectedResult, res)
 	}
 }
@@ -1,6 +1,7 @@
 package kubernetes
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/cache"
 )
 
@@ -13,3 +14,39 @@ func Key(obj interface{}) string {
 	}
 	return res
 }
+
+// HasFinalizer returns a value indicating whether the specified Kubernetes API resource has the specified finalizer.
+func HasFinalizer(obj metav1.Object, finalizer string) bool {
+	for _, f := range obj.GetFinalizers() {
+		if f == finalizer {
+			return true
+		}
+	}
+	return false
+}
+
+// AddFinalizer adds the specified finalizer to the specified Kubernetes API resource in case it is not present yet.
+// It returns a value indicating whether the Kubernetes API resource was modified.
+func AddFinalizer(obj metav1.Object, finalizer string) bool {
+	if HasFinalizer(obj, finalizer) {
+		return false
+	}
+	obj.SetFinalizers(append(obj.GetFinalizers(), finalizer))
+	return true
+}
+
+// RemoveFinalizer removes the specified finalizer from the specified Kubernetes API resource in case it is present.
+// It returns a value indicating whether the Kubernetes API resource was modified.
+func RemoveFinalizer(obj metav1.Object, finalizer string) bool {
+	if !HasFinalizer(obj, finalizer) {
+		return false
+	}
+	res := make([]string, 0, len(obj.GetFinalizers())-1)
+	for _, f := range obj.GetFinalizers() {
+		if f != finalizer {
+			res = append(res, f)
+		}
+	}
+	obj.SetFinalizers(res)
+	return true
+}
@@ -48,3 +48,23 @@ func TestKey(t *testing.T) {
 		assert.Equal(t, test.output, kubernetes.Key(test.input))
 	}
 }
+
+// TestAddRemoveFinalizer tests the "AddFinalizer", "HasFinalizer" and "RemoveFinalizer" functions.
+func TestAddRemoveFinalizer(t *testing.T) {
+	obj := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Finalizers: []string{"foo"},
+			Namespace:  "foo",
+			Name:       "bar",
+		},
+	}
+	assert.False(t, kubernetes.HasFinalizer(obj, "bar"))
+	assert.True(t, kubernetes.AddFinalizer(obj, "bar"))
+	assert.False(t, kubernetes.AddFinalizer(obj, "bar"))
+	assert.True(t, kubernetes.HasFinalizer(obj, "bar"))
+	assert.Equal(t, []string{"foo", "bar"}, obj.Finalizers)
+	assert.True(t, kubernetes.RemoveFinalizer(obj, "bar"))
+	assert.False(t, kubernetes.RemoveFinalizer(obj, "bar"))
+	assert.False(t, kubernetes.HasFinalizer(obj, "bar"))
+	assert.Equal(t, []string{"foo"}, obj.Finalizers)
+}
@@ -0,0 +1,73 @@
+package kubernetes
+
+import (
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/mesosphere/dklb/pkg/constants"
+)
+
+var (
+	// LoadBalancerClass is the load balancer class that identifies Service resources meant to be provisioned using EdgeLB.
+	LoadBalancerClass = constants.DefaultLoadBalancerClass
+	// ProvisionUnclassifiedServices indicates whether Service resources of type "LoadBalancer" that don't specify a load balancer class are meant to be provisioned using EdgeLB.
+	ProvisionUnclassifiedServices = true
+)
+
+// IsEdgeLBService returns a value indicating whether the specified Service resource is meant to be provisioned by EdgeLB.
+// Service resources of type "LoadBalancer" are provisioned by EdgeLB unless they specify a load balancer class other than the one configured for dklb (or no load balancer class at all, if so configured).
+// Service resources of type "NodePort" are provisioned by EdgeLB only if they explicitly opt in to it, and unless they specify a load balancer class other than the one configured for dklb.
+func IsEdgeLBService(service *corev1.Service) bool {
+	switch service.Spec.Type {
+	case corev1.ServiceTypeLoadBalancer:
+	case corev1.ServiceTypeNodePort:
+		if service.Annotations[constants.DklbExposeAnnotationKey] != strconv.FormatBool(true) {
+			return false
+		}
+	default:
+		return false
+	}
+	// If a load balancer class has been specified, return whether it matches the expected one.
+	if v, exists := service.Annotations[constants.EdgeLBLoadBalancerClassAnnotationKey]; exists {
+		return v == LoadBalancerClass
+	}
+	// Service resources of type "NodePort" have explicitly opted in to being provisioned by EdgeLB.
+	return service.Spec.Type == corev1.ServiceTypeNodePort || ProvisionUnclassifiedServices
+}
+
+// IsSupportedServicePort returns a value indicating whether the specified service port can be exposed by EdgeLB.
+// EdgeLB is backed by HAProxy, which only supports TCP-based protocols.
+func IsSupportedServicePort(port corev1.ServicePort) bool {
+	return port.Protocol == "" || port.Protocol == corev1.ProtocolTCP
+}
+
+// SupportedServicePorts returns the service ports defined on the specified Service resource that can be exposed by EdgeLB.
+func SupportedServicePorts(service *corev1.Service) []corev1.ServicePort {
+	res := make([]corev1.ServicePort, 0, len(service.Spec.Ports))
+	for _, port := range service.Spec.Ports {
+		if IsSupportedServicePort(port) {
+			res = append(res, port)
+		}
+	}
+	return res
+}
+
+// UnsupportedServicePorts returns the service ports defined on the specified Service resource that cannot be exposed by EdgeLB.
+// The second return value holds the subset of said service ports whose port number is not shared with a service port that can be exposed by EdgeLB (e.g. a UDP port that is not accompanied by a TCP port with the same port number).
+func UnsupportedServicePorts(service *corev1.Service) (unsupported, unmatched []corev1.ServicePort) {
+	supported := make(map[int32]bool, len(service.Spec.Ports))
+	for _, port := range SupportedServicePorts(service) {
+		supported[port.Port] = true
+	}
+	for _, port := range service.Spec.Ports {
+		if IsSupportedServicePort(port) {
+			continue
+		}
+		unsupported = append(unsupported, port)
+		if !supported[port.Port] {
+			unmatched = append(unmatched, port)
+		}
+	}
+	return unsupported, unmatched
+}
@@ -0,0 +1,122 @@
+package kubernetes_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/mesosphere/dklb/pkg/constants"
+	"github.com/mesosphere/dklb/pkg/util/kubernetes"
+)
+
+// TestSupportedServicePorts tests the "SupportedServicePorts" and "UnsupportedServicePorts" functions.
+func TestSupportedServicePorts(t *testing.T) {
+	service := &corev1.Service{
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{Name: "dns-tcp", Port: 53, Protocol: corev1.ProtocolTCP},
+				{Name: "dns-udp", Port: 53, Protocol: corev1.ProtocolUDP},
+				{Name: "syslog", Port: 514, Protocol: corev1.ProtocolUDP},
+				{Name: "http", Port: 80},
+			},
+		},
+	}
+	assert.Equal(t, []corev1.ServicePort{service.Spec.Ports[0], service.Spec.Ports[3]}, kubernetes.SupportedServicePorts(service))
+	unsupported, unmatched := kubernetes.UnsupportedServicePorts(service)
+	assert.Equal(t, []corev1.ServicePort{service.Spec.Ports[1], service.Spec.Ports[2]}, unsupported)
+	assert.Equal(t, []corev1.ServicePort{service.Spec.Ports[2]}, unmatched)
+}
+
+// TestIsEdgeLBService tests the "IsEdgeLBService" function.
+func TestIsEdgeLBService(t *testing.T) {
+	tests := []struct {
+		description                   string
+		serviceType                   corev1.ServiceType
+		annotations                   map[string]string
+		provisionUnclassifiedServices bool
+		expectedResult                bool
+	}{
+		{
+			description:                   "service of type LoadBalancer with no load balancer class",
+			serviceType:                   corev1.ServiceTypeLoadBalancer,
+			provisionUnclassifiedServices: true,
+			expectedResult:                true,
+		},
+		{
+			description:                   "service of type LoadBalancer with no load balancer class when unclassified services are not provisioned",
+			serviceType:                   corev1.ServiceTypeLoadBalancer,
+			provisionUnclassifiedServices: false,
+			expectedResult:                false,
+		},
+		{
+			description: "service of type LoadBalancer with the expected load balancer class",
+			serviceType: corev1.ServiceTypeLoadBalancer,
+			annotations: map[string]string{
+				constants.EdgeLBLoadBalancerClassAnnotationKey: constants.DefaultLoadBalancerClass,
+			},
+			provisionUnclassifiedServices: false,
+			expectedResult:                true,
+		},
+		{
+			description: "service of type LoadBalancer with a different load balancer class",
+			serviceType: corev1.ServiceTypeLoadBalancer,
+			annotations: map[string]string{
+				constants.EdgeLBLoadBalancerClassAnnotationKey: "other",
+			},
+			provisionUnclassifiedServices: true,
+			expectedResult:                false,
+		},
+		{
+			description:                   "service of type NodePort without opting in",
+			serviceType:                   corev1.ServiceTypeNodePort,
+			provisionUnclassifiedServices: true,
+			expectedResult:                false,
+		},
+		{
+			description: "service of type NodePort opting in",
+			serviceType: corev1.ServiceTypeNodePort,
+			annotations: map[string]string{
+				constants.DklbExposeAnnotationKey: "true",
+			},
+			provisionUnclassifiedServices: false,
+			expectedResult:                true,
+		},
+		{
+			description: "service of type NodePort opting in with a different load balancer class",
+			serviceType: corev1.ServiceTypeNodePort,
+			annotations: map[string]string{
+				constants.DklbExposeAnnotationKey:              "true",
+				constants.EdgeLBLoadBalancerClassAnnotationKey: "other",
+			},
+			provisionUnclassifiedServices: true,
+			expectedResult:                false,
+		},
+		{
+			description: "service of type ClusterIP opting in",
+			serviceType: corev1.ServiceTypeClusterIP,
+			annotations: map[string]string{
+				constants.DklbExposeAnnotationKey: "true",
+			},
+			provisionUnclassifiedServices: true,
+			expectedResult:                false,
+		},
+	}
+	defer func() {
+		kubernetes.ProvisionUnclassifiedServices = true
+	}()
+	for _, test := range tests {
+		t.Logf("test case: %s", test.description)
+		kubernetes.ProvisionUnclassifiedServices = test.provisionUnclassifiedServices
+		service := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: test.annotations,
+			},
+			Spec: corev1.ServiceSpec{
+				Type: test.serviceType,
+			},
+		}
+		assert.Equal(t, test.expectedResult, kubernetes.IsEdgeLBService(service))
+	}
+}
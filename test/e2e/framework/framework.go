@@ -59,7 +59,7 @@ func New(edgelbOptions edgelbmanager.EdgeLBManagerOptions, kubeconfig string) *F
 	var (
 		clusterName string
 	)
-	m, err := kubeClient.CoreV1().ConfigMaps(mkeClusterInfoConfigMapNamespace).Get(mkeClusterInfoConfigMapName, metav1.GetOptions{})
+	m, err := kubeClient.CoreV1().ConfigMaps(mkeClusterInfoConfigMapNamespace).Get(context.TODO(), mkeClusterInfoConfigMapName, metav1.GetOptions{})
 	if err != nil {
 		log.Fatalf("failed to read the \"%s/%s\" configmap: %v", mkeClusterInfoConfigMapNamespace, mkeClusterInfoConfigMapName, err)
 	}
@@ -92,9 +92,7 @@ func New(edgelbOptions edgelbmanager.EdgeLBManagerOptions, kubeconfig string) *F
 // These prerequisites include no pre-existing Kubernetes namespaces starting with "KubernetesNamespacePrefix", and no pre-existing EdgeLB pools.
 func (f *Framework) CheckTestPrerequisites() error {
 	// Check that there are no namespaces whose name starts with "KubernetesNamespacePrefix".
-	namespaces, err := f.KubeClient.CoreV1().Namespaces().List(metav1.ListOptions{
-		IncludeUninitialized: true,
-	})
+	namespaces, err := f.KubeClient.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
 	Expect(err).NotTo(HaveOccurred(), "failed to list namespaces")
 	for _, ns := range namespaces.Items {
 		Expect(ns.Name).NotTo(HavePrefix(KubernetesNamespacePrefix), "expected no pre-existing namespaces with prefix %q", KubernetesNamespacePrefix)
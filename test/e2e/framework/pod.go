@@ -1,6 +1,8 @@
 package framework
 
 import (
+	"context"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -32,7 +34,7 @@ func (f *Framework) CreatePod(namespace, name string, fn PodCustomizer) (*corev1
 	if fn != nil {
 		fn(pod)
 	}
-	return f.KubeClient.CoreV1().Pods(namespace).Create(pod)
+	return f.KubeClient.CoreV1().Pods(namespace).Create(context.TODO(), pod, metav1.CreateOptions{})
 }
 
 // CreateEchoPod creates an "echo" pod in the specified namespace with the provided name.
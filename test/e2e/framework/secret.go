@@ -1,6 +1,8 @@
 package framework
 
 import (
+	"context"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -20,5 +22,5 @@ func (f *Framework) CreateSecret(namespace, name string, fn SecretCustomizer) (*
 	if fn != nil {
 		fn(svc)
 	}
-	return f.KubeClient.CoreV1().Secrets(namespace).Create(svc)
+	return f.KubeClient.CoreV1().Secrets(namespace).Create(context.TODO(), svc, metav1.CreateOptions{})
 }
@@ -39,7 +39,7 @@ func (f *Framework) CreateService(namespace, name string, fn ServiceCustomizer)
 	if fn != nil {
 		fn(svc)
 	}
-	return f.KubeClient.CoreV1().Services(namespace).Create(svc)
+	return f.KubeClient.CoreV1().Services(namespace).Create(context.TODO(), svc, metav1.CreateOptions{})
 }
 
 // CreateServiceOfTypeLoadBalancer creates the Service resource of type LoadBalancer with the specified namespace and name in the Kubernetes API after running it through the specified customization function.
@@ -74,7 +74,7 @@ func (f *Framework) UpdateServiceEdgeLBPoolSpec(service *corev1.Service, fn Serv
 	}
 	fn(s)
 	_ = translatorapi.SetServiceEdgeLBPoolSpec(service, s)
-	return f.KubeClient.CoreV1().Services(service.Namespace).Update(service)
+	return f.KubeClient.CoreV1().Services(service.Namespace).Update(context.TODO(), service, metav1.UpdateOptions{})
 }
 
 // WaitUntilServiceCondition blocks until the specified condition function is verified, or until the provided context times out.
@@ -85,11 +85,11 @@ func (f *Framework) WaitUntilServiceCondition(ctx context.Context, service *core
 	lw := &cache.ListWatch{
 		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
 			options.FieldSelector = fs.String()
-			return f.KubeClient.CoreV1().Services(service.Namespace).List(options)
+			return f.KubeClient.CoreV1().Services(service.Namespace).List(context.TODO(), options)
 		},
 		WatchFunc: func(options metav1.ListOptions) (watchapi.Interface, error) {
 			options.FieldSelector = fs.String()
-			return f.KubeClient.CoreV1().Services(service.Namespace).Watch(options)
+			return f.KubeClient.CoreV1().Services(service.Namespace).Watch(context.TODO(), options)
 		},
 	}
 	// Watch for updates to the specified Service resource until fn is satisfied.
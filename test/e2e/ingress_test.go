@@ -18,10 +18,9 @@ import (
 	log "github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v2"
 	corev1 "k8s.io/api/core/v1"
-	extsv1beta1 "k8s.io/api/extensions/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/mesosphere/dklb/pkg/constants"
 	translatorapi "github.com/mesosphere/dklb/pkg/translator/api"
@@ -35,7 +34,7 @@ var _ = Describe("Ingress", func() {
 	Context("not annotated for provisioning by EdgeLB", func() {
 		It("is ignored by the admission webhook [HTTP] [Admission]", func() {
 			f.WithTemporaryNamespace(func(namespace *corev1.Namespace) {
-				_, err := f.CreateIngress(namespace.Name, "", func(ingress *extsv1beta1.Ingress) {
+				_, err := f.CreateIngress(namespace.Name, "", func(ingress *networkingv1.Ingress) {
 					// Use an invalid value for "kubernetes.dcos.io/dklb-config" (i.e. one for which ".size" is negative).
 					// The resulting Ingress resource would be be invalid, and hence an error would be reported, should it be annotated for provisioning by EdgeLB.
 					_ = translatorapi.SetIngressEdgeLBPoolSpec(ingress, &translatorapi.IngressEdgeLBPoolSpec{
@@ -46,9 +45,11 @@ var _ = Describe("Ingress", func() {
 					// Use a randomly-generated name for the Ingress resource.
 					ingress.GenerateName = fmt.Sprintf("%s-", namespace.Name)
 					// Define a default backend so that the Ingress resource is valid.
-					ingress.Spec.Backend = &extsv1beta1.IngressBackend{
-						ServiceName: "foo",
-						ServicePort: intstr.FromInt(80),
+					ingress.Spec.DefaultBackend = &networkingv1.IngressBackend{
+						Service: &networkingv1.IngressServiceBackend{
+							Name: "foo",
+							Port: networkingv1.ServiceBackendPort{Number: 80},
+						},
 					}
 				})
 				// Make sure that no error occurred (meaning the admission webhook has ignored the Ingress resource).
@@ -64,17 +65,19 @@ var _ = Describe("Ingress", func() {
 					err     error
 					objSpec translatorapi.IngressEdgeLBPoolSpec
 					rawSpec string
-					ing     *extsv1beta1.Ingress
+					ing     *networkingv1.Ingress
 				)
 
 				// Create an Ingress resource annotated for provisioning with EdgeLB but without the "kubernetes.dcos.io/dklb-config" annotation.
-				ing, err = f.CreateEdgeLBIngress(namespace.Name, "bare-ingress", func(ingress *extsv1beta1.Ingress) {
+				ing, err = f.CreateEdgeLBIngress(namespace.Name, "bare-ingress", func(ingress *networkingv1.Ingress) {
 					ingress.Annotations = map[string]string{
 						constants.DklbPaused: strconv.FormatBool(true),
 					}
-					ingress.Spec.Backend = &extsv1beta1.IngressBackend{
-						ServiceName: "foo",
-						ServicePort: intstr.FromString("bar"),
+					ingress.Spec.DefaultBackend = &networkingv1.IngressBackend{
+						Service: &networkingv1.IngressServiceBackend{
+							Name: "foo",
+							Port: networkingv1.ServiceBackendPort{Name: "bar"},
+						},
 					}
 				})
 				Expect(err).NotTo(HaveOccurred(), "failed to create test ingress")
@@ -106,14 +109,14 @@ var _ = Describe("Ingress", func() {
 				}{
 					{
 						description: "\"kubernetes.dcos.io/dklb-config\" cannot be parsed as yaml",
-						fn: func(ingress *extsv1beta1.Ingress) {
+						fn: func(ingress *networkingv1.Ingress) {
 							ingress.Annotations[constants.DklbConfigAnnotationKey] = "invalid: yaml: str"
 						},
 						expectedErrorMessageRegex: "failed to parse the value of \"kubernetes.dcos.io/dklb-config\" as a configuration object",
 					},
 					{
 						description: "\"kubernetes.dcos.io/dklb-config\" specifies an invalid edgelb pool name",
-						fn: func(ingress *extsv1beta1.Ingress) {
+						fn: func(ingress *networkingv1.Ingress) {
 							_ = translatorapi.SetIngressEdgeLBPoolSpec(ingress, &translatorapi.IngressEdgeLBPoolSpec{
 								BaseEdgeLBPoolSpec: translatorapi.BaseEdgeLBPoolSpec{
 									Name: pointers.NewString("__foo__"),
@@ -124,7 +127,7 @@ var _ = Describe("Ingress", func() {
 					},
 					{
 						description: "\"kubernetes.dcos.io/dklb-config\" specifies an invalid edgelb pool network",
-						fn: func(ingress *extsv1beta1.Ingress) {
+						fn: func(ingress *networkingv1.Ingress) {
 							_ = translatorapi.SetIngressEdgeLBPoolSpec(ingress, &translatorapi.IngressEdgeLBPoolSpec{
 								BaseEdgeLBPoolSpec: translatorapi.BaseEdgeLBPoolSpec{
 									Network: pointers.NewString("dcos"),
@@ -135,7 +138,7 @@ var _ = Describe("Ingress", func() {
 					},
 					{
 						description: "\"kubernetes.dcos.io/dklb-config\" specifies an invalid edgelb pool cpu request",
-						fn: func(ingress *extsv1beta1.Ingress) {
+						fn: func(ingress *networkingv1.Ingress) {
 							_ = translatorapi.SetIngressEdgeLBPoolSpec(ingress, &translatorapi.IngressEdgeLBPoolSpec{
 								BaseEdgeLBPoolSpec: translatorapi.BaseEdgeLBPoolSpec{
 									CPUs: pointers.NewFloat64(-0.1),
@@ -146,7 +149,7 @@ var _ = Describe("Ingress", func() {
 					},
 					{
 						description: "\"kubernetes.dcos.io/dklb-config\" specifies an invalid edgelb pool memory request",
-						fn: func(ingress *extsv1beta1.Ingress) {
+						fn: func(ingress *networkingv1.Ingress) {
 							_ = translatorapi.SetIngressEdgeLBPoolSpec(ingress, &translatorapi.IngressEdgeLBPoolSpec{
 								BaseEdgeLBPoolSpec: translatorapi.BaseEdgeLBPoolSpec{
 									Memory: pointers.NewInt32(-256),
@@ -157,7 +160,7 @@ var _ = Describe("Ingress", func() {
 					},
 					{
 						description: "\"kubernetes.dcos.io/dklb-config\" specifies an invalid edgelb pool size request",
-						fn: func(ingress *extsv1beta1.Ingress) {
+						fn: func(ingress *networkingv1.Ingress) {
 							_ = translatorapi.SetIngressEdgeLBPoolSpec(ingress, &translatorapi.IngressEdgeLBPoolSpec{
 								BaseEdgeLBPoolSpec: translatorapi.BaseEdgeLBPoolSpec{
 									Size: pointers.NewInt32(-1),
@@ -168,7 +171,7 @@ var _ = Describe("Ingress", func() {
 					},
 					{
 						description: "\"kubernetes.dcos.io/dklb-config\" specifies an invalid edgelb pool creation strategy",
-						fn: func(ingress *extsv1beta1.Ingress) {
+						fn: func(ingress *networkingv1.Ingress) {
 							strategy := translatorapi.EdgeLBPoolCreationStrategy("InvalidStrategy")
 							_ = translatorapi.SetIngressEdgeLBPoolSpec(ingress, &translatorapi.IngressEdgeLBPoolSpec{
 								BaseEdgeLBPoolSpec: translatorapi.BaseEdgeLBPoolSpec{
@@ -182,7 +185,7 @@ var _ = Describe("Ingress", func() {
 					},
 					{
 						description: "\"kubernetes.dcos.io/dklb-config\" specifies an invalid edgelb frontend HTTP port",
-						fn: func(ingress *extsv1beta1.Ingress) {
+						fn: func(ingress *networkingv1.Ingress) {
 							_ = translatorapi.SetIngressEdgeLBPoolSpec(ingress, &translatorapi.IngressEdgeLBPoolSpec{
 								Frontends: &translatorapi.IngressEdgeLBPoolFrontendsSpec{
 									HTTP: &translatorapi.IngressEdgeLBPoolHTTPFrontendSpec{
@@ -195,7 +198,7 @@ var _ = Describe("Ingress", func() {
 					},
 					{
 						description: "\"kubernetes.dcos.io/dklb-config\" specifies an invalid frontend http mode",
-						fn: func(ingress *extsv1beta1.Ingress) {
+						fn: func(ingress *networkingv1.Ingress) {
 							_ = translatorapi.SetIngressEdgeLBPoolSpec(ingress, &translatorapi.IngressEdgeLBPoolSpec{
 								Frontends: &translatorapi.IngressEdgeLBPoolFrontendsSpec{
 									HTTP: &translatorapi.IngressEdgeLBPoolHTTPFrontendSpec{
@@ -208,7 +211,7 @@ var _ = Describe("Ingress", func() {
 					},
 					{
 						description: "\"kubernetes.dcos.io/dklb-config\" specifies an invalid edgelb frontend HTTPS port",
-						fn: func(ingress *extsv1beta1.Ingress) {
+						fn: func(ingress *networkingv1.Ingress) {
 							_ = translatorapi.SetIngressEdgeLBPoolSpec(ingress, &translatorapi.IngressEdgeLBPoolSpec{
 								Frontends: &translatorapi.IngressEdgeLBPoolFrontendsSpec{
 									HTTPS: &translatorapi.IngressEdgeLBPoolHTTPSFrontendSpec{
@@ -235,11 +238,11 @@ var _ = Describe("Ingress", func() {
 			f.WithTemporaryNamespace(func(namespace *corev1.Namespace) {
 				var (
 					err            error
-					initialIngress *extsv1beta1.Ingress
+					initialIngress *networkingv1.Ingress
 				)
 
 				// Create an Ingress resource annotated for provisioning with EdgeLB and containing a valid EdgeLB pool specification.
-				initialIngress, err = f.CreateEdgeLBIngress(namespace.Name, "http-echo", func(ingress *extsv1beta1.Ingress) {
+				initialIngress, err = f.CreateEdgeLBIngress(namespace.Name, "http-echo", func(ingress *networkingv1.Ingress) {
 					_ = translatorapi.SetIngressEdgeLBPoolSpec(ingress, &translatorapi.IngressEdgeLBPoolSpec{
 						BaseEdgeLBPoolSpec: translatorapi.BaseEdgeLBPoolSpec{
 							Name: pointers.NewString(namespace.Name),
@@ -249,9 +252,11 @@ var _ = Describe("Ingress", func() {
 					// Request for translation to be paused so that no EdgeLB pool is actually created.
 					ingress.Annotations[constants.DklbPaused] = strconv.FormatBool(true)
 					// Define a default backend so that the Ingress resource can actually be created.
-					ingress.Spec.Backend = &extsv1beta1.IngressBackend{
-						ServiceName: "foo",
-						ServicePort: intstr.FromString("http"),
+					ingress.Spec.DefaultBackend = &networkingv1.IngressBackend{
+						Service: &networkingv1.IngressServiceBackend{
+							Name: "foo",
+							Port: networkingv1.ServiceBackendPort{Name: "http"},
+						},
 					}
 				})
 				Expect(err).NotTo(HaveOccurred(), "failed to create test ingress")
@@ -269,13 +274,6 @@ var _ = Describe("Ingress", func() {
 						},
 						expecterErrorMessageRegex: "the name of the target edgelb pool cannot be changed",
 					},
-					{
-						description: "update the target edgelb pool's role",
-						fn: func(spec *translatorapi.IngressEdgeLBPoolSpec) {
-							spec.Role = pointers.NewString("new-role")
-						},
-						expecterErrorMessageRegex: "the role of the target edgelb pool cannot be changed",
-					},
 					{
 						description: "update the target edgelb pool's virtual network",
 						fn: func(spec *translatorapi.IngressEdgeLBPoolSpec) {
@@ -310,7 +308,7 @@ var _ = Describe("Ingress", func() {
 					echoSvc4     *corev1.Service
 					err          error
 					httpEchoSpec translatorapi.IngressEdgeLBPoolSpec
-					ingress      *extsv1beta1.Ingress
+					ingress      *networkingv1.Ingress
 					pool         *models.V2Pool
 					publicIP     string
 				)
@@ -371,32 +369,38 @@ var _ = Describe("Ingress", func() {
 				// * Requests for the "http-echo-3.com" host and the "/bar(/.*)?" path are directed towards "http-echo-3".
 				// * Requests for the "http-echo-3.com" host and any other path are directed towards "http-echo-2".
 				// * Unmatched requests are directed towards "http-echo-1".
-				ingress, err = f.CreateEdgeLBIngress(namespace.Name, "http-echo", func(ingress *extsv1beta1.Ingress) {
+				ingress, err = f.CreateEdgeLBIngress(namespace.Name, "http-echo", func(ingress *networkingv1.Ingress) {
 					// Use "httpEchoSpec" as the specification for the target EdgeLB pool.
 					_ = translatorapi.SetIngressEdgeLBPoolSpec(ingress, &httpEchoSpec)
 					// Use "echoSvc1" as the default backend.
-					ingress.Spec.Backend = &extsv1beta1.IngressBackend{
-						ServiceName: echoSvc1.Name,
-						ServicePort: intstr.FromString(echoSvc1.Spec.Ports[0].Name),
+					ingress.Spec.DefaultBackend = &networkingv1.IngressBackend{
+						Service: &networkingv1.IngressServiceBackend{
+							Name: echoSvc1.Name,
+							Port: networkingv1.ServiceBackendPort{Name: echoSvc1.Spec.Ports[0].Name},
+						},
 					}
 					// Setup rules as described above.
-					ingress.Spec.Rules = []extsv1beta1.IngressRule{
+					ingress.Spec.Rules = []networkingv1.IngressRule{
 						{
 							Host: "http-echo-3.com",
-							IngressRuleValue: extsv1beta1.IngressRuleValue{
-								HTTP: &extsv1beta1.HTTPIngressRuleValue{
-									Paths: []extsv1beta1.HTTPIngressPath{
+							IngressRuleValue: networkingv1.IngressRuleValue{
+								HTTP: &networkingv1.HTTPIngressRuleValue{
+									Paths: []networkingv1.HTTPIngressPath{
 										{
-											Backend: extsv1beta1.IngressBackend{
-												ServiceName: echoSvc2.Name,
-												ServicePort: intstr.FromInt(int(echoSvc2.Spec.Ports[0].Port)),
+											Backend: networkingv1.IngressBackend{
+												Service: &networkingv1.IngressServiceBackend{
+													Name: echoSvc2.Name,
+													Port: networkingv1.ServiceBackendPort{Number: echoSvc2.Spec.Ports[0].Port},
+												},
 											},
 										},
 										{
 											Path: "/bar(/.*)?",
-											Backend: extsv1beta1.IngressBackend{
-												ServiceName: echoSvc3.Name,
-												ServicePort: intstr.FromString(echoSvc3.Spec.Ports[0].Name),
+											Backend: networkingv1.IngressBackend{
+												Service: &networkingv1.IngressServiceBackend{
+													Name: echoSvc3.Name,
+													Port: networkingv1.ServiceBackendPort{Name: echoSvc3.Spec.Ports[0].Name},
+												},
 											},
 										},
 									},
@@ -405,13 +409,15 @@ var _ = Describe("Ingress", func() {
 						},
 						{
 							Host: "http-echo-4.com",
-							IngressRuleValue: extsv1beta1.IngressRuleValue{
-								HTTP: &extsv1beta1.HTTPIngressRuleValue{
-									Paths: []extsv1beta1.HTTPIngressPath{
+							IngressRuleValue: networkingv1.IngressRuleValue{
+								HTTP: &networkingv1.HTTPIngressRuleValue{
+									Paths: []networkingv1.HTTPIngressPath{
 										{
-											Backend: extsv1beta1.IngressBackend{
-												ServiceName: echoSvc4.Name,
-												ServicePort: intstr.FromString(echoSvc4.Spec.Ports[0].Name),
+											Backend: networkingv1.IngressBackend{
+												Service: &networkingv1.IngressServiceBackend{
+													Name: echoSvc4.Name,
+													Port: networkingv1.ServiceBackendPort{Name: echoSvc4.Spec.Ports[0].Name},
+												},
 											},
 										},
 									},
@@ -508,7 +514,7 @@ var _ = Describe("Ingress", func() {
 				}
 
 				// Manually delete the Ingress resource now so that the target EdgeLB pool isn't possibly left dangling after namespace deletion.
-				err = f.KubeClient.ExtensionsV1beta1().Ingresses(ingress.Namespace).Delete(ingress.Name, metav1.NewDeleteOptions(0))
+				err = f.KubeClient.NetworkingV1().Ingresses(ingress.Namespace).Delete(context.TODO(), ingress.Name, *metav1.NewDeleteOptions(0))
 				Expect(err).NotTo(HaveOccurred(), "failed to delete ingress %q", kubernetes.Key(ingress))
 			})
 		})
@@ -528,7 +534,7 @@ var _ = Describe("Ingress", func() {
 					echoSvc4     *corev1.Service
 					err          error
 					httpEchoSpec translatorapi.IngressEdgeLBPoolSpec
-					ingress      *extsv1beta1.Ingress
+					ingress      *networkingv1.Ingress
 					pool         *models.V2Pool
 					publicIP     string
 				)
@@ -591,32 +597,38 @@ var _ = Describe("Ingress", func() {
 				// * Requests for the "http-echo-3.com" host and the "/bar(/.*)?" path are directed towards "http-echo-3".
 				// * Requests for the "http-echo-3.com" host and any other path are directed towards "http-echo-2".
 				// * Unmatched requests are directed towards "http-echo-1".
-				ingress, err = f.CreateEdgeLBIngress(namespace.Name, "http-echo", func(ingress *extsv1beta1.Ingress) {
+				ingress, err = f.CreateEdgeLBIngress(namespace.Name, "http-echo", func(ingress *networkingv1.Ingress) {
 					// Use "httpEchoSpec" as the specification for the target EdgeLB pool.
 					_ = translatorapi.SetIngressEdgeLBPoolSpec(ingress, &httpEchoSpec)
 					// Use "echoSvc1" as the default backend.
-					ingress.Spec.Backend = &extsv1beta1.IngressBackend{
-						ServiceName: echoSvc1.Name,
-						ServicePort: intstr.FromString(echoSvc1.Spec.Ports[0].Name),
+					ingress.Spec.DefaultBackend = &networkingv1.IngressBackend{
+						Service: &networkingv1.IngressServiceBackend{
+							Name: echoSvc1.Name,
+							Port: networkingv1.ServiceBackendPort{Name: echoSvc1.Spec.Ports[0].Name},
+						},
 					}
 					// Setup rules as described above.
-					ingress.Spec.Rules = []extsv1beta1.IngressRule{
+					ingress.Spec.Rules = []networkingv1.IngressRule{
 						{
 							Host: "http-echo-3.com",
-							IngressRuleValue: extsv1beta1.IngressRuleValue{
-								HTTP: &extsv1beta1.HTTPIngressRuleValue{
-									Paths: []extsv1beta1.HTTPIngressPath{
+							IngressRuleValue: networkingv1.IngressRuleValue{
+								HTTP: &networkingv1.HTTPIngressRuleValue{
+									Paths: []networkingv1.HTTPIngressPath{
 										{
-											Backend: extsv1beta1.IngressBackend{
-												ServiceName: echoSvc2.Name,
-												ServicePort: intstr.FromInt(int(echoSvc2.Spec.Ports[0].Port)),
+											Backend: networkingv1.IngressBackend{
+												Service: &networkingv1.IngressServiceBackend{
+													Name: echoSvc2.Name,
+													Port: networkingv1.ServiceBackendPort{Number: echoSvc2.Spec.Ports[0].Port},
+												},
 											},
 										},
 										{
 											Path: "/bar(/.*)?",
-											Backend: extsv1beta1.IngressBackend{
-												ServiceName: echoSvc3.Name,
-												ServicePort: intstr.FromString(echoSvc3.Spec.Ports[0].Name),
+											Backend: networkingv1.IngressBackend{
+												Service: &networkingv1.IngressServiceBackend{
+													Name: echoSvc3.Name,
+													Port: networkingv1.ServiceBackendPort{Name: echoSvc3.Spec.Ports[0].Name},
+												},
 											},
 										},
 									},
@@ -625,13 +637,15 @@ var _ = Describe("Ingress", func() {
 						},
 						{
 							Host: "http-echo-4.com",
-							IngressRuleValue: extsv1beta1.IngressRuleValue{
-								HTTP: &extsv1beta1.HTTPIngressRuleValue{
-									Paths: []extsv1beta1.HTTPIngressPath{
+							IngressRuleValue: networkingv1.IngressRuleValue{
+								HTTP: &networkingv1.HTTPIngressRuleValue{
+									Paths: []networkingv1.HTTPIngressPath{
 										{
-											Backend: extsv1beta1.IngressBackend{
-												ServiceName: echoSvc4.Name,
-												ServicePort: intstr.FromString(echoSvc4.Spec.Ports[0].Name),
+											Backend: networkingv1.IngressBackend{
+												Service: &networkingv1.IngressServiceBackend{
+													Name: echoSvc4.Name,
+													Port: networkingv1.ServiceBackendPort{Name: echoSvc4.Spec.Ports[0].Name},
+												},
 											},
 										},
 									},
@@ -728,7 +742,7 @@ var _ = Describe("Ingress", func() {
 				}
 
 				// Manually delete the Ingress resource now so that the target EdgeLB pool isn't possibly left dangling after namespace deletion.
-				err = f.KubeClient.ExtensionsV1beta1().Ingresses(ingress.Namespace).Delete(ingress.Name, metav1.NewDeleteOptions(0))
+				err = f.KubeClient.NetworkingV1().Ingresses(ingress.Namespace).Delete(context.TODO(), ingress.Name, *metav1.NewDeleteOptions(0))
 				Expect(err).NotTo(HaveOccurred(), "failed to delete ingress %q", kubernetes.Key(ingress))
 			})
 		})
@@ -743,9 +757,9 @@ var _ = Describe("Ingress", func() {
 						echoSvc1     *corev1.Service
 						echoSvc2     *corev1.Service
 						err          error
-						ingress1     *extsv1beta1.Ingress
+						ingress1     *networkingv1.Ingress
 						ingress1Spec translatorapi.IngressEdgeLBPoolSpec
-						ingress2     *extsv1beta1.Ingress
+						ingress2     *networkingv1.Ingress
 						ingress2Spec translatorapi.IngressEdgeLBPoolSpec
 						pool         *models.V2Pool
 						publicIP     string
@@ -773,18 +787,20 @@ var _ = Describe("Ingress", func() {
 
 					// Create an Ingress resource targeting the "http-echo-1" service above, annotating it to be provisioned by EdgeLB.
 					// The Ingress is configured to direct all traffic under "/foo" to "http-echo-1".
-					ingress1, err = f.CreateEdgeLBIngress(namespace1.Name, "http-echo", func(ingress *extsv1beta1.Ingress) {
+					ingress1, err = f.CreateEdgeLBIngress(namespace1.Name, "http-echo", func(ingress *networkingv1.Ingress) {
 						_ = translatorapi.SetIngressEdgeLBPoolSpec(ingress, &ingress1Spec)
-						ingress.Spec.Rules = []extsv1beta1.IngressRule{
+						ingress.Spec.Rules = []networkingv1.IngressRule{
 							{
-								IngressRuleValue: extsv1beta1.IngressRuleValue{
-									HTTP: &extsv1beta1.HTTPIngressRuleValue{
-										Paths: []extsv1beta1.HTTPIngressPath{
+								IngressRuleValue: networkingv1.IngressRuleValue{
+									HTTP: &networkingv1.HTTPIngressRuleValue{
+										Paths: []networkingv1.HTTPIngressPath{
 											{
 												Path: "/foo(/.*)?",
-												Backend: extsv1beta1.IngressBackend{
-													ServiceName: echoSvc1.Name,
-													ServicePort: intstr.FromInt(int(echoSvc1.Spec.Ports[0].Port)),
+												Backend: networkingv1.IngressBackend{
+													Service: &networkingv1.IngressServiceBackend{
+														Name: echoSvc1.Name,
+														Port: networkingv1.ServiceBackendPort{Number: echoSvc1.Spec.Ports[0].Port},
+													},
 												},
 											},
 										},
@@ -850,18 +866,20 @@ var _ = Describe("Ingress", func() {
 
 					// Create an Ingress resource targeting the "http-echo-2" service above, annotating it to be provisioned by EdgeLB.
 					// The Ingress is configured to direct all traffic under "/foo" to "http-echo-2".
-					ingress2, err = f.CreateEdgeLBIngress(namespace2.Name, "http-echo", func(ingress *extsv1beta1.Ingress) {
+					ingress2, err = f.CreateEdgeLBIngress(namespace2.Name, "http-echo", func(ingress *networkingv1.Ingress) {
 						_ = translatorapi.SetIngressEdgeLBPoolSpec(ingress, &ingress2Spec)
-						ingress.Spec.Rules = []extsv1beta1.IngressRule{
+						ingress.Spec.Rules = []networkingv1.IngressRule{
 							{
-								IngressRuleValue: extsv1beta1.IngressRuleValue{
-									HTTP: &extsv1beta1.HTTPIngressRuleValue{
-										Paths: []extsv1beta1.HTTPIngressPath{
+								IngressRuleValue: networkingv1.IngressRuleValue{
+									HTTP: &networkingv1.HTTPIngressRuleValue{
+										Paths: []networkingv1.HTTPIngressPath{
 											{
 												Path: "/foo(/.*)?",
-												Backend: extsv1beta1.IngressBackend{
-													ServiceName: echoSvc2.Name,
-													ServicePort: intstr.FromInt(int(echoSvc2.Spec.Ports[0].Port)),
+												Backend: networkingv1.IngressBackend{
+													Service: &networkingv1.IngressServiceBackend{
+														Name: echoSvc2.Name,
+														Port: networkingv1.ServiceBackendPort{Number: echoSvc2.Spec.Ports[0].Port},
+													},
 												},
 											},
 										},
@@ -933,9 +951,9 @@ var _ = Describe("Ingress", func() {
 					Expect(len(pools)).To(Equal(1), "expecting a single edgelb pool, found %d", len(pools))
 
 					// Manually delete the Ingress resources now in order to prevent the EdgeLB pool from being re-created during namespace deletion.
-					err = f.KubeClient.ExtensionsV1beta1().Ingresses(ingress1.Namespace).Delete(ingress1.Name, metav1.NewDeleteOptions(0))
+					err = f.KubeClient.NetworkingV1().Ingresses(ingress1.Namespace).Delete(context.TODO(), ingress1.Name, *metav1.NewDeleteOptions(0))
 					Expect(err).NotTo(HaveOccurred(), "failed to delete ingress %q", kubernetes.Key(ingress1))
-					err = f.KubeClient.ExtensionsV1beta1().Ingresses(ingress2.Namespace).Delete(ingress1.Name, metav1.NewDeleteOptions(0))
+					err = f.KubeClient.NetworkingV1().Ingresses(ingress2.Namespace).Delete(context.TODO(), ingress1.Name, *metav1.NewDeleteOptions(0))
 					Expect(err).NotTo(HaveOccurred(), "failed to delete ingress %q", kubernetes.Key(ingress2))
 				})
 			})
@@ -948,8 +966,8 @@ var _ = Describe("Ingress", func() {
 					echoPod1 *corev1.Pod
 					echoPod2 *corev1.Pod
 					err      error
-					ingress1 *extsv1beta1.Ingress
-					ingress2 *extsv1beta1.Ingress
+					ingress1 *networkingv1.Ingress
+					ingress2 *networkingv1.Ingress
 					publicIP string
 				)
 
@@ -991,7 +1009,7 @@ spec:
 
 				defer func() {
 					// Manually delete the Ingress resources now in order to prevent the EdgeLB pool from being re-created during namespace deletion.
-					err := f.KubeClient.ExtensionsV1beta1().Ingresses(ingress1.Namespace).Delete(ingress1.Name, metav1.NewDeleteOptions(0))
+					err := f.KubeClient.NetworkingV1().Ingresses(ingress1.Namespace).Delete(context.TODO(), ingress1.Name, *metav1.NewDeleteOptions(0))
 					Expect(err).NotTo(HaveOccurred(), "failed to delete ingress %q", kubernetes.Key(ingress1))
 				}()
 
@@ -1037,7 +1055,7 @@ spec:
 				Expect(err).NotTo(HaveOccurred(), "failed to create ingress")
 
 				defer func() {
-					err := f.KubeClient.ExtensionsV1beta1().Ingresses(ingress2.Namespace).Delete(ingress2.Name, metav1.NewDeleteOptions(0))
+					err := f.KubeClient.NetworkingV1().Ingresses(ingress2.Namespace).Delete(context.TODO(), ingress2.Name, *metav1.NewDeleteOptions(0))
 					Expect(err).NotTo(HaveOccurred(), "failed to delete ingress %q", kubernetes.Key(ingress2))
 				}()
 
@@ -1132,7 +1150,7 @@ spec:
 					echoSpec translatorapi.IngressEdgeLBPoolSpec
 					echoSvc1 *corev1.Service
 					err      error
-					ingress  *extsv1beta1.Ingress
+					ingress  *networkingv1.Ingress
 					publicIP string
 				)
 
@@ -1155,25 +1173,29 @@ spec:
 				}
 
 				// Create an Ingress resource targeting the service above, annotating it to be provisioned by EdgeLB.
-				ingress, err = f.CreateEdgeLBIngress(namespace.Name, "http-echo", func(ingress *extsv1beta1.Ingress) {
+				ingress, err = f.CreateEdgeLBIngress(namespace.Name, "http-echo", func(ingress *networkingv1.Ingress) {
 					_ = translatorapi.SetIngressEdgeLBPoolSpec(ingress, &echoSpec)
-					ingress.Spec.Rules = []extsv1beta1.IngressRule{
+					ingress.Spec.Rules = []networkingv1.IngressRule{
 						{
-							IngressRuleValue: extsv1beta1.IngressRuleValue{
-								HTTP: &extsv1beta1.HTTPIngressRuleValue{
-									Paths: []extsv1beta1.HTTPIngressPath{
+							IngressRuleValue: networkingv1.IngressRuleValue{
+								HTTP: &networkingv1.HTTPIngressRuleValue{
+									Paths: []networkingv1.HTTPIngressPath{
 										{
 											Path: "/foo(/.*)?",
-											Backend: extsv1beta1.IngressBackend{
-												ServiceName: echoSvc1.Name,
-												ServicePort: intstr.FromInt(int(echoSvc1.Spec.Ports[0].Port)),
+											Backend: networkingv1.IngressBackend{
+												Service: &networkingv1.IngressServiceBackend{
+													Name: echoSvc1.Name,
+													Port: networkingv1.ServiceBackendPort{Number: echoSvc1.Spec.Ports[0].Port},
+												},
 											},
 										},
 										{
 											Path: "/bar(/.*)?",
-											Backend: extsv1beta1.IngressBackend{
-												ServiceName: "missing-service",
-												ServicePort: intstr.FromString("http"),
+											Backend: networkingv1.IngressBackend{
+												Service: &networkingv1.IngressServiceBackend{
+													Name: "missing-service",
+													Port: networkingv1.ServiceBackendPort{Name: "http"},
+												},
 											},
 										},
 									},
@@ -1254,7 +1276,7 @@ spec:
 				}
 
 				// Manually delete the Ingress resource now in order to prevent the EdgeLB pool from being re-created during namespace deletion.
-				err = f.KubeClient.ExtensionsV1beta1().Ingresses(ingress.Namespace).Delete(ingress.Name, metav1.NewDeleteOptions(0))
+				err = f.KubeClient.NetworkingV1().Ingresses(ingress.Namespace).Delete(context.TODO(), ingress.Name, *metav1.NewDeleteOptions(0))
 				Expect(err).NotTo(HaveOccurred(), "failed to delete ingress %q", kubernetes.Key(ingress))
 			})
 		})
@@ -1264,7 +1286,7 @@ spec:
 
 			var (
 				err      error
-				ingress  *extsv1beta1.Ingress
+				ingress  *networkingv1.Ingress
 				publicIP string
 				spec     translatorapi.IngressEdgeLBPoolSpec
 				svc      *corev1.Service
@@ -1272,11 +1294,11 @@ spec:
 
 			// Read the "default/kubernetes" service so we can later update it in order to set its ".spec.type" to "NodePort".
 			// This way we will have a suitable, TLS-enabled backend without having to deploy pods and managing TLS ourselves.
-			svc, err = f.KubeClient.CoreV1().Services(corev1.NamespaceDefault).Get("kubernetes", metav1.GetOptions{})
+			svc, err = f.KubeClient.CoreV1().Services(corev1.NamespaceDefault).Get(context.TODO(), "kubernetes", metav1.GetOptions{})
 			Expect(err).NotTo(HaveOccurred())
 			// Update "default/kubernetes" in order to set its ".spec.type" to "NodePort".
 			svc.Spec.Type = corev1.ServiceTypeNodePort
-			svc, err = f.KubeClient.CoreV1().Services(svc.Namespace).Update(svc)
+			svc, err = f.KubeClient.CoreV1().Services(svc.Namespace).Update(context.TODO(), svc, metav1.UpdateOptions{})
 			Expect(err).NotTo(HaveOccurred())
 
 			// Create an object holding the target EdgeLB pool's specification for the "http-echo" Ingress.
@@ -1287,18 +1309,20 @@ spec:
 			}
 
 			// Create an Ingress resource targeting the "default/kubernetes" service, annotating it to be provisioned by EdgeLB.
-			ingress, err = f.CreateEdgeLBIngress(svc.Namespace, svc.Name, func(ingress *extsv1beta1.Ingress) {
+			ingress, err = f.CreateEdgeLBIngress(svc.Namespace, svc.Name, func(ingress *networkingv1.Ingress) {
 				_ = translatorapi.SetIngressEdgeLBPoolSpec(ingress, &spec)
-				ingress.Spec.Rules = []extsv1beta1.IngressRule{
+				ingress.Spec.Rules = []networkingv1.IngressRule{
 					{
-						IngressRuleValue: extsv1beta1.IngressRuleValue{
-							HTTP: &extsv1beta1.HTTPIngressRuleValue{
-								Paths: []extsv1beta1.HTTPIngressPath{
+						IngressRuleValue: networkingv1.IngressRuleValue{
+							HTTP: &networkingv1.HTTPIngressRuleValue{
+								Paths: []networkingv1.HTTPIngressPath{
 									{
 										Path: "/kubernetes",
-										Backend: extsv1beta1.IngressBackend{
-											ServiceName: svc.Name,
-											ServicePort: intstr.FromInt(int(svc.Spec.Ports[0].Port)),
+										Backend: networkingv1.IngressBackend{
+											Service: &networkingv1.IngressServiceBackend{
+												Name: svc.Name,
+												Port: networkingv1.ServiceBackendPort{Number: svc.Spec.Ports[0].Port},
+											},
 										},
 									},
 								},
@@ -1310,7 +1334,7 @@ spec:
 			Expect(err).NotTo(HaveOccurred(), "failed to create test ingress")
 			// Manually delete the Ingress resource regardless of test result.
 			defer func() {
-				err = f.KubeClient.ExtensionsV1beta1().Ingresses(ingress.Namespace).Delete(ingress.Name, metav1.NewDeleteOptions(0))
+				err = f.KubeClient.NetworkingV1().Ingresses(ingress.Namespace).Delete(context.TODO(), ingress.Name, *metav1.NewDeleteOptions(0))
 				Expect(err).NotTo(HaveOccurred(), "failed to delete ingress %q", kubernetes.Key(ingress))
 			}()
 
@@ -1353,11 +1377,11 @@ spec:
 			Expect(status).Should(Or(Equal(http.StatusUnauthorized), Equal(http.StatusForbidden)))
 
 			// Undo the change made to the "default/kubernetes" service.
-			svc, err = f.KubeClient.CoreV1().Services(svc.Namespace).Get(svc.Name, metav1.GetOptions{})
+			svc, err = f.KubeClient.CoreV1().Services(svc.Namespace).Get(context.TODO(), svc.Name, metav1.GetOptions{})
 			Expect(err).NotTo(HaveOccurred())
 			svc.Spec.Type = corev1.ServiceTypeClusterIP
 			svc.Spec.Ports[0].NodePort = 0
-			svc, err = f.KubeClient.CoreV1().Services(svc.Namespace).Update(svc)
+			svc, err = f.KubeClient.CoreV1().Services(svc.Namespace).Update(context.TODO(), svc, metav1.UpdateOptions{})
 			Expect(err).NotTo(HaveOccurred())
 		})
 
@@ -1368,20 +1392,22 @@ spec:
 					err     error
 					objSpec translatorapi.IngressEdgeLBPoolSpec
 					rawSpec string
-					ing     *extsv1beta1.Ingress
+					ing     *networkingv1.Ingress
 				)
 
 				// Create an Ingress resource annotated for provisioning with EdgeLB but without the "kubernetes.dcos.io/dklb-config" annotation.
-				ing, err = f.CreateEdgeLBIngress(namespace.Name, "tls-ingress", func(ingress *extsv1beta1.Ingress) {
+				ing, err = f.CreateEdgeLBIngress(namespace.Name, "tls-ingress", func(ingress *networkingv1.Ingress) {
 					ingress.Annotations = map[string]string{
 						constants.DklbPaused: strconv.FormatBool(true),
 					}
-					ingress.Spec.TLS = []extsv1beta1.IngressTLS{
+					ingress.Spec.TLS = []networkingv1.IngressTLS{
 						{SecretName: "foo"},
 					}
-					ingress.Spec.Backend = &extsv1beta1.IngressBackend{
-						ServiceName: "foo",
-						ServicePort: intstr.FromString("bar"),
+					ingress.Spec.DefaultBackend = &networkingv1.IngressBackend{
+						Service: &networkingv1.IngressServiceBackend{
+							Name: "foo",
+							Port: networkingv1.ServiceBackendPort{Name: "bar"},
+						},
 					}
 				})
 				Expect(err).NotTo(HaveOccurred(), "failed to create test ingress")
@@ -1412,11 +1438,11 @@ spec:
 					err     error
 					objSpec translatorapi.IngressEdgeLBPoolSpec
 					rawSpec string
-					ing     *extsv1beta1.Ingress
+					ing     *networkingv1.Ingress
 				)
 
 				// Create an Ingress resource annotated for provisioning with EdgeLB but without the "kubernetes.dcos.io/dklb-config" annotation.
-				ing, err = f.CreateEdgeLBIngress(namespace.Name, "tls-ingress", func(ingress *extsv1beta1.Ingress) {
+				ing, err = f.CreateEdgeLBIngress(namespace.Name, "tls-ingress", func(ingress *networkingv1.Ingress) {
 					_ = translatorapi.SetIngressEdgeLBPoolSpec(ingress, &translatorapi.IngressEdgeLBPoolSpec{
 						Frontends: &translatorapi.IngressEdgeLBPoolFrontendsSpec{
 							HTTP: &translatorapi.IngressEdgeLBPoolHTTPFrontendSpec{
@@ -1426,12 +1452,14 @@ spec:
 					})
 					ingress.Annotations[constants.DklbPaused] = strconv.FormatBool(true)
 
-					ingress.Spec.TLS = []extsv1beta1.IngressTLS{
+					ingress.Spec.TLS = []networkingv1.IngressTLS{
 						{SecretName: "foo"},
 					}
-					ingress.Spec.Backend = &extsv1beta1.IngressBackend{
-						ServiceName: "foo",
-						ServicePort: intstr.FromString("bar"),
+					ingress.Spec.DefaultBackend = &networkingv1.IngressBackend{
+						Service: &networkingv1.IngressServiceBackend{
+							Name: "foo",
+							Port: networkingv1.ServiceBackendPort{Name: "bar"},
+						},
 					}
 				})
 				Expect(err).NotTo(HaveOccurred(), "failed to create test ingress")
@@ -1463,7 +1491,7 @@ spec:
 					echoSvc1     *corev1.Service
 					err          error
 					httpEchoSpec translatorapi.IngressEdgeLBPoolSpec
-					ingress      *extsv1beta1.Ingress
+					ingress      *networkingv1.Ingress
 					pool         *models.V2Pool
 					publicIP     string
 				)
@@ -1556,32 +1584,36 @@ hBHIkVstxMO9c4ZCA60QbQ==
 				// Create an Ingress resource targeting the services above, annotating it to be provisioned by EdgeLB.
 				// The following rules are defined on the Ingress resource:
 				// * Requests for the "foo.bar.com" host are directed towards "http-echo-1".
-				ingress, err = f.CreateEdgeLBIngress(namespace.Name, "http-echo", func(ingress *extsv1beta1.Ingress) {
+				ingress, err = f.CreateEdgeLBIngress(namespace.Name, "http-echo", func(ingress *networkingv1.Ingress) {
 					// Use "httpEchoSpec" as the specification for the target EdgeLB pool.
 					_ = translatorapi.SetIngressEdgeLBPoolSpec(ingress, &httpEchoSpec)
 					// Use "echoSvc1" as the default backend.
-					ingress.Spec.Backend = &extsv1beta1.IngressBackend{
-						ServiceName: echoSvc1.Name,
-						ServicePort: intstr.FromString(echoSvc1.Spec.Ports[0].Name),
+					ingress.Spec.DefaultBackend = &networkingv1.IngressBackend{
+						Service: &networkingv1.IngressServiceBackend{
+							Name: echoSvc1.Name,
+							Port: networkingv1.ServiceBackendPort{Name: echoSvc1.Spec.Ports[0].Name},
+						},
 					}
 					// setup secret with TLS
-					ingress.Spec.TLS = []extsv1beta1.IngressTLS{
+					ingress.Spec.TLS = []networkingv1.IngressTLS{
 						{
 							Hosts:      []string{host},
 							SecretName: "test-secret",
 						},
 					}
 					// Setup rules as described above.
-					ingress.Spec.Rules = []extsv1beta1.IngressRule{
+					ingress.Spec.Rules = []networkingv1.IngressRule{
 						{
 							Host: host,
-							IngressRuleValue: extsv1beta1.IngressRuleValue{
-								HTTP: &extsv1beta1.HTTPIngressRuleValue{
-									Paths: []extsv1beta1.HTTPIngressPath{
+							IngressRuleValue: networkingv1.IngressRuleValue{
+								HTTP: &networkingv1.HTTPIngressRuleValue{
+									Paths: []networkingv1.HTTPIngressPath{
 										{
-											Backend: extsv1beta1.IngressBackend{
-												ServiceName: echoSvc1.Name,
-												ServicePort: intstr.FromInt(int(echoSvc1.Spec.Ports[0].Port)),
+											Backend: networkingv1.IngressBackend{
+												Service: &networkingv1.IngressServiceBackend{
+													Name: echoSvc1.Name,
+													Port: networkingv1.ServiceBackendPort{Number: echoSvc1.Spec.Ports[0].Port},
+												},
 											},
 										},
 									},
@@ -1593,7 +1625,7 @@ hBHIkVstxMO9c4ZCA60QbQ==
 				Expect(err).NotTo(HaveOccurred(), "failed to create ingress")
 				// Manually delete the Ingress resource regardless of test result.
 				defer func() {
-					err = f.KubeClient.ExtensionsV1beta1().Ingresses(ingress.Namespace).Delete(ingress.Name, metav1.NewDeleteOptions(0))
+					err = f.KubeClient.NetworkingV1().Ingresses(ingress.Namespace).Delete(context.TODO(), ingress.Name, *metav1.NewDeleteOptions(0))
 					Expect(err).NotTo(HaveOccurred(), "failed to delete ingress %q", kubernetes.Key(ingress))
 					f.WaitEdgeLBPoolDelete(pool)
 				}()
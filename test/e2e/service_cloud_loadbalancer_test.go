@@ -97,7 +97,7 @@ var _ = Describe("Service", func() {
 				Expect(err).NotTo(HaveOccurred(), "failed to create test service")
 				defer func() {
 					// Manually delete the Service resource now in order to prevent the EdgeLB pool from being re-created during namespace deletion.
-					err = f.KubeClient.CoreV1().Services(redisSvc.Namespace).Delete(redisSvc.Name, metav1.NewDeleteOptions(0))
+					err = f.KubeClient.CoreV1().Services(redisSvc.Namespace).Delete(context.TODO(), redisSvc.Name, *metav1.NewDeleteOptions(0))
 					Expect(err).NotTo(HaveOccurred(), "failed to delete service %q", kubernetes.Key(redisSvc))
 				}()
 
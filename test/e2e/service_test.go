@@ -307,13 +307,6 @@ var _ = Describe("Service", func() {
 						},
 						expecterErrorMessageRegex: "the name of the target edgelb pool cannot be changed",
 					},
-					{
-						description: "update the target edgelb pool's role",
-						fn: func(spec *translatorapi.ServiceEdgeLBPoolSpec) {
-							spec.Role = pointers.NewString("new-role")
-						},
-						expecterErrorMessageRegex: "the role of the target edgelb pool cannot be changed",
-					},
 					{
 						description: "update the target edgelb pool's virtual network",
 						fn: func(spec *translatorapi.ServiceEdgeLBPoolSpec) {
@@ -444,7 +437,7 @@ var _ = Describe("Service", func() {
 				Expect(err).NotTo(HaveOccurred(), "timed out while attempting to ping redis")
 
 				// Manually delete the Service resource now so that the target EdgeLB pool isn't possibly left dangling after namespace deletion.
-				err = f.KubeClient.CoreV1().Services(redisSvc.Namespace).Delete(redisSvc.Name, metav1.NewDeleteOptions(0))
+				err = f.KubeClient.CoreV1().Services(redisSvc.Namespace).Delete(context.TODO(), redisSvc.Name, *metav1.NewDeleteOptions(0))
 				Expect(err).NotTo(HaveOccurred(), "failed to delete service %q", kubernetes.Key(redisSvc))
 			})
 		})
@@ -641,9 +634,9 @@ var _ = Describe("Service", func() {
 					Expect(len(pools)).To(Equal(1), "expecting a single edgelb pool, found %d", len(pools))
 
 					// Manually delete the two services now in order to prevent the EdgeLB pool from being re-created during namespace deletion.
-					err = f.KubeClient.CoreV1().Services(redisSvc.Namespace).Delete(redisSvc.Name, metav1.NewDeleteOptions(0))
+					err = f.KubeClient.CoreV1().Services(redisSvc.Namespace).Delete(context.TODO(), redisSvc.Name, *metav1.NewDeleteOptions(0))
 					Expect(err).NotTo(HaveOccurred(), "failed to delete service %q", kubernetes.Key(redisSvc))
-					err = f.KubeClient.CoreV1().Services(mongoSvc.Namespace).Delete(mongoSvc.Name, metav1.NewDeleteOptions(0))
+					err = f.KubeClient.CoreV1().Services(mongoSvc.Namespace).Delete(context.TODO(), mongoSvc.Name, *metav1.NewDeleteOptions(0))
 					Expect(err).NotTo(HaveOccurred(), "failed to delete service %q", kubernetes.Key(mongoSvc))
 				})
 			})
@@ -755,10 +748,10 @@ var _ = Describe("Service", func() {
 				// This is required in order to prevent https://jira.mesosphere.com/browse/DCOS-46508 from happening due to the controller's resync period elapsing in the meantime.
 				// NOTE: redisSvc must be re-read from the Kubernetes API in order to avoid "409 CONFLICT" errors when updating the resource.
 				// These errors would otherwise happen, as the "service controller" has updated the resource's status in the meantime (in order to report the IPs).
-				redisSvc, err = f.KubeClient.CoreV1().Services(redisSvc.Namespace).Get(redisSvc.Name, metav1.GetOptions{})
+				redisSvc, err = f.KubeClient.CoreV1().Services(redisSvc.Namespace).Get(context.TODO(), redisSvc.Name, metav1.GetOptions{})
 				Expect(err).NotTo(HaveOccurred(), "failed to read an updated version of the service resource")
 				redisSvc.Annotations[constants.DklbPaused] = "1"
-				redisSvc, err = f.KubeClient.CoreV1().Services(redisSvc.Namespace).Update(redisSvc)
+				redisSvc, err = f.KubeClient.CoreV1().Services(redisSvc.Namespace).Update(context.TODO(), redisSvc, metav1.UpdateOptions{})
 				Expect(err).NotTo(HaveOccurred(), "failed to set the type of %q to %q", kubernetes.Key(redisSvc), corev1.ServiceTypeNodePort)
 
 				// Delete the pool.
@@ -782,7 +775,7 @@ var _ = Describe("Service", func() {
 				// Resume translation of the Service resource.
 				// This will cause the service controller to restart processing this resource, and re-create the pool since we're using the default pool re-creation strategy.
 				redisSvc.Annotations[constants.DklbPaused] = "0"
-				redisSvc, err = f.KubeClient.CoreV1().Services(redisSvc.Namespace).Update(redisSvc)
+				redisSvc, err = f.KubeClient.CoreV1().Services(redisSvc.Namespace).Update(context.TODO(), redisSvc, metav1.UpdateOptions{})
 				Expect(err).NotTo(HaveOccurred(), "failed to set the type of %q to %q", kubernetes.Key(redisSvc), corev1.ServiceTypeNodePort)
 
 				// Wait for the pool to be re-provisioned, making Redis reachable again.
@@ -807,7 +800,7 @@ var _ = Describe("Service", func() {
 				Expect(err).NotTo(HaveOccurred(), "timed out while attempting to ping redis")
 
 				// Manually delete the two services now in order to prevent the EdgeLB pool from being re-created during namespace deletion.
-				err = f.KubeClient.CoreV1().Services(redisSvc.Namespace).Delete(redisSvc.Name, metav1.NewDeleteOptions(0))
+				err = f.KubeClient.CoreV1().Services(redisSvc.Namespace).Delete(context.TODO(), redisSvc.Name, *metav1.NewDeleteOptions(0))
 				Expect(err).NotTo(HaveOccurred(), "failed to delete service %q", kubernetes.Key(redisSvc))
 			})
 		})
@@ -17,14 +17,18 @@ func NewFakeSharedInformerFactory(res ...runtime.Object) kubeinformers.SharedInf
 	// Create a shared informer factory that uses the fake Kubernetes clientset.
 	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(fakeClient, 30*time.Second)
 	// Start all the required informers.
-	ingressInformer := kubeInformerFactory.Extensions().V1beta1().Ingresses()
+	endpointsInformer := kubeInformerFactory.Core().V1().Endpoints()
+	ingressInformer := kubeInformerFactory.Networking().V1().Ingresses()
+	ingressClassInformer := kubeInformerFactory.Networking().V1().IngressClasses()
 	serviceInformer := kubeInformerFactory.Core().V1().Services()
 	secretInformer := kubeInformerFactory.Core().V1().Secrets()
+	go endpointsInformer.Informer().Run(wait.NeverStop)
 	go ingressInformer.Informer().Run(wait.NeverStop)
+	go ingressClassInformer.Informer().Run(wait.NeverStop)
 	go serviceInformer.Informer().Run(wait.NeverStop)
 	go secretInformer.Informer().Run(wait.NeverStop)
 	// Wait for the caches to be synced.
-	if !kubecache.WaitForCacheSync(wait.NeverStop, ingressInformer.Informer().HasSynced, serviceInformer.Informer().HasSynced, secretInformer.Informer().HasSynced) {
+	if !kubecache.WaitForCacheSync(wait.NeverStop, endpointsInformer.Informer().HasSynced, ingressInformer.Informer().HasSynced, ingressClassInformer.Informer().HasSynced, serviceInformer.Informer().HasSynced, secretInformer.Informer().HasSynced) {
 		panic("failed to wait for caches to be synced")
 	}
 	// Return the shared informer factory.